@@ -5,6 +5,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/viper"
@@ -18,29 +19,46 @@ const (
 )
 
 // WaConfig is the main struct that stores the welsh academy configuration.
-// It contains fields for database configuration, mode, logging level, and JWT key.
+// It contains fields for database configuration, mode, logging level, JWT key, the intervals of the background jobs and the storage of the uploaded images.
 type WaConfig struct {
-	DbCfg    *DbConfig
-	Mode     string
-	LogLevel string
-	JwtKey   string
+	DbCfg                  *DbConfig
+	Mode                   string
+	LogLevel               string
+	JwtKey                 string
+	RecommendationInterval time.Duration
+	TrendingInterval       time.Duration
+	MediaDir               string // the directory of the uploaded images
+	MediaUrl               string // the URL the uploaded images are served at, a path is served on the host of the API
+	MaxImageSize           int64  // the maximum size of an uploaded image in bytes
 }
 
 // Global variable to store the welsh academy configuration.
 var waConfig *WaConfig
 
 // GetWaConfig function retrieves the WaConfig from Viper configuration.
-// It sets up Viper to use the environment prefix "wa" and retrieves values for database configuration, mode, logging level, and JWT key.
+// It sets up Viper to use the environment prefix "wa" and retrieves values for database configuration, mode, logging level, JWT key,
+// and the intervals of the background jobs, the recommendations are computed every hour and the trending recipes every 15 minutes by default.
+// The uploaded images are stored in the uploads directory served at /uploads by default and they are limited to 5 MiB.
 // If WaConfig has not been initialized, it initializes it and returns it.
 func GetWaConfig() *WaConfig {
 	if waConfig == nil {
 		viper.SetEnvPrefix("wa")
 		viper.AutomaticEnv()
+		viper.SetDefault("recommendationInterval", time.Hour)
+		viper.SetDefault("trendingInterval", 15*time.Minute)
+		viper.SetDefault("mediaDir", "uploads")
+		viper.SetDefault("mediaUrl", "/uploads")
+		viper.SetDefault("maxImageSize", 5<<20)
 		waConfig = &WaConfig{
-			DbCfg:    DbConfigFromViper(),
-			Mode:     viper.GetString("mode"),
-			LogLevel: viper.GetString("logLevel"),
-			JwtKey:   viper.GetString("JWTKEY"),
+			DbCfg:                  DbConfigFromViper(),
+			Mode:                   viper.GetString("mode"),
+			LogLevel:               viper.GetString("logLevel"),
+			JwtKey:                 viper.GetString("JWTKEY"),
+			RecommendationInterval: viper.GetDuration("recommendationInterval"),
+			TrendingInterval:       viper.GetDuration("trendingInterval"),
+			MediaDir:               viper.GetString("mediaDir"),
+			MediaUrl:               viper.GetString("mediaUrl"),
+			MaxImageSize:           viper.GetInt64("maxImageSize"),
 		}
 	}
 	return waConfig
@@ -4,6 +4,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -41,3 +42,12 @@ func DbConfigFromViper() *DbConfig {
 func (cfg *DbConfig) BuildDsn() string {
 	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s timezone=%s", cfg.Host, cfg.User, cfg.Password, cfg.DbName, cfg.Port, cfg.SslMode, cfg.TimeZone)
 }
+
+// Location returns the time zone of the database used to interpret the dates, UTC when the time zone is unknown
+func (cfg *DbConfig) Location() *time.Location {
+	location, err := time.LoadLocation(cfg.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
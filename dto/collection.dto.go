@@ -0,0 +1,98 @@
+// Package dto defines data transfer objects (DTOs) used for communicating between the input and output of an API
+package dto
+
+import (
+	"time"
+
+	"github.com/clementb49/welsh_academy/models"
+)
+
+// CollectionReqBody represents the request body for creating or updating a recipe collection, a collection is private unless Public is true
+type CollectionReqBody struct {
+	Title       string `json:"title" xml:"title" binding:"required,max=100"`
+	Description string `json:"description" xml:"description" binding:"max=2000"`
+	Public      bool   `json:"public" xml:"public"`
+}
+
+// ConvertToModel converts a CollectionReqBody to the Collection model of the user
+func (c *CollectionReqBody) ConvertToModel(userId uint) *models.Collection {
+	return &models.Collection{
+		Title:       c.Title,
+		Description: c.Description,
+		UserID:      userId,
+		Public:      c.Public,
+	}
+}
+
+// CollectionRecipesOrderReqBody represents the request body for reordering the recipes of a collection
+type CollectionRecipesOrderReqBody struct {
+	RecipesId []uint `json:"recipes_id" xml:"recipes_id" binding:"required,min=1"`
+}
+
+// CollectionRecipePathUri represents the URI parameters for a recipe of a collection
+type CollectionRecipePathUri struct {
+	ID       uint `uri:"id" binding:"required,min=0"`       // ID represents the unique identifier of the collection
+	RecipeID uint `uri:"recipeId" binding:"required,min=0"` // RecipeID represents the unique identifier of the recipe
+}
+
+// CollectionTokenPathUri represents the URI parameter for the secret token of a collection share link
+type CollectionTokenPathUri struct {
+	Token string `uri:"token" binding:"required,len=64,hexadecimal"`
+}
+
+// PublicCollectionQuery represents the query parameters used to browse the public collections,
+// only the collections of the user are listed when UserId is provided
+type PublicCollectionQuery struct {
+	CommonQueryPage
+	UserId uint `form:"user_id" json:"user_id,omitempty" xml:"user_id,omitempty"`
+}
+
+// CollectionRecipeResBody represents a recipe of a collection with its position and the date it was added
+type CollectionRecipeResBody struct {
+	RecipeLinkResBody
+	Position uint      `json:"position" xml:"position"`
+	AddedAt  time.Time `json:"added_at" xml:"added_at"`
+}
+
+// CollectionResBody represents the response body for a recipe collection, only the first name of its owner is given.
+// The recipes deleted since they were added aren't listed.
+type CollectionResBody struct {
+	CommonResBody
+	Title       string                     `json:"title" xml:"title"`
+	Description string                     `json:"description" xml:"description"`
+	Public      bool                       `json:"public" xml:"public"`
+	Shared      bool                       `json:"shared" xml:"shared"` // the collection can be read with a share link
+	AuthorId    uint                       `json:"author_id" xml:"author_id"`
+	AuthorName  string                     `json:"author_name" xml:"author_name"`
+	RecipeCount int                        `json:"recipe_count" xml:"recipe_count"`
+	Recipes     []*CollectionRecipeResBody `json:"recipes" xml:"recipe"`
+}
+
+// ConvertFromModel converts a Collection model to a CollectionResBody
+func (c *CollectionResBody) ConvertFromModel(model *models.Collection) {
+	c.convertFromGormModel(&model.Model)
+	c.Title = model.Title
+	c.Description = model.Description
+	c.Public = model.Public
+	c.Shared = model.ShareToken != nil
+	c.AuthorId = model.UserID
+	if model.User != nil {
+		c.AuthorName = model.User.FirstName
+	}
+	c.Recipes = make([]*CollectionRecipeResBody, 0, len(model.Recipes))
+	for _, v := range model.Recipes {
+		if v.Recipe == nil {
+			continue
+		}
+		dto := &CollectionRecipeResBody{Position: v.Position, AddedAt: v.CreatedAt}
+		dto.ConvertFromModel(v.Recipe)
+		c.Recipes = append(c.Recipes, dto)
+	}
+	c.RecipeCount = len(c.Recipes)
+}
+
+// CollectionShareResBody represents the share link of a collection, everyone knowing the link can read the collection
+type CollectionShareResBody struct {
+	Token string `json:"token" xml:"token"`
+	Url   string `json:"url" xml:"url"`
+}
@@ -0,0 +1,63 @@
+// Package dto defines data transfer objects (DTOs) used for communicating between the input and output of an API
+package dto
+
+import "github.com/clementb49/welsh_academy/models"
+
+// CommentPathUri represents the URI parameters for a comment of a recipe
+type CommentPathUri struct {
+	ID        uint `uri:"id" binding:"required,min=0"`        // ID represents the unique identifier of the recipe
+	CommentID uint `uri:"commentId" binding:"required,min=0"` // CommentID represents the unique identifier of the comment
+}
+
+// CommentReqBody represents the request body for writing a comment or a reply to the ParentId comment.
+// The ParentId is only used on creation, a comment can't be moved to another thread.
+type CommentReqBody struct {
+	Text     string `json:"text" xml:"text" binding:"required,max=5000"`
+	ParentId *uint  `json:"parent_id" xml:"parent_id" binding:"omitempty,min=1"`
+}
+
+// ConvertToModel converts a CommentReqBody to a Comment model of the user on the recipe
+func (c *CommentReqBody) ConvertToModel(userId, recipeId uint) *models.Comment {
+	return &models.Comment{
+		RecipeID: recipeId,
+		UserID:   userId,
+		ParentID: c.ParentId,
+		Text:     c.Text,
+	}
+}
+
+// CommentResBody represents the response body for a comment with its replies.
+// The text and the author of a deleted comment are hidden, it's only returned to keep its replies in the thread.
+type CommentResBody struct {
+	CommonResBody
+	RecipeId   uint              `json:"recipe_id" xml:"recipe_id"`
+	ParentId   *uint             `json:"parent_id,omitempty" xml:"parent_id,omitempty"`
+	AuthorId   uint              `json:"author_id,omitempty" xml:"author_id,omitempty"`
+	AuthorName string            `json:"author_name,omitempty" xml:"author_name,omitempty"`
+	Text       string            `json:"text" xml:"text"`
+	Pinned     bool              `json:"pinned" xml:"pinned"`
+	Deleted    bool              `json:"deleted" xml:"deleted"`
+	Replies    []*CommentResBody `json:"replies" xml:"reply"`
+}
+
+// ConvertFromModel converts a Comment model to a CommentResBody with its replies
+func (c *CommentResBody) ConvertFromModel(model *models.Comment) {
+	c.convertFromGormModel(&model.Model)
+	c.RecipeId = model.RecipeID
+	c.ParentId = model.ParentID
+	c.Pinned = model.Pinned
+	c.Deleted = model.DeletedAt.Valid
+	if !c.Deleted {
+		c.AuthorId = model.UserID
+		if model.User != nil {
+			c.AuthorName = model.User.FirstName
+		}
+		c.Text = model.Text
+	}
+	c.Replies = make([]*CommentResBody, len(model.Replies))
+	for i, v := range model.Replies {
+		dto := &CommentResBody{}
+		dto.ConvertFromModel(v)
+		c.Replies[i] = dto
+	}
+}
@@ -0,0 +1,91 @@
+// Package dto defines data transfer objects (DTOs) used for communicating between the input and output of an API
+package dto
+
+import (
+	"mime/multipart"
+	"time"
+
+	"github.com/clementb49/welsh_academy/media"
+	"github.com/clementb49/welsh_academy/models"
+)
+
+// ImageReqBody represents the multipart form used to upload the image of an ingredient
+type ImageReqBody struct {
+	Image *multipart.FileHeader `form:"image" binding:"required"` // the JPEG, PNG or GIF file
+}
+
+// RecipeImageReqBody represents the multipart form used to upload a photo of a recipe,
+// the first photo of a recipe is its cover even when it's not asked
+type RecipeImageReqBody struct {
+	Image *multipart.FileHeader `form:"image" binding:"required"` // the JPEG, PNG or GIF file
+	Cover bool                  `form:"cover"`                    // the photo becomes the cover of the recipe
+}
+
+// RecipeImagePathUri represents the URI parameters used to identify a photo of a recipe
+type RecipeImagePathUri struct {
+	ID      uint `uri:"id" binding:"required,min=0"`      // ID represents the unique identifier of the recipe
+	ImageID uint `uri:"imageId" binding:"required,min=0"` // ImageID represents the unique identifier of the photo
+}
+
+// ImageResBody represents the response body for an uploaded image with the URLs of its files
+type ImageResBody struct {
+	Url          string `json:"url" xml:"url"`
+	ThumbnailUrl string `json:"thumbnail_url" xml:"thumbnail_url"`
+	ContentType  string `json:"content_type" xml:"content_type"`
+	Width        int    `json:"width" xml:"width"`
+	Height       int    `json:"height" xml:"height"`
+	Size         int64  `json:"size" xml:"size"` // the size of the uploaded file in bytes
+	// the paths of the files in the media storage, the services build the URLs from them
+	path          string
+	thumbnailPath string
+}
+
+// ConvertFromModel converts an Image model to an ImageResBody, the URLs are set with SetUrls
+func (i *ImageResBody) ConvertFromModel(model *models.Image) {
+	i.path = model.Path
+	i.thumbnailPath = model.ThumbnailPath
+	i.ContentType = model.ContentType
+	i.Width = model.Width
+	i.Height = model.Height
+	i.Size = model.Size
+}
+
+// SetUrls sets the public URLs of the files of the image kept in the storage
+func (i *ImageResBody) SetUrls(storage media.Storage) {
+	i.Url = storage.Url(i.path)
+	i.ThumbnailUrl = storage.Url(i.thumbnailPath)
+}
+
+// RecipeImageResBody represents the response body for a photo of a recipe
+type RecipeImageResBody struct {
+	ID uint `json:"id" xml:"id"`
+	ImageResBody
+	Cover     bool      `json:"cover" xml:"cover"`
+	CreatedAt time.Time `json:"created_at" xml:"created_at"`
+}
+
+// ConvertFromModel converts a RecipeImage model to a RecipeImageResBody
+func (r *RecipeImageResBody) ConvertFromModel(model *models.RecipeImage) {
+	r.ID = model.ID
+	r.ImageResBody.ConvertFromModel(&model.Image)
+	r.Cover = model.Cover
+	r.CreatedAt = model.CreatedAt
+}
+
+// ConvertRecipeImagesFromModel converts the photos of a recipe to their response body
+func ConvertRecipeImagesFromModel(images []*models.RecipeImage) []*RecipeImageResBody {
+	imagesRes := make([]*RecipeImageResBody, len(images))
+	for i, v := range images {
+		dto := &RecipeImageResBody{}
+		dto.ConvertFromModel(v)
+		imagesRes[i] = dto
+	}
+	return imagesRes
+}
+
+// SetRecipeImagesUrls sets the public URLs of the files of the photos kept in the storage
+func SetRecipeImagesUrls(images []*RecipeImageResBody, storage media.Storage) {
+	for _, v := range images {
+		v.SetUrls(storage)
+	}
+}
@@ -0,0 +1,125 @@
+// Package dto defines data transfer objects (DTOs) used for communicating between the input and output of an API
+package dto
+
+import (
+	"mime/multipart"
+
+	"github.com/clementb49/welsh_academy/importer"
+	"github.com/clementb49/welsh_academy/models"
+)
+
+// RecipeImportQuery represents the query parameters of a recipe import preview.
+// The difficulty isn't published with the schema.org recipes so it's given with the import.
+type RecipeImportQuery struct {
+	Difficulty uint8 `form:"difficulty" json:"difficulty" xml:"difficulty" binding:"omitempty,min=1,max=5"`
+}
+
+// RecipeImportReqBody represents the multipart form used to upload the document of the imported recipe,
+// the document can also be sent as the raw request body
+type RecipeImportReqBody struct {
+	File *multipart.FileHeader `form:"file" binding:"required"` // the schema.org Recipe JSON-LD or an HTML page embedding it
+}
+
+// ImportedIngredientReqBody represents an ingredient line of a reviewed import,
+// the ingredient is found by its name or created with the recipe when IngredientId is 0
+type ImportedIngredientReqBody struct {
+	IngredientId uint    `json:"ingredient_id" xml:"ingredient_id"`
+	Name         string  `json:"name" xml:"name" binding:"required_without=IngredientId,max=100"`
+	Quantity     float64 `json:"quantity" xml:"quantity" binding:"min=0"`
+	Unit         string  `json:"unit" xml:"unit" binding:"max=20"`
+	Note         string  `json:"note" xml:"note" binding:"max=200"`
+	Optional     bool    `json:"optional" xml:"optional"`
+}
+
+// RecipeImportCommitReqBody represents the request body committing a reviewed import,
+// the preview of the import can be corrected and sent as is
+type RecipeImportCommitReqBody struct {
+	Title       string                      `json:"title" xml:"title" binding:"required,max=200"`
+	Description string                      `json:"description" xml:"description"`
+	Difficulty  uint8                       `json:"difficulty" xml:"difficulty" binding:"required,min=1,max=5"`
+	Servings    uint                        `json:"servings" xml:"servings" binding:"omitempty,min=1,max=1000"`
+	Ingredients []ImportedIngredientReqBody `json:"ingredients" xml:"ingredient" binding:"required,min=1,dive"`
+	Steps       []string                    `json:"steps" xml:"step" binding:"dive,required"`
+}
+
+// ConvertToModel converts a RecipeImportCommitReqBody to the Recipe model of the user,
+// the lines without ingredient ID are left to the service which finds or creates their ingredient
+func (r *RecipeImportCommitReqBody) ConvertToModel(userId uint) *models.Recipe {
+	recipe := &models.Recipe{
+		Title:       r.Title,
+		Description: r.Description,
+		Difficulty:  r.Difficulty,
+		Servings:    r.Servings,
+		AuthorID:    uint64(userId),
+		Ingredients: make([]*models.RecipeIngredient, len(r.Ingredients)),
+		Steps:       make([]*models.RecipeStep, len(r.Steps)),
+	}
+	for i, line := range r.Ingredients {
+		recipe.Ingredients[i] = &models.RecipeIngredient{
+			IngredientID: line.IngredientId,
+			Position:     uint(i),
+			Quantity:     line.Quantity,
+			Unit:         line.Unit,
+			Note:         line.Note,
+			Optional:     line.Optional,
+		}
+	}
+	for i, text := range r.Steps {
+		recipe.Steps[i] = &models.RecipeStep{Position: uint(i + 1), Text: text}
+	}
+	return recipe
+}
+
+// ImportedIngredientResBody represents an ingredient line of an imported recipe with the ingredient it's matched with
+type ImportedIngredientResBody struct {
+	Line         string  `json:"line" xml:"line"` // the line as published
+	Name         string  `json:"name" xml:"name"`
+	IngredientId uint    `json:"ingredient_id" xml:"ingredient_id"` // 0 when the ingredient is created with the recipe
+	New          bool    `json:"new" xml:"new"`                     // the ingredient is unknown and created with the recipe
+	Quantity     float64 `json:"quantity" xml:"quantity"`
+	Unit         string  `json:"unit" xml:"unit"`
+	Note         string  `json:"note" xml:"note"`
+	Optional     bool    `json:"optional" xml:"optional"`
+}
+
+// RecipeImportResBody represents the preview of a recipe import, it has the fields of a RecipeImportCommitReqBody
+// so it can be corrected and sent back to commit the import
+type RecipeImportResBody struct {
+	Title         string                       `json:"title" xml:"title"`
+	TitleConflict bool                         `json:"title_conflict" xml:"title_conflict"` // a recipe already has the title, it must be changed before the commit
+	Description   string                       `json:"description" xml:"description"`
+	Difficulty    uint8                        `json:"difficulty" xml:"difficulty"`
+	Servings      uint                         `json:"servings" xml:"servings"`
+	Ingredients   []*ImportedIngredientResBody `json:"ingredients" xml:"ingredient"`
+	Steps         []string                     `json:"steps" xml:"step"`
+	Warnings      []string                     `json:"warnings" xml:"warning"` // the parts of the document which couldn't be imported as is
+}
+
+// ConvertFromModel converts the Recipe model built from the imported recipe to a RecipeImportResBody,
+// the ingredient lines of the model are in the order of the imported lines
+func (r *RecipeImportResBody) ConvertFromModel(model *models.Recipe, imported *importer.Recipe) {
+	r.Title = model.Title
+	r.Description = model.Description
+	r.Difficulty = model.Difficulty
+	r.Servings = model.Servings
+	r.Ingredients = make([]*ImportedIngredientResBody, len(model.Ingredients))
+	for i, line := range model.Ingredients {
+		r.Ingredients[i] = &ImportedIngredientResBody{
+			Line:         imported.Ingredients[i].Text,
+			IngredientId: line.IngredientID,
+			New:          line.IngredientID == 0,
+			Quantity:     line.Quantity,
+			Unit:         line.Unit,
+			Note:         line.Note,
+			Optional:     line.Optional,
+		}
+		if line.Ingredient != nil {
+			r.Ingredients[i].Name = line.Ingredient.Name
+		}
+	}
+	r.Steps = make([]string, len(model.Steps))
+	for i, step := range model.Steps {
+		r.Steps[i] = step.Text
+	}
+	r.Warnings = imported.Warnings
+}
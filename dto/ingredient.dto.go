@@ -1,26 +1,52 @@
 // Package dto defines data transfer objects (DTOs) used for communicating between the input and output of an API
 package dto
 
-import "github.com/clementb49/welsh_academy/models"
+import (
+	"github.com/clementb49/welsh_academy/media"
+	"github.com/clementb49/welsh_academy/models"
+)
 
 // IngredientReqBody defines the request body for creating or updating an Ingredient
 type IngredientReqBody struct {
-	Name string `json:"name" xml:"name" binding:"required"`
-	Type string `json:"type" xml:"type" binding:"required"`
+	Name    string  `json:"name" xml:"name" binding:"required"`
+	Type    string  `json:"type" xml:"type" binding:"required"`
+	Density float64 `json:"density,omitempty" xml:"density,omitempty" binding:"min=0"` // Density in g/ml used to convert the volumes to weights
+	// PieceWeight is the weight in g of one piece used to weigh the counted quantities
+	PieceWeight float64 `json:"piece_weight,omitempty" xml:"piece_weight,omitempty" binding:"min=0"`
+	// Allergens lists the regulated allergens contained by the ingredient
+	Allergens []string `json:"allergens" xml:"allergen" binding:"omitempty,dive,oneof=celery gluten crustaceans eggs fish lupin milk molluscs mustard nuts peanuts sesame soya sulphites"`
 }
 
 // ConvertToModel converts an IngredientReqBody to a models.Ingredient
 func (i *IngredientReqBody) ConvertToModel() *models.Ingredient {
 	return &models.Ingredient{
-		Name: i.Name,
-		Type: i.Type,
+		Name:        i.Name,
+		Type:        i.Type,
+		Density:     i.Density,
+		PieceWeight: i.PieceWeight,
+		Allergens:   models.AllergensFromNames(i.Allergens),
 	}
 }
 
+// ApplyToModel replaces the fields of the Ingredient model with the values of the IngredientReqBody
+func (i *IngredientReqBody) ApplyToModel(model *models.Ingredient) {
+	model.Name = i.Name
+	model.Type = i.Type
+	model.Density = i.Density
+	model.PieceWeight = i.PieceWeight
+	model.Allergens = models.AllergensFromNames(i.Allergens)
+}
+
 // IngredientResBody defines the response body for getting an Ingredient
 type IngredientResBody struct {
 	CommonResBody
 	IngredientReqBody
+	// Nutrition is the nutrition facts for 100 g, it's omitted when they are unknown
+	Nutrition *NutritionResBody `json:"nutrition,omitempty" xml:"nutrition,omitempty"`
+	// Price is the latest reference price, it's omitted when the ingredient isn't priced
+	Price *IngredientPriceResBody `json:"price,omitempty" xml:"price,omitempty"`
+	// Image is the image of the ingredient, it's omitted when the ingredient has none
+	Image *ImageResBody `json:"image,omitempty" xml:"image,omitempty"`
 }
 
 // ConvertFromModel converts a models.Ingredient to an IngredientResBody
@@ -28,4 +54,26 @@ func (i *IngredientResBody) ConvertFromModel(model *models.Ingredient) {
 	i.convertFromGormModel(&model.Model)
 	i.Name = model.Name
 	i.Type = model.Type
+	i.Density = model.Density
+	i.PieceWeight = model.PieceWeight
+	i.Allergens = model.Allergens.Names()
+	if model.Nutrition != nil {
+		i.Nutrition = &NutritionResBody{}
+		i.Nutrition.ConvertFromModel(model.Nutrition)
+	}
+	if model.Price != nil {
+		i.Price = &IngredientPriceResBody{}
+		i.Price.ConvertFromModel(model.Price)
+	}
+	if model.Image != nil {
+		i.Image = &ImageResBody{}
+		i.Image.ConvertFromModel(&model.Image.Image)
+	}
+}
+
+// SetImageUrls sets the public URLs of the image of the ingredient kept in the storage
+func (i *IngredientResBody) SetImageUrls(storage media.Storage) {
+	if i.Image != nil {
+		i.Image.SetUrls(storage)
+	}
 }
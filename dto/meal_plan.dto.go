@@ -0,0 +1,88 @@
+// Package dto defines data transfer objects (DTOs) used for communicating between the input and output of an API
+package dto
+
+import (
+	"time"
+
+	"github.com/clementb49/welsh_academy/models"
+)
+
+// MealPlanReqBody represents the request body for planning a recipe for a meal or updating a meal plan.
+// The date is a day formatted as 2006-01-02, Servings defaults to the servings of the recipe.
+type MealPlanReqBody struct {
+	RecipeId uint   `json:"recipe_id" xml:"recipe_id" binding:"required"`
+	Date     string `json:"date" xml:"date" binding:"required,datetime=2006-01-02"`
+	Slot     string `json:"slot" xml:"slot" binding:"required,oneof=breakfast lunch dinner"`
+	Servings uint   `json:"servings" xml:"servings" binding:"omitempty,min=1,max=1000"`
+	Note     string `json:"note" xml:"note" binding:"max=200"`
+}
+
+// ConvertToModel converts a MealPlanReqBody to a MealPlan model of the user, the date is a day of the location
+func (m *MealPlanReqBody) ConvertToModel(userId uint, location *time.Location) (*models.MealPlan, error) {
+	model := &models.MealPlan{UserID: userId}
+	err := m.ApplyToModel(model, location)
+	if err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// ApplyToModel replaces the fields of the MealPlan model with the values of the MealPlanReqBody
+func (m *MealPlanReqBody) ApplyToModel(model *models.MealPlan, location *time.Location) error {
+	date, err := time.ParseInLocation(models.DateFormat, m.Date, location)
+	if err != nil {
+		return err
+	}
+	model.RecipeID = m.RecipeId
+	model.Date = date
+	model.Slot = m.Slot
+	model.Servings = m.Servings
+	model.Note = m.Note
+	return nil
+}
+
+// MealPlanQuery represents the query parameters used when getting the meal plans of a period,
+// the dates are included and the period is the coming week when they are omitted
+type MealPlanQuery struct {
+	From string `form:"from" json:"from" xml:"from" binding:"omitempty,datetime=2006-01-02"`
+	To   string `form:"to" json:"to" xml:"to" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// MealPlanResBody represents the response body for a meal plan
+type MealPlanResBody struct {
+	CommonResBody
+	Date        string `json:"date" xml:"date"`
+	Slot        string `json:"slot" xml:"slot"`
+	RecipeId    uint   `json:"recipe_id" xml:"recipe_id"`
+	RecipeTitle string `json:"recipe_title" xml:"recipe_title"`
+	Servings    uint   `json:"servings" xml:"servings"`
+	Note        string `json:"note,omitempty" xml:"note,omitempty"`
+}
+
+// ConvertFromModel converts a MealPlan model to a MealPlanResBody, the servings of the recipe are used when the plan doesn't specify them
+func (m *MealPlanResBody) ConvertFromModel(model *models.MealPlan) {
+	m.convertFromGormModel(&model.Model)
+	m.Date = model.Date.Format(models.DateFormat)
+	m.Slot = model.Slot
+	m.RecipeId = model.RecipeID
+	m.Servings = model.Servings
+	if model.Recipe != nil {
+		m.RecipeTitle = model.Recipe.Title
+		if m.Servings == 0 {
+			m.Servings = model.Recipe.Servings
+		}
+	}
+	m.Note = model.Note
+}
+
+// MealPlanCalendarResBody represents the response body for the calendar feed of the meal plans.
+// The feed URL contains the secret token, anyone knowing it can read the meal plans of the user.
+type MealPlanCalendarResBody struct {
+	Token string `json:"token" xml:"token"`
+	Url   string `json:"url" xml:"url"`
+}
+
+// CalendarTokenPathUri represents the URI parameter for the secret token of a calendar feed
+type CalendarTokenPathUri struct {
+	Token string `uri:"token" binding:"required,len=64,hexadecimal"`
+}
@@ -0,0 +1,67 @@
+// Package dto defines data transfer objects (DTOs) used for communicating between the input and output of an API
+package dto
+
+import "github.com/clementb49/welsh_academy/models"
+
+// NutritionReqBody represents the request body for the nutrition facts of an ingredient for 100 g
+type NutritionReqBody struct {
+	EnergyKcal   float64 `json:"energy_kcal" xml:"energy_kcal" binding:"min=0,max=900"`
+	Fat          float64 `json:"fat" xml:"fat" binding:"min=0,max=100"`
+	Saturates    float64 `json:"saturates" xml:"saturates" binding:"min=0,max=100"`
+	Carbohydrate float64 `json:"carbohydrate" xml:"carbohydrate" binding:"min=0,max=100"`
+	Sugars       float64 `json:"sugars" xml:"sugars" binding:"min=0,max=100"`
+	Protein      float64 `json:"protein" xml:"protein" binding:"min=0,max=100"`
+	Salt         float64 `json:"salt" xml:"salt" binding:"min=0,max=100"`
+}
+
+// ConvertToModel converts a NutritionReqBody to the IngredientNutrition model of the ingredient
+func (n *NutritionReqBody) ConvertToModel(ingredientId uint) *models.IngredientNutrition {
+	return &models.IngredientNutrition{
+		IngredientID: ingredientId,
+		EnergyKcal:   n.EnergyKcal,
+		Fat:          n.Fat,
+		Saturates:    n.Saturates,
+		Carbohydrate: n.Carbohydrate,
+		Sugars:       n.Sugars,
+		Protein:      n.Protein,
+		Salt:         n.Salt,
+	}
+}
+
+// NutritionResBody represents the nutrition facts of an ingredient for 100 g or of a recipe,
+// the energy is in kcal and the other values in g
+type NutritionResBody struct {
+	EnergyKcal   float64 `json:"energy_kcal" xml:"energy_kcal"`
+	Fat          float64 `json:"fat" xml:"fat"`
+	Saturates    float64 `json:"saturates" xml:"saturates"`
+	Carbohydrate float64 `json:"carbohydrate" xml:"carbohydrate"`
+	Sugars       float64 `json:"sugars" xml:"sugars"`
+	Protein      float64 `json:"protein" xml:"protein"`
+	Salt         float64 `json:"salt" xml:"salt"`
+}
+
+// ConvertFromModel converts an IngredientNutrition model to a NutritionResBody
+func (n *NutritionResBody) ConvertFromModel(model *models.IngredientNutrition) {
+	n.EnergyKcal = model.EnergyKcal
+	n.Fat = model.Fat
+	n.Saturates = model.Saturates
+	n.Carbohydrate = model.Carbohydrate
+	n.Sugars = model.Sugars
+	n.Protein = model.Protein
+	n.Salt = model.Salt
+}
+
+// RecipeNutritionResBody represents the nutrition facts of a recipe computed by the services from its ingredient quantities.
+// The totals are incomplete when the nutrition facts or the weight of some ingredients are unknown, these ingredients are listed.
+type RecipeNutritionResBody struct {
+	Total              *NutritionResBody        `json:"total" xml:"total"`
+	PerServing         *NutritionResBody        `json:"per_serving" xml:"per_serving"`
+	Complete           bool                     `json:"complete" xml:"complete"`
+	MissingIngredients []*IngredientLinkResBody `json:"missing_ingredients" xml:"missing_ingredient"`
+}
+
+// IngredientLinkResBody represents an ingredient referenced by another resource
+type IngredientLinkResBody struct {
+	ID   uint   `json:"id" xml:"id"`
+	Name string `json:"name" xml:"name"`
+}
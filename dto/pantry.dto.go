@@ -0,0 +1,25 @@
+// Package dto defines data transfer objects (DTOs) used for communicating between the input and output of an API
+package dto
+
+// PantryReqBody represents the request body for replacing the content of the pantry of a user, an empty list clears the pantry
+type PantryReqBody struct {
+	IngredientIds []uint `json:"ingredient_ids" xml:"ingredient_id" binding:"max=1000"`
+}
+
+// CookableQuery represents the query parameters used to find the recipes the user can cook with their pantry.
+// When MaxMissing is provided, the recipes missing more required ingredients are left out.
+// When AllowSubstitutes is true, an ingredient is available when one of its substitutes is in the pantry.
+type CookableQuery struct {
+	CommonQueryPage
+	MaxMissing       *uint `form:"max_missing" json:"max_missing,omitempty" xml:"max_missing,omitempty" binding:"omitempty,max=100"`
+	AllowSubstitutes bool  `form:"allow_substitutes" json:"allow_substitutes,omitempty" xml:"allow_substitutes,omitempty"`
+}
+
+// CookableRecipeResBody represents a recipe using the ingredients of the pantry with the required ingredients missing from it,
+// the optional ingredients aren't counted
+type CookableRecipeResBody struct {
+	RecipeResBody
+	IngredientCount    int                      `json:"ingredient_count" xml:"ingredient_count"`
+	AvailableCount     int                      `json:"available_count" xml:"available_count"`
+	MissingIngredients []*IngredientLinkResBody `json:"missing_ingredients" xml:"missing_ingredient"`
+}
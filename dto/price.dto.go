@@ -0,0 +1,100 @@
+// Package dto defines data transfer objects (DTOs) used for communicating between the input and output of an API
+package dto
+
+import (
+	"time"
+
+	"github.com/clementb49/welsh_academy/models"
+)
+
+// IngredientPriceReqBody represents the request body for recording a price of an ingredient.
+// The price is paid for the quantity of the unit, the quantity defaults to 1 and an empty unit is a number of pieces.
+// The price is recorded today when the date is omitted, the dates are days of the location of the application.
+type IngredientPriceReqBody struct {
+	Price      float64 `json:"price" xml:"price" binding:"required,gt=0"`
+	Currency   string  `json:"currency" xml:"currency" binding:"required,iso4217"`
+	Quantity   float64 `json:"quantity" xml:"quantity" binding:"omitempty,gt=0"`
+	Unit       string  `json:"unit" xml:"unit" binding:"max=20"`
+	RecordedAt string  `json:"recorded_at" xml:"recorded_at" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// ConvertToModel converts an IngredientPriceReqBody to the IngredientPrice model of the ingredient recorded by the user,
+// the date is a day of the location
+func (p *IngredientPriceReqBody) ConvertToModel(ingredientId uint, userId uint, location *time.Location) (*models.IngredientPrice, error) {
+	now := time.Now().In(location)
+	recordedAt := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	if p.RecordedAt != "" {
+		date, err := time.ParseInLocation(models.DateFormat, p.RecordedAt, location)
+		if err != nil {
+			return nil, err
+		}
+		recordedAt = date
+	}
+	quantity := p.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+	return &models.IngredientPrice{
+		IngredientID: ingredientId,
+		Price:        p.Price,
+		Currency:     p.Currency,
+		Quantity:     quantity,
+		Unit:         p.Unit,
+		RecordedAt:   recordedAt,
+		CreatorID:    userId,
+	}, nil
+}
+
+// IngredientPricePathUri represents the URI parameters for a price of an ingredient
+type IngredientPricePathUri struct {
+	ID      uint `uri:"id" binding:"required,min=0"`      // ID represents the unique identifier of the ingredient
+	PriceID uint `uri:"priceId" binding:"required,min=0"` // PriceID represents the unique identifier of the price
+}
+
+// IngredientPriceResBody represents a recorded price of an ingredient
+type IngredientPriceResBody struct {
+	ID           uint    `json:"id" xml:"id"`
+	IngredientId uint    `json:"ingredient_id" xml:"ingredient_id"`
+	Price        float64 `json:"price" xml:"price"`
+	Currency     string  `json:"currency" xml:"currency"`
+	Quantity     float64 `json:"quantity" xml:"quantity"`
+	Unit         string  `json:"unit" xml:"unit"`
+	RecordedAt   string  `json:"recorded_at" xml:"recorded_at"`
+	CreatorId    uint    `json:"creator_id" xml:"creator_id"`
+}
+
+// ConvertFromModel converts an IngredientPrice model to an IngredientPriceResBody
+func (p *IngredientPriceResBody) ConvertFromModel(model *models.IngredientPrice) {
+	p.ID = model.ID
+	p.IngredientId = model.IngredientID
+	p.Price = model.Price
+	p.Currency = model.Currency
+	p.Quantity = model.Quantity
+	p.Unit = model.Unit
+	p.RecordedAt = model.RecordedAt.Format(models.DateFormat)
+	p.CreatorId = model.CreatorID
+}
+
+// RecipeCostResBody represents the estimated cost of a recipe computed by the services from the reference prices of its ingredients.
+// The cost is given in the currency used by most of the priced ingredients, there is no exchange rate so the ingredients
+// priced in another currency are reported missing like the ones without price or which can't be converted to the priced unit.
+type RecipeCostResBody struct {
+	Currency           string                   `json:"currency" xml:"currency"`
+	Total              float64                  `json:"total" xml:"total"`
+	PerServing         float64                  `json:"per_serving" xml:"per_serving"`
+	Complete           bool                     `json:"complete" xml:"complete"`
+	MissingIngredients []*IngredientLinkResBody `json:"missing_ingredients" xml:"missing_ingredient"`
+}
+
+// RecipeCostPointResBody represents the estimated cost of a recipe at a date
+type RecipeCostPointResBody struct {
+	Date string `json:"date" xml:"date"`
+	RecipeCostResBody
+}
+
+// RecipeCostHistoryResBody represents how the estimated cost of a recipe changed over time,
+// there is a point at each date a price of one of its ingredients was recorded
+type RecipeCostHistoryResBody struct {
+	RecipeId uint                      `json:"recipe_id" xml:"recipe_id"`
+	Points   []*RecipeCostPointResBody `json:"points" xml:"point"`
+}
@@ -1,14 +1,51 @@
 // Package dto defines data transfer objects (DTOs) used for communicating between the input and output of an API
 package dto
 
-import "github.com/clementb49/welsh_academy/models"
+import (
+	"github.com/clementb49/welsh_academy/media"
+	"github.com/clementb49/welsh_academy/models"
+)
+
+// RecipeIngredientReqBody represents an ingredient line of the recipe request body.
+type RecipeIngredientReqBody struct {
+	IngredientId uint    `json:"ingredient_id" xml:"ingredient_id" binding:"required"`
+	Quantity     float64 `json:"quantity" xml:"quantity" binding:"min=0"`
+	Unit         string  `json:"unit" xml:"unit" binding:"max=20"`
+	Note         string  `json:"note" xml:"note" binding:"max=200"`
+	Optional     bool    `json:"optional" xml:"optional"`
+}
+
+// ConvertToModel converts a RecipeIngredientReqBody to a RecipeIngredient model at the given position.
+func (r *RecipeIngredientReqBody) ConvertToModel(position int) *models.RecipeIngredient {
+	return &models.RecipeIngredient{
+		IngredientID: r.IngredientId,
+		Position:     uint(position),
+		Quantity:     r.Quantity,
+		Unit:         r.Unit,
+		Note:         r.Note,
+		Optional:     r.Optional,
+	}
+}
+
+// convertIngredientLinesToModel converts the ingredient lines of a request body to RecipeIngredient models, the position is the index of the line.
+func convertIngredientLinesToModel(lines []RecipeIngredientReqBody) []*models.RecipeIngredient {
+	lineModels := make([]*models.RecipeIngredient, len(lines))
+	for i := range lines {
+		lineModels[i] = lines[i].ConvertToModel(i)
+	}
+	return lineModels
+}
 
 // RecipeReqBody represents the request body for creating a recipe.
 type RecipeReqBody struct {
-	Title         string `json:"title" xml:"title" binding:"required"`
-	Description   string `json:"description" xml:"description" binding:"required"`
-	Difficulty    uint8  `json:"difficulty" xml:"difficulty" binding:"required,min=0,max=5"`
-	IngredientsId []uint `json:"ingredients_id" xml:"ingredients_id" binding:"required,min=1"`
+	Title       string                    `json:"title" xml:"title" binding:"required"`
+	Description string                    `json:"description" xml:"description" binding:"required"`
+	Difficulty  uint8                     `json:"difficulty" xml:"difficulty" binding:"required,min=0,max=5"`
+	Servings    uint                      `json:"servings" xml:"servings" binding:"omitempty,min=1,max=1000"`
+	Ingredients []RecipeIngredientReqBody `json:"ingredients" xml:"ingredient" binding:"required,min=1,dive"`
+	// OvenTemperature is the optional oven temperature expressed in OvenTemperatureUnit, Celsius by default
+	OvenTemperature     *float64 `json:"oven_temperature" xml:"oven_temperature" binding:"omitempty,gt=0"`
+	OvenTemperatureUnit string   `json:"oven_temperature_unit" xml:"oven_temperature_unit" binding:"omitempty,oneof=C F gas"`
 }
 
 // ConvertToModdel converts a RecipeReqBody to a Recipe model.
@@ -17,30 +54,251 @@ func (r *RecipeReqBody) ConvertToModdel() *models.Recipe {
 		Title:       r.Title,
 		Description: r.Description,
 		Difficulty:  r.Difficulty,
+		Servings:    r.Servings,
+		Ingredients: convertIngredientLinesToModel(r.Ingredients),
+		// the temperature unit is only stored with a temperature
+		OvenTemperature:     r.OvenTemperature,
+		OvenTemperatureUnit: ovenTemperatureUnit(r.OvenTemperature, r.OvenTemperatureUnit),
+	}
+}
+
+// ovenTemperatureUnit returns the unit stored with the oven temperature, Celsius when it's not specified
+func ovenTemperatureUnit(temperature *float64, unit string) string {
+	if temperature == nil {
+		return ""
+	}
+	if unit == "" {
+		return "C"
+	}
+	return unit
+}
+
+// ApplyToModel replaces the fields and the ingredient lines of the Recipe model with the values of the RecipeReqBody.
+// The number of servings is kept when it's omitted.
+func (r *RecipeReqBody) ApplyToModel(model *models.Recipe) {
+	model.Title = r.Title
+	model.Description = r.Description
+	model.Difficulty = r.Difficulty
+	if r.Servings != 0 {
+		model.Servings = r.Servings
+	}
+	model.Ingredients = convertIngredientLinesToModel(r.Ingredients)
+	model.OvenTemperature = r.OvenTemperature
+	model.OvenTemperatureUnit = ovenTemperatureUnit(r.OvenTemperature, r.OvenTemperatureUnit)
+}
+
+// RecipePatchReqBody represents the request body for partially updating a recipe, only the provided fields are updated.
+type RecipePatchReqBody struct {
+	Title       *string                   `json:"title" xml:"title" binding:"omitempty,min=1"`
+	Description *string                   `json:"description" xml:"description" binding:"omitempty,min=1"`
+	Difficulty  *uint8                    `json:"difficulty" xml:"difficulty" binding:"omitempty,min=0,max=5"`
+	Servings    *uint                     `json:"servings" xml:"servings" binding:"omitempty,min=1,max=1000"`
+	Ingredients []RecipeIngredientReqBody `json:"ingredients" xml:"ingredient" binding:"omitempty,min=1,dive"`
+	// OvenTemperatureUnit is only used with OvenTemperature
+	OvenTemperature     *float64 `json:"oven_temperature" xml:"oven_temperature" binding:"omitempty,gt=0"`
+	OvenTemperatureUnit string   `json:"oven_temperature_unit" xml:"oven_temperature_unit" binding:"omitempty,oneof=C F gas"`
+}
+
+// ApplyToModel replaces the fields of the Recipe model with the values provided in the RecipePatchReqBody.
+func (r *RecipePatchReqBody) ApplyToModel(model *models.Recipe) {
+	if r.Title != nil {
+		model.Title = *r.Title
+	}
+	if r.Description != nil {
+		model.Description = *r.Description
+	}
+	if r.Difficulty != nil {
+		model.Difficulty = *r.Difficulty
+	}
+	if r.Servings != nil {
+		model.Servings = *r.Servings
+	}
+	if r.Ingredients != nil {
+		model.Ingredients = convertIngredientLinesToModel(r.Ingredients)
+	}
+	if r.OvenTemperature != nil {
+		model.OvenTemperature = r.OvenTemperature
+		model.OvenTemperatureUnit = ovenTemperatureUnit(r.OvenTemperature, r.OvenTemperatureUnit)
+	}
+}
+
+// RecipeQuery represents the query parameters used when getting a recipe.
+// When Servings is provided, the ingredient quantities are scaled to make this number of servings.
+// When Units is metric or imperial, the ingredient quantities and the oven temperature are converted to this measurement system.
+type RecipeQuery struct {
+	Servings uint   `form:"servings" json:"servings" xml:"servings" binding:"omitempty,min=1,max=1000"`
+	Units    string `form:"units" json:"units" xml:"units" binding:"omitempty,oneof=metric imperial original"`
+}
+
+// RecipeFilterQuery represents the query parameters used to filter the recipes listing.
+// Match defines if a recipe must use all the included ingredients, types and tags or only one of them.
+// The recipes containing one of the excluded allergens are left out, whatever the Match value.
+// When AllowSubstitutes is true, a recipe using an ingredient which can be replaced by an included ingredient is kept.
+// Sort defines the order of the recipes, the best rated or the most reviewed recipes come first with rating and reviews.
+type RecipeFilterQuery struct {
+	CommonQueryPage
+	IncludeIngredients []uint   `form:"include_ingredients" json:"include_ingredients,omitempty" xml:"include_ingredients,omitempty"`
+	ExcludeIngredients []uint   `form:"exclude_ingredients" json:"exclude_ingredients,omitempty" xml:"exclude_ingredients,omitempty"`
+	IncludeTypes       []string `form:"include_types" json:"include_types,omitempty" xml:"include_types,omitempty"`
+	ExcludeTypes       []string `form:"exclude_types" json:"exclude_types,omitempty" xml:"exclude_types,omitempty"`
+	Tags               []uint   `form:"tags" json:"tags,omitempty" xml:"tags,omitempty"`
+	ExcludeAllergens   []string `form:"exclude_allergens" json:"exclude_allergens,omitempty" xml:"exclude_allergens,omitempty" binding:"omitempty,dive,oneof=celery gluten crustaceans eggs fish lupin milk molluscs mustard nuts peanuts sesame soya sulphites"`
+	Match              string   `form:"match" json:"match,omitempty" xml:"match,omitempty" binding:"omitempty,oneof=any all"`
+	AllowSubstitutes   bool     `form:"allow_substitutes" json:"allow_substitutes,omitempty" xml:"allow_substitutes,omitempty"`
+	Sort               string   `form:"sort" json:"sort,omitempty" xml:"sort,omitempty" binding:"omitempty,oneof=newest oldest rating reviews title"`
+}
+
+// RecipeSearchQuery represents the query parameters of the recipes full text search.
+type RecipeSearchQuery struct {
+	CommonQueryPage
+	Query string `form:"q" json:"q" xml:"q" binding:"required,max=200"`
+}
+
+// RecipeIngredientResBody represents an ingredient line of the recipe response body.
+type RecipeIngredientResBody struct {
+	IngredientResBody
+	Quantity float64 `json:"quantity" xml:"quantity"`
+	Unit     string  `json:"unit" xml:"unit"`
+	Note     string  `json:"note" xml:"note"`
+	Optional bool    `json:"optional" xml:"optional"`
+	// Substitutes lists the ingredients which can replace this one with their quantity
+	Substitutes []*SubstituteResBody `json:"substitutes" xml:"substitute"`
+}
+
+// ConvertFromModel converts a RecipeIngredient model to a RecipeIngredientResBody.
+func (r *RecipeIngredientResBody) ConvertFromModel(model *models.RecipeIngredient) {
+	r.Substitutes = make([]*SubstituteResBody, 0)
+	if model.Ingredient != nil {
+		r.IngredientResBody.ConvertFromModel(model.Ingredient)
+		r.Substitutes = convertSubstitutesFromModel(model.Ingredient)
+	} else {
+		r.ID = model.IngredientID
 	}
+	r.Quantity = model.Quantity
+	r.Unit = model.Unit
+	r.Note = model.Note
+	r.Optional = model.Optional
+	r.UpdateSubstitutes()
 }
 
 // RecipeResBody represents the response body for a recipe.
 type RecipeResBody struct {
 	CommonResBody
-	Title       string               `json:"title" xml:"title"`
-	Description string               `json:"description" xml:"description"`
-	Difficulty  uint8                `json:"difficulty" xml:"difficulty"`
-	Ingredients []*IngredientResBody `json:"ingredients" xml:"ingredient"`
-	AuthorId    uint                 `json:"author_id"`
+	Title               string                     `json:"title" xml:"title"`
+	Description         string                     `json:"description" xml:"description"`
+	Difficulty          uint8                      `json:"difficulty" xml:"difficulty"`
+	Servings            uint                       `json:"servings" xml:"servings"`
+	OriginalServings    uint                       `json:"original_servings,omitempty" xml:"original_servings,omitempty"` // only set when the quantities are scaled
+	Ingredients         []*RecipeIngredientResBody `json:"ingredients" xml:"ingredient"`
+	Steps               []*RecipeStepResBody       `json:"steps,omitempty" xml:"step,omitempty"`
+	Tags                []*TagResBody              `json:"tags" xml:"tag"`
+	Images              []*RecipeImageResBody      `json:"images" xml:"image"`
+	CoverImage          *RecipeImageResBody        `json:"cover_image,omitempty" xml:"cover_image,omitempty"`
+	Allergens           []string                   `json:"allergens" xml:"allergen"` // the allergens contained by the ingredients, the optional ones included
+	Nutrition           *RecipeNutritionResBody    `json:"nutrition" xml:"nutrition"`
+	Cost                *RecipeCostResBody         `json:"cost" xml:"cost"`
+	AuthorId            uint                       `json:"author_id"`
+	OvenTemperature     *float64                   `json:"oven_temperature,omitempty" xml:"oven_temperature,omitempty"`
+	OvenTemperatureUnit string                     `json:"oven_temperature_unit,omitempty" xml:"oven_temperature_unit,omitempty"`
+	OvenGasMark         *float64                   `json:"oven_gas_mark,omitempty" xml:"oven_gas_mark,omitempty"` // only set when the temperature is converted to the imperial system
+	RatingAverage       float64                    `json:"rating_average" xml:"rating_average"`
+	RatingCount         uint                       `json:"rating_count" xml:"rating_count"`
+	CommentCount        uint                       `json:"comment_count" xml:"comment_count"`
+	Parent              *RecipeLinkResBody         `json:"parent,omitempty" xml:"parent,omitempty"` // the recipe this one was forked from
+	Forks               []*RecipeLinkResBody       `json:"forks,omitempty" xml:"fork,omitempty"`    // only set when the recipe is get by ID
+	ForkCount           uint                       `json:"fork_count" xml:"fork_count"`
+}
+
+// RecipeLinkResBody represents a recipe linked to another one by a fork, its title and its author are only set when it's loaded.
+type RecipeLinkResBody struct {
+	ID       uint   `json:"id" xml:"id"`
+	Title    string `json:"title,omitempty" xml:"title,omitempty"`
+	AuthorId uint   `json:"author_id,omitempty" xml:"author_id,omitempty"`
 }
 
-// ConvertFromModel converts a Recipe model to a RecipeResBody.
+// ConvertFromModel converts a Recipe model to a RecipeLinkResBody.
+func (r *RecipeLinkResBody) ConvertFromModel(model *models.Recipe) {
+	r.ID = model.ID
+	r.Title = model.Title
+	r.AuthorId = uint(model.AuthorID)
+}
+
+// ConvertFromModel converts a Recipe model to a RecipeResBody, the nutrition facts and the cost are computed by the services.
 func (r *RecipeResBody) ConvertFromModel(model *models.Recipe) {
 	r.convertFromGormModel(&model.Model)
-	r.Ingredients = make([]*IngredientResBody, len(model.Ingredients))
+	r.Ingredients = make([]*RecipeIngredientResBody, len(model.Ingredients))
+	var allergens models.Allergens
 	for i, v := range model.Ingredients {
-		dto := &IngredientResBody{}
+		dto := &RecipeIngredientResBody{}
 		dto.ConvertFromModel(v)
 		r.Ingredients[i] = dto
+		if v.Ingredient != nil {
+			allergens |= v.Ingredient.Allergens
+		}
+	}
+	r.Allergens = allergens.Names()
+	r.Steps = ConvertStepsFromModel(model.Steps)
+	r.Images = ConvertRecipeImagesFromModel(model.Images)
+	for _, v := range r.Images {
+		if v.Cover {
+			r.CoverImage = v
+			break
+		}
+	}
+	r.Tags = make([]*TagResBody, len(model.Tags))
+	for i, v := range model.Tags {
+		dto := &TagResBody{}
+		dto.ConvertFromModel(v)
+		r.Tags[i] = dto
 	}
 	r.Title = model.Title
 	r.Description = model.Description
 	r.Difficulty = model.Difficulty
+	r.Servings = model.Servings
+	r.OvenTemperature = model.OvenTemperature
+	r.OvenTemperatureUnit = model.OvenTemperatureUnit
 	r.AuthorId = uint(model.AuthorID)
+	r.RatingAverage = model.RatingAverage
+	r.RatingCount = model.RatingCount
+	r.CommentCount = model.CommentCount
+	r.ForkCount = model.ForkCount
+	if model.Parent != nil {
+		r.Parent = &RecipeLinkResBody{}
+		r.Parent.ConvertFromModel(model.Parent)
+	} else if model.ParentID != nil {
+		r.Parent = &RecipeLinkResBody{ID: *model.ParentID}
+	}
+	if len(model.Forks) > 0 {
+		r.Forks = make([]*RecipeLinkResBody, len(model.Forks))
+		for i, v := range model.Forks {
+			dto := &RecipeLinkResBody{}
+			dto.ConvertFromModel(v)
+			r.Forks[i] = dto
+		}
+	}
+}
+
+// SetImageUrls sets the public URLs of the photos of the recipe and of the images of its ingredients kept in the storage
+func (r *RecipeResBody) SetImageUrls(storage media.Storage) {
+	SetRecipeImagesUrls(r.Images, storage)
+	for _, v := range r.Ingredients {
+		v.SetImageUrls(storage)
+	}
+}
+
+// RecipeSearchResBody represents the response body for a recipe found by the full text search.
+type RecipeSearchResBody struct {
+	RecipeResBody
+	Rank    float64 `json:"rank" xml:"rank"`
+	Snippet string  `json:"snippet" xml:"snippet"` // HTML-safe extract of the description, the searched terms are surrounded by <mark> tags
+}
+
+// SimilarRecipeResBody represents the response body for a recipe similar to another one with the ingredients they share,
+// the similarities are between 0 and 1.
+type SimilarRecipeResBody struct {
+	RecipeResBody
+	Similarity           float64                  `json:"similarity" xml:"similarity"`
+	IngredientSimilarity float64                  `json:"ingredient_similarity" xml:"ingredient_similarity"`
+	TagSimilarity        float64                  `json:"tag_similarity" xml:"tag_similarity"`
+	SharedIngredients    []*IngredientLinkResBody `json:"shared_ingredients" xml:"shared_ingredient"`
 }
@@ -0,0 +1,95 @@
+// Package dto defines data transfer objects (DTOs) used for communicating between the input and output of an API
+package dto
+
+import (
+	"time"
+
+	"github.com/clementb49/welsh_academy/models"
+)
+
+// RecipeRevisionPathUri represents the URI parameters for a revision of a recipe
+type RecipeRevisionPathUri struct {
+	ID     uint `uri:"id" binding:"required,min=0"`     // ID represents the unique identifier of the recipe
+	Number uint `uri:"number" binding:"required,min=1"` // Number represents the number of the revision in the history of the recipe
+}
+
+// RecipeRevisionDiffQuery represents the query parameters to compare two revisions of a recipe
+type RecipeRevisionDiffQuery struct {
+	From uint `form:"from" json:"from" xml:"from" binding:"required,min=1"`
+	To   uint `form:"to" json:"to" xml:"to" binding:"required,min=1"`
+}
+
+// RecipeRevisionIngredientResBody represents an ingredient line of a recipe revision.
+type RecipeRevisionIngredientResBody struct {
+	IngredientId uint    `json:"ingredient_id" xml:"ingredient_id"`
+	Name         string  `json:"name" xml:"name"`
+	Quantity     float64 `json:"quantity" xml:"quantity"`
+	Unit         string  `json:"unit" xml:"unit"`
+	Note         string  `json:"note" xml:"note"`
+	Optional     bool    `json:"optional" xml:"optional"`
+}
+
+// RecipeRevisionResBody represents the response body for a revision of a recipe.
+type RecipeRevisionResBody struct {
+	Number              uint                               `json:"number" xml:"number"`
+	RecipeId            uint                               `json:"recipe_id" xml:"recipe_id"`
+	EditorId            uint                               `json:"editor_id" xml:"editor_id"`
+	CreatedAt           time.Time                          `json:"created_at" xml:"created_at"`
+	Title               string                             `json:"title" xml:"title"`
+	Description         string                             `json:"description" xml:"description"`
+	Difficulty          uint8                              `json:"difficulty" xml:"difficulty"`
+	Servings            uint                               `json:"servings" xml:"servings"`
+	OvenTemperature     *float64                           `json:"oven_temperature,omitempty" xml:"oven_temperature,omitempty"`
+	OvenTemperatureUnit string                             `json:"oven_temperature_unit,omitempty" xml:"oven_temperature_unit,omitempty"`
+	Ingredients         []*RecipeRevisionIngredientResBody `json:"ingredients" xml:"ingredient"`
+}
+
+// ConvertFromModel converts a RecipeRevision model to a RecipeRevisionResBody.
+func (r *RecipeRevisionResBody) ConvertFromModel(model *models.RecipeRevision) {
+	r.Number = model.Number
+	r.RecipeId = model.RecipeID
+	r.EditorId = model.EditorID
+	r.CreatedAt = model.CreatedAt
+	r.Title = model.Title
+	r.Description = model.Description
+	r.Difficulty = model.Difficulty
+	r.Servings = model.Servings
+	r.OvenTemperature = model.OvenTemperature
+	r.OvenTemperatureUnit = model.OvenTemperatureUnit
+	r.Ingredients = make([]*RecipeRevisionIngredientResBody, len(model.Ingredients))
+	for i, v := range model.Ingredients {
+		r.Ingredients[i] = &RecipeRevisionIngredientResBody{
+			IngredientId: v.IngredientID,
+			Name:         v.Name,
+			Quantity:     v.Quantity,
+			Unit:         v.Unit,
+			Note:         v.Note,
+			Optional:     v.Optional,
+		}
+	}
+}
+
+// RecipeFieldChangeResBody represents a field whose value is different between two revisions.
+type RecipeFieldChangeResBody struct {
+	Field string      `json:"field" xml:"field"`
+	From  interface{} `json:"from" xml:"from"`
+	To    interface{} `json:"to" xml:"to"`
+}
+
+// RecipeIngredientChangeResBody represents an ingredient line added, removed or modified between two revisions,
+// the changes list the modified fields of the line.
+type RecipeIngredientChangeResBody struct {
+	IngredientId uint                        `json:"ingredient_id" xml:"ingredient_id"`
+	Name         string                      `json:"name" xml:"name"`
+	Change       string                      `json:"change" xml:"change"`
+	Changes      []*RecipeFieldChangeResBody `json:"changes,omitempty" xml:"changes,omitempty"`
+}
+
+// RecipeRevisionDiffResBody represents the differences between two revisions of a recipe.
+type RecipeRevisionDiffResBody struct {
+	RecipeId    uint                             `json:"recipe_id" xml:"recipe_id"`
+	From        uint                             `json:"from" xml:"from"`
+	To          uint                             `json:"to" xml:"to"`
+	Changes     []*RecipeFieldChangeResBody      `json:"changes" xml:"change"`
+	Ingredients []*RecipeIngredientChangeResBody `json:"ingredients" xml:"ingredient"`
+}
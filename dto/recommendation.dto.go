@@ -0,0 +1,11 @@
+// Package dto defines data transfer objects (DTOs) used for communicating between the input and output of an API
+package dto
+
+// RecommendationResBody represents a recipe recommended to the current user with the relevance of the recommendation.
+// The source is favorites when the recipe is favored by the users with the same favorites, ingredients when it uses
+// the ingredients the user likes and popular when it's only recommended for its rating.
+type RecommendationResBody struct {
+	RecipeResBody
+	Score  float64 `json:"score" xml:"score"`
+	Source string  `json:"source" xml:"source"`
+}
@@ -0,0 +1,42 @@
+// Package dto defines data transfer objects (DTOs) used for communicating between the input and output of an API
+package dto
+
+import "github.com/clementb49/welsh_academy/models"
+
+// ReviewReqBody represents the request body for rating and reviewing a recipe, the text is optional
+type ReviewReqBody struct {
+	Rating uint8  `json:"rating" xml:"rating" binding:"required,min=1,max=5"`
+	Text   string `json:"text" xml:"text" binding:"max=5000"`
+}
+
+// ConvertToModel converts a ReviewReqBody to the Review model of the user for the recipe
+func (r *ReviewReqBody) ConvertToModel(userId, recipeId uint) *models.Review {
+	return &models.Review{
+		UserID:   userId,
+		RecipeID: recipeId,
+		Rating:   r.Rating,
+		Text:     r.Text,
+	}
+}
+
+// ReviewResBody represents the response body for a review, only the first name of its author is given
+type ReviewResBody struct {
+	CommonResBody
+	RecipeId   uint   `json:"recipe_id" xml:"recipe_id"`
+	AuthorId   uint   `json:"author_id" xml:"author_id"`
+	AuthorName string `json:"author_name" xml:"author_name"`
+	Rating     uint8  `json:"rating" xml:"rating"`
+	Text       string `json:"text" xml:"text"`
+}
+
+// ConvertFromModel converts a Review model to a ReviewResBody
+func (r *ReviewResBody) ConvertFromModel(model *models.Review) {
+	r.convertFromGormModel(&model.Model)
+	r.RecipeId = model.RecipeID
+	r.AuthorId = model.UserID
+	if model.User != nil {
+		r.AuthorName = model.User.FirstName
+	}
+	r.Rating = model.Rating
+	r.Text = model.Text
+}
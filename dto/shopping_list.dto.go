@@ -0,0 +1,80 @@
+// Package dto defines data transfer objects (DTOs) used for communicating between the input and output of an API
+package dto
+
+import "github.com/clementb49/welsh_academy/models"
+
+// ShoppingListRecipeReqBody represents a recipe to cook in a shopping list request.
+// When Servings is omitted, the quantities of the recipe are used as is.
+type ShoppingListRecipeReqBody struct {
+	RecipeId uint `json:"recipe_id" xml:"recipe_id" binding:"required"`
+	Servings uint `json:"servings" xml:"servings" binding:"omitempty,min=1,max=1000"`
+}
+
+// ShoppingListReqBody represents the request body for generating a shopping list from a set of recipes.
+// The optional ingredients of the recipes are skipped unless IncludeOptional is true.
+type ShoppingListReqBody struct {
+	Title           string                      `json:"title" xml:"title" binding:"max=100"`
+	Recipes         []ShoppingListRecipeReqBody `json:"recipes" xml:"recipe" binding:"required,min=1,dive"`
+	IncludeOptional bool                        `json:"include_optional" xml:"include_optional"`
+}
+
+// ShoppingListItemPathUri represents the URI parameters for an item of a shopping list
+type ShoppingListItemPathUri struct {
+	ID     uint `uri:"id" binding:"required,min=0"`     // ID represents the unique identifier of the shopping list
+	ItemID uint `uri:"itemId" binding:"required,min=0"` // ItemID represents the unique identifier of the item
+}
+
+// ShoppingListItemReqBody represents the request body for ticking off an item of a shopping list
+type ShoppingListItemReqBody struct {
+	Checked *bool `json:"checked" xml:"checked" binding:"required"`
+}
+
+// ShoppingListQuery represents the query parameters used when getting a shopping list.
+// The list is exported as plain text or Markdown when Format is text or markdown.
+type ShoppingListQuery struct {
+	Format string `form:"format" json:"format" xml:"format" binding:"omitempty,oneof=json text markdown"`
+}
+
+// ShoppingListItemResBody represents the response body for an item of a shopping list
+type ShoppingListItemResBody struct {
+	CommonResBody
+	IngredientId uint    `json:"ingredient_id" xml:"ingredient_id"`
+	Name         string  `json:"name" xml:"name"`
+	Aisle        string  `json:"aisle" xml:"aisle"`
+	Quantity     float64 `json:"quantity" xml:"quantity"`
+	Unit         string  `json:"unit" xml:"unit"`
+	Checked      bool    `json:"checked" xml:"checked"`
+}
+
+// ConvertFromModel converts a ShoppingListItem model to a ShoppingListItemResBody
+func (s *ShoppingListItemResBody) ConvertFromModel(model *models.ShoppingListItem) {
+	s.convertFromGormModel(&model.Model)
+	s.IngredientId = model.IngredientID
+	if model.Ingredient != nil {
+		s.Name = model.Ingredient.Name
+	}
+	s.Aisle = model.Aisle
+	s.Quantity = model.Quantity
+	s.Unit = model.Unit
+	s.Checked = model.Checked
+}
+
+// ShoppingListResBody represents the response body for a shopping list, the items are ordered by aisle.
+// The list generated for a preview isn't saved so its identifiers are 0.
+type ShoppingListResBody struct {
+	CommonResBody
+	Title string                     `json:"title" xml:"title"`
+	Items []*ShoppingListItemResBody `json:"items" xml:"item"`
+}
+
+// ConvertFromModel converts a ShoppingList model to a ShoppingListResBody
+func (s *ShoppingListResBody) ConvertFromModel(model *models.ShoppingList) {
+	s.convertFromGormModel(&model.Model)
+	s.Title = model.Title
+	s.Items = make([]*ShoppingListItemResBody, len(model.Items))
+	for i, v := range model.Items {
+		dto := &ShoppingListItemResBody{}
+		dto.ConvertFromModel(v)
+		s.Items[i] = dto
+	}
+}
@@ -0,0 +1,74 @@
+// Package dto defines data transfer objects (DTOs) used for communicating between the input and output of an API
+package dto
+
+import "github.com/clementb49/welsh_academy/models"
+
+// RecipeStepPathUri represents the URI parameters for a step of a recipe
+type RecipeStepPathUri struct {
+	ID     uint `uri:"id" binding:"required,min=0"`     // ID represents the unique identifier of the recipe
+	StepID uint `uri:"stepId" binding:"required,min=0"` // StepID represents the unique identifier of the step
+}
+
+// RecipeStepReqBody represents the request body for creating or updating a recipe step.
+// Position is used only on creation to insert the step, the step is appended at the end of the recipe when it's omitted.
+type RecipeStepReqBody struct {
+	Text          string `json:"text" xml:"text" binding:"required"`
+	Duration      *uint  `json:"duration" xml:"duration" binding:"omitempty,min=1"`
+	IngredientsId []uint `json:"ingredients_id" xml:"ingredients_id"`
+	Position      uint   `json:"position" xml:"position"`
+}
+
+// ConvertToModel converts a RecipeStepReqBody to a RecipeStep model of the recipe.
+func (r *RecipeStepReqBody) ConvertToModel(recipeId uint) *models.RecipeStep {
+	return &models.RecipeStep{
+		RecipeID: recipeId,
+		Position: r.Position,
+		Text:     r.Text,
+		Duration: r.Duration,
+	}
+}
+
+// ApplyToModel replaces the text and the duration of the RecipeStep model with the values of the RecipeStepReqBody.
+func (r *RecipeStepReqBody) ApplyToModel(model *models.RecipeStep) {
+	model.Text = r.Text
+	model.Duration = r.Duration
+}
+
+// RecipeStepsOrderReqBody represents the request body for reordering the steps of a recipe.
+type RecipeStepsOrderReqBody struct {
+	StepsId []uint `json:"steps_id" xml:"steps_id" binding:"required,min=1"`
+}
+
+// RecipeStepResBody represents the response body for a recipe step.
+type RecipeStepResBody struct {
+	CommonResBody
+	Position    uint                 `json:"position" xml:"position"`
+	Text        string               `json:"text" xml:"text"`
+	Duration    *uint                `json:"duration,omitempty" xml:"duration,omitempty"`
+	Ingredients []*IngredientResBody `json:"ingredients" xml:"ingredient"`
+}
+
+// ConvertFromModel converts a RecipeStep model to a RecipeStepResBody.
+func (r *RecipeStepResBody) ConvertFromModel(model *models.RecipeStep) {
+	r.convertFromGormModel(&model.Model)
+	r.Position = model.Position
+	r.Text = model.Text
+	r.Duration = model.Duration
+	r.Ingredients = make([]*IngredientResBody, len(model.Ingredients))
+	for i, v := range model.Ingredients {
+		dto := &IngredientResBody{}
+		dto.ConvertFromModel(v)
+		r.Ingredients[i] = dto
+	}
+}
+
+// ConvertStepsFromModel converts the RecipeStep models to their response bodies.
+func ConvertStepsFromModel(steps []*models.RecipeStep) []*RecipeStepResBody {
+	stepsRes := make([]*RecipeStepResBody, len(steps))
+	for i, v := range steps {
+		dto := &RecipeStepResBody{}
+		dto.ConvertFromModel(v)
+		stepsRes[i] = dto
+	}
+	return stepsRes
+}
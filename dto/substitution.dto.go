@@ -0,0 +1,122 @@
+// Package dto defines data transfer objects (DTOs) used for communicating between the input and output of an API
+package dto
+
+import (
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/units"
+)
+
+// SubstitutionUpdateReqBody represents the request body for updating a substitution.
+// The ratio is the quantity of substitute used for one unit of the ingredient, it defaults to 1.
+// A bidirectional substitution also lets the substitute be replaced by the ingredient.
+type SubstitutionUpdateReqBody struct {
+	Ratio         float64 `json:"ratio" xml:"ratio" binding:"omitempty,gt=0,max=100"`
+	Note          string  `json:"note" xml:"note" binding:"max=200"`
+	Bidirectional bool    `json:"bidirectional" xml:"bidirectional"`
+}
+
+// SubstitutionReqBody represents the request body for adding a substitute to an ingredient
+type SubstitutionReqBody struct {
+	SubstituteId uint `json:"substitute_id" xml:"substitute_id" binding:"required"`
+	SubstitutionUpdateReqBody
+}
+
+// ConvertToModel converts a SubstitutionReqBody to the IngredientSubstitution model of the ingredient added by the user
+func (s *SubstitutionReqBody) ConvertToModel(ingredientId uint, userId uint) *models.IngredientSubstitution {
+	substitution := &models.IngredientSubstitution{
+		IngredientID: ingredientId,
+		SubstituteID: s.SubstituteId,
+		CreatorID:    userId,
+	}
+	s.ApplyToModel(substitution, ingredientId)
+	return substitution
+}
+
+// ApplyToModel replaces the fields of the substitution with the values of the SubstitutionUpdateReqBody,
+// the ratio is given for the ingredient so it's inverted when the ingredient is the substitute of a bidirectional substitution
+func (s *SubstitutionUpdateReqBody) ApplyToModel(model *models.IngredientSubstitution, ingredientId uint) {
+	ratio := s.Ratio
+	if ratio == 0 {
+		ratio = 1
+	}
+	if model.IngredientID != ingredientId {
+		ratio = 1 / ratio
+	}
+	model.Ratio = ratio
+	model.Note = s.Note
+	model.Bidirectional = s.Bidirectional
+}
+
+// SubstitutionPathUri represents the URI parameters for a substitution of an ingredient
+type SubstitutionPathUri struct {
+	ID             uint `uri:"id" binding:"required,min=0"`             // ID represents the unique identifier of the ingredient
+	SubstitutionID uint `uri:"substitutionId" binding:"required,min=0"` // SubstitutionID represents the unique identifier of the substitution
+}
+
+// SubstitutionResBody represents a substitution seen from the ingredient it replaces,
+// the ratio of a bidirectional substitution is inverted when the ingredient is its substitute
+type SubstitutionResBody struct {
+	ID            uint                   `json:"id" xml:"id"`
+	IngredientId  uint                   `json:"ingredient_id" xml:"ingredient_id"`
+	Substitute    *IngredientLinkResBody `json:"substitute" xml:"substitute"`
+	Ratio         float64                `json:"ratio" xml:"ratio"`
+	Note          string                 `json:"note" xml:"note"`
+	Bidirectional bool                   `json:"bidirectional" xml:"bidirectional"`
+	CreatorId     uint                   `json:"creator_id" xml:"creator_id"`
+}
+
+// ConvertFromModel converts an IngredientSubstitution model to a SubstitutionResBody seen from the ingredient
+func (s *SubstitutionResBody) ConvertFromModel(model *models.IngredientSubstitution, ingredientId uint) {
+	s.ID = model.ID
+	s.IngredientId = ingredientId
+	s.Ratio = model.Ratio
+	s.Note = model.Note
+	s.Bidirectional = model.Bidirectional
+	s.CreatorId = model.CreatorID
+	substitute, substituteId := model.Substitute, model.SubstituteID
+	if model.IngredientID != ingredientId {
+		substitute, substituteId = model.Ingredient, model.IngredientID
+		s.Ratio = 1 / model.Ratio
+	}
+	s.Substitute = &IngredientLinkResBody{ID: substituteId}
+	if substitute != nil {
+		s.Substitute.Name = substitute.Name
+	}
+}
+
+// SubstituteResBody represents a substitute suggested for an ingredient line of a recipe,
+// the quantity of substitute is computed from the quantity of the line with the ratio of the substitution
+type SubstituteResBody struct {
+	SubstitutionId uint    `json:"substitution_id" xml:"substitution_id"`
+	ID             uint    `json:"id" xml:"id"`
+	Name           string  `json:"name" xml:"name"`
+	Ratio          float64 `json:"ratio" xml:"ratio"`
+	Quantity       float64 `json:"quantity" xml:"quantity"`
+	Unit           string  `json:"unit" xml:"unit"`
+	Note           string  `json:"note" xml:"note"`
+}
+
+// convertSubstitutesFromModel lists the substitutes of the ingredient, the substitutions where it's the substitute are used
+// in the other direction when they are bidirectional. The deleted substitutes aren't suggested.
+func convertSubstitutesFromModel(model *models.Ingredient) []*SubstituteResBody {
+	substitutes := make([]*SubstituteResBody, 0, len(model.Substitutes)+len(model.ReplacedIngredients))
+	for _, v := range model.Substitutes {
+		if v.Substitute != nil {
+			substitutes = append(substitutes, &SubstituteResBody{SubstitutionId: v.ID, ID: v.Substitute.ID, Name: v.Substitute.Name, Ratio: v.Ratio, Note: v.Note})
+		}
+	}
+	for _, v := range model.ReplacedIngredients {
+		if v.Ingredient != nil && v.Bidirectional {
+			substitutes = append(substitutes, &SubstituteResBody{SubstitutionId: v.ID, ID: v.Ingredient.ID, Name: v.Ingredient.Name, Ratio: 1 / v.Ratio, Note: v.Note})
+		}
+	}
+	return substitutes
+}
+
+// UpdateSubstitutes computes the quantity of each substitute from the quantity and the unit of the ingredient line
+func (r *RecipeIngredientResBody) UpdateSubstitutes() {
+	for _, substitute := range r.Substitutes {
+		substitute.Quantity = units.Round(r.Quantity*substitute.Ratio, r.Unit)
+		substitute.Unit = r.Unit
+	}
+}
@@ -0,0 +1,64 @@
+// Package dto defines data transfer objects (DTOs) used for communicating between the input and output of an API
+package dto
+
+import (
+	"strings"
+
+	"github.com/clementb49/welsh_academy/models"
+)
+
+// TagReqBody represents the request body for creating or updating a tag, a tag without category is a free-form tag
+type TagReqBody struct {
+	Name     string `json:"name" xml:"name" binding:"required,max=50"`
+	Category string `json:"category" xml:"category" binding:"omitempty,oneof=tag course cuisine occasion"`
+}
+
+// ConvertToModel converts a TagReqBody to a Tag model created by the user, the name is stored in lower case
+func (t *TagReqBody) ConvertToModel(userId uint) *models.Tag {
+	tag := &models.Tag{CreatorID: userId}
+	t.ApplyToModel(tag)
+	return tag
+}
+
+// ApplyToModel replaces the name and the category of the Tag model with the values of the TagReqBody
+func (t *TagReqBody) ApplyToModel(model *models.Tag) {
+	model.Name = strings.ToLower(strings.TrimSpace(t.Name))
+	model.Category = t.Category
+	if model.Category == "" {
+		model.Category = models.TagCategoryFree
+	}
+}
+
+// TagQuery represents the query parameters used to list the tags of a category
+type TagQuery struct {
+	CommonQueryPage
+	Category string `form:"category" json:"category,omitempty" xml:"category,omitempty" binding:"omitempty,oneof=tag course cuisine occasion"`
+}
+
+// RecipeTagPathUri represents the URI parameters for a tag of a recipe
+type RecipeTagPathUri struct {
+	ID    uint `uri:"id" binding:"required,min=0"`    // ID represents the unique identifier of the recipe
+	TagID uint `uri:"tagId" binding:"required,min=0"` // TagID represents the unique identifier of the tag
+}
+
+// TagResBody represents the response body for a tag
+type TagResBody struct {
+	CommonResBody
+	Name      string `json:"name" xml:"name"`
+	Category  string `json:"category" xml:"category"`
+	CreatorId uint   `json:"creator_id" xml:"creator_id"`
+}
+
+// ConvertFromModel converts a Tag model to a TagResBody
+func (t *TagResBody) ConvertFromModel(model *models.Tag) {
+	t.convertFromGormModel(&model.Model)
+	t.Name = model.Name
+	t.Category = model.Category
+	t.CreatorId = model.CreatorID
+}
+
+// TagUsageResBody represents the response body for a tag with the number of recipes it classifies
+type TagUsageResBody struct {
+	TagResBody
+	UsageCount int64 `json:"usage_count" xml:"usage_count"`
+}
@@ -0,0 +1,17 @@
+// Package dto defines data transfer objects (DTOs) used for communicating between the input and output of an API
+package dto
+
+// TrendingQuery represents the query parameters used to get the trending recipes over a time window,
+// the window is 24h, 7d or 30d and the recipes trending over the last 7 days are returned when it's empty.
+type TrendingQuery struct {
+	CommonQueryPage
+	Window string `form:"window" json:"window,omitempty" xml:"window,omitempty" binding:"omitempty,oneof=24h 7d 30d"`
+}
+
+// TrendingRecipeResBody represents a trending recipe with its time-decayed score and the number of favorites and views during the window
+type TrendingRecipeResBody struct {
+	RecipeResBody
+	Score         float64 `json:"score" xml:"score"`
+	FavoriteCount uint    `json:"favorite_count" xml:"favorite_count"`
+	ViewCount     uint    `json:"view_count" xml:"view_count"`
+}
@@ -0,0 +1,302 @@
+// Package handlers provides handlers for the HTTP API endpoints of the application.
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// The path of a shared collection, the secret token identifies the collection
+const sharedCollectionPath = "/api/v1/collections/shared/%s"
+
+// CollectionHandler is the interface for recipe collection handlers.
+type CollectionHandler interface {
+	CreateCollectionHandler(ctx *gin.Context)
+	GetAllCollectionsHandler(ctx *gin.Context)
+	GetCollectionByIdHandler(ctx *gin.Context)
+	UpdateCollectionHandler(ctx *gin.Context)
+	DeleteCollectionByIdHandler(ctx *gin.Context)
+	AddRecipeToCollectionHandler(ctx *gin.Context)
+	DeleteRecipeFromCollectionHandler(ctx *gin.Context)
+	ReorderCollectionRecipesHandler(ctx *gin.Context)
+	GetCollectionShareHandler(ctx *gin.Context)
+	RegenerateCollectionShareHandler(ctx *gin.Context)
+	DeleteCollectionShareHandler(ctx *gin.Context)
+	GetAllPublicCollectionsHandler(ctx *gin.Context)
+	GetPublicCollectionByIdHandler(ctx *gin.Context)
+	GetSharedCollectionHandler(ctx *gin.Context)
+}
+
+// collectionHandler is the implementation of CollectionHandler.
+type collectionHandler struct {
+	service services.CollectionService
+	logger  *zap.Logger
+}
+
+// NewCollectionHandler creates a new instance of CollectionHandler.
+func NewCollectionHandler(service services.CollectionService) CollectionHandler {
+	return &collectionHandler{
+		service: service,
+		logger:  zap.L(),
+	}
+}
+
+// CreateCollectionHandler is the handler for creating a recipe collection of the current user.
+func (h *collectionHandler) CreateCollectionHandler(ctx *gin.Context) {
+	var body dto.CollectionReqBody
+	err := ctx.ShouldBind(&body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	collection, err := h.service.CreateCollection(&body, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, collection)
+}
+
+// GetAllCollectionsHandler is the handler for getting the collections of the current user with pagination.
+func (h *collectionHandler) GetAllCollectionsHandler(ctx *gin.Context) {
+	var query dto.CommonQueryPage
+	err := ctx.ShouldBindQuery(&query)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 10
+	}
+	userId := ctx.GetUint("userId")
+	pageCollections, err := h.service.GetAllCollections(&query, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, pageCollections)
+}
+
+// GetCollectionByIdHandler is the handler for getting a collection of the current user by ID.
+func (h *collectionHandler) GetCollectionByIdHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	collection, err := h.service.GetCollectionById(&input, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, collection)
+}
+
+// UpdateCollectionHandler is the handler for updating the title, the description and the visibility of a collection of the current user.
+func (h *collectionHandler) UpdateCollectionHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var body dto.CollectionReqBody
+	err = ctx.ShouldBind(&body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	collection, err := h.service.UpdateCollection(&input, &body, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, collection)
+}
+
+// DeleteCollectionByIdHandler is the handler for deleting a collection of the current user by ID.
+func (h *collectionHandler) DeleteCollectionByIdHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	err = h.service.DeleteCollectionById(&input, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// AddRecipeToCollectionHandler is the handler for adding a recipe at the end of a collection of the current user.
+func (h *collectionHandler) AddRecipeToCollectionHandler(ctx *gin.Context) {
+	var input dto.CollectionRecipePathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	collection, err := h.service.AddRecipeToCollection(&input, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, collection)
+}
+
+// DeleteRecipeFromCollectionHandler is the handler for removing a recipe from a collection of the current user.
+func (h *collectionHandler) DeleteRecipeFromCollectionHandler(ctx *gin.Context) {
+	var input dto.CollectionRecipePathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	collection, err := h.service.DeleteRecipeFromCollection(&input, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, collection)
+}
+
+// ReorderCollectionRecipesHandler is the handler for changing the order of the recipes of a collection of the current user.
+func (h *collectionHandler) ReorderCollectionRecipesHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var body dto.CollectionRecipesOrderReqBody
+	err = ctx.ShouldBind(&body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	collection, err := h.service.ReorderCollectionRecipes(&input, &body, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, collection)
+}
+
+// GetCollectionShareHandler is the handler for getting the share link of a collection of the current user.
+func (h *collectionHandler) GetCollectionShareHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	share, err := h.service.GetCollectionShareToken(&input, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	share.Url = absoluteUrl(ctx, fmt.Sprintf(sharedCollectionPath, share.Token))
+	ctx.JSON(http.StatusOK, share)
+}
+
+// RegenerateCollectionShareHandler is the handler for replacing the share link of a collection of the current user.
+func (h *collectionHandler) RegenerateCollectionShareHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	share, err := h.service.RegenerateCollectionShareToken(&input, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	share.Url = absoluteUrl(ctx, fmt.Sprintf(sharedCollectionPath, share.Token))
+	ctx.JSON(http.StatusOK, share)
+}
+
+// DeleteCollectionShareHandler is the handler for stopping the sharing of a collection of the current user.
+func (h *collectionHandler) DeleteCollectionShareHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	err = h.service.DeleteCollectionShareToken(&input, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetAllPublicCollectionsHandler is the handler for browsing the public collections with pagination.
+func (h *collectionHandler) GetAllPublicCollectionsHandler(ctx *gin.Context) {
+	var query dto.PublicCollectionQuery
+	err := ctx.ShouldBindQuery(&query)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 10
+	}
+	pageCollections, err := h.service.GetAllPublicCollections(&query)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, pageCollections)
+}
+
+// GetPublicCollectionByIdHandler is the handler for getting a public collection by ID.
+func (h *collectionHandler) GetPublicCollectionByIdHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	collection, err := h.service.GetPublicCollectionById(&input)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, collection)
+}
+
+// GetSharedCollectionHandler is the handler for reading a collection with its share link, the collection is identified by the secret token.
+func (h *collectionHandler) GetSharedCollectionHandler(ctx *gin.Context) {
+	var input dto.CollectionTokenPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	collection, err := h.service.GetSharedCollection(&input)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, collection)
+}
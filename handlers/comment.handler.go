@@ -0,0 +1,150 @@
+// Package handlers provides handlers for the HTTP API endpoints of the application.
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// CommentHandler is the interface for recipe comment handlers.
+type CommentHandler interface {
+	GetAllCommentsHandler(ctx *gin.Context)
+	CreateCommentHandler(ctx *gin.Context)
+	UpdateCommentHandler(ctx *gin.Context)
+	DeleteCommentHandler(ctx *gin.Context)
+	PinCommentHandler(ctx *gin.Context)
+	UnpinCommentHandler(ctx *gin.Context)
+}
+
+// commentHandler is the implementation of CommentHandler.
+type commentHandler struct {
+	service services.CommentService
+	logger  *zap.Logger
+}
+
+// NewCommentHandler creates a new instance of CommentHandler.
+func NewCommentHandler(service services.CommentService) CommentHandler {
+	return &commentHandler{
+		service: service,
+		logger:  zap.L(),
+	}
+}
+
+// GetAllCommentsHandler is the handler for getting the comment threads of a recipe with pagination.
+func (h *commentHandler) GetAllCommentsHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var query dto.CommonQueryPage
+	err = ctx.ShouldBindQuery(&query)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 10
+	}
+	pageComments, err := h.service.GetAllComments(&input, &query)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, pageComments)
+}
+
+// CreateCommentHandler is the handler for commenting a recipe or replying to a comment.
+func (h *commentHandler) CreateCommentHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var body dto.CommentReqBody
+	err = ctx.ShouldBind(&body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	comment, err := h.service.CreateComment(&input, &body, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, comment)
+}
+
+// UpdateCommentHandler is the handler for editing the text of a comment.
+func (h *commentHandler) UpdateCommentHandler(ctx *gin.Context) {
+	var input dto.CommentPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var body dto.CommentReqBody
+	err = ctx.ShouldBind(&body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	comment, err := h.service.UpdateComment(&input, &body, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, comment)
+}
+
+// DeleteCommentHandler is the handler for deleting a comment, its replies are kept.
+func (h *commentHandler) DeleteCommentHandler(ctx *gin.Context) {
+	var input dto.CommentPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	err = h.service.DeleteComment(&input, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// PinCommentHandler is the handler for pinning a comment at the top of the comments of a recipe.
+func (h *commentHandler) PinCommentHandler(ctx *gin.Context) {
+	h.pinComment(ctx, true)
+}
+
+// UnpinCommentHandler is the handler for unpinning a comment of a recipe.
+func (h *commentHandler) UnpinCommentHandler(ctx *gin.Context) {
+	h.pinComment(ctx, false)
+}
+
+// pinComment sets the pinned flag of the comment
+func (h *commentHandler) pinComment(ctx *gin.Context, pinned bool) {
+	var input dto.CommentPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	comment, err := h.service.PinComment(&input, pinned, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, comment)
+}
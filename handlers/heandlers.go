@@ -2,8 +2,14 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 
+	"github.com/clementb49/welsh_academy/importer"
+	"github.com/clementb49/welsh_academy/media"
+	"github.com/clementb49/welsh_academy/repositories"
+	"github.com/clementb49/welsh_academy/services"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
@@ -12,15 +18,43 @@ import (
 // The error types are checked, and the corresponding HTTP status code is set. If the error is not recognized, a 500 Internal Server Error status code is returned.
 // The function takes a gin.Context object and the GORM error as input parameters. The error message is included in the JSON response.
 // The function is used in the Golang Gin framework to handle GORM database errors in HTTP request handlers.
+// The errors returned by the services and the repositories for invalid operations are also translated to their HTTP status code.
 func gormErrorResponseHandler(ctx *gin.Context, err error) {
 	var httpStatus int
-	switch err {
-	case gorm.ErrRecordNotFound:
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
 		httpStatus = http.StatusNotFound
-	case gorm.ErrDuplicatedKey:
+	case errors.Is(err, gorm.ErrDuplicatedKey):
 		httpStatus = http.StatusConflict
+	case errors.Is(err, services.ErrForbidden):
+		httpStatus = http.StatusForbidden
+	case errors.Is(err, repositories.ErrRecipeNotAcceptable), errors.Is(err, repositories.ErrRecipeDuplicatedIngredient),
+		errors.Is(err, repositories.ErrStepIngredientNotAcceptable), errors.Is(err, repositories.ErrStepsOrderNotAcceptable),
+		errors.Is(err, services.ErrMealPlanPeriodNotAcceptable), errors.Is(err, repositories.ErrCommentParentNotAcceptable),
+		errors.Is(err, services.ErrPriceUnitNotAcceptable), errors.Is(err, repositories.ErrPantryIngredientNotAcceptable),
+		errors.Is(err, services.ErrSubstitutionNotAcceptable), errors.Is(err, repositories.ErrCollectionOrderNotAcceptable),
+		errors.Is(err, services.ErrRecipeImagesNotAcceptable), errors.Is(err, importer.ErrRecipeNotFound),
+		errors.Is(err, importer.ErrRecipeNotAcceptable):
+		httpStatus = http.StatusUnprocessableEntity
+	case errors.Is(err, media.ErrImageTooLarge), errors.Is(err, importer.ErrDocumentTooLarge), errors.As(err, new(*http.MaxBytesError)):
+		httpStatus = http.StatusRequestEntityTooLarge
+	case errors.Is(err, media.ErrImageNotAcceptable):
+		httpStatus = http.StatusUnsupportedMediaType
 	default:
 		httpStatus = http.StatusInternalServerError
 	}
 	ctx.JSON(httpStatus, gin.H{"error": err.Error()})
 }
+
+// absoluteUrl returns the URL of the path on the host of the request, it's used to build the links given to the clients.
+// The scheme forwarded by a reverse proxy is preferred to the scheme of the request.
+func absoluteUrl(ctx *gin.Context, path string) string {
+	scheme := "http"
+	if ctx.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := ctx.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return fmt.Sprintf("%s://%s%s", scheme, ctx.Request.Host, path)
+}
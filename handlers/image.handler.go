@@ -0,0 +1,148 @@
+// Package handlers provides handlers for the HTTP API endpoints of the application.
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ImageHandler is the interface for recipe photo and ingredient image handlers.
+type ImageHandler interface {
+	UploadRecipeImageHandler(ctx *gin.Context)
+	GetAllRecipeImagesHandler(ctx *gin.Context)
+	SetRecipeCoverImageHandler(ctx *gin.Context)
+	DeleteRecipeImageHandler(ctx *gin.Context)
+	UploadIngredientImageHandler(ctx *gin.Context)
+	DeleteIngredientImageHandler(ctx *gin.Context)
+}
+
+// imageHandler is the implementation of ImageHandler.
+type imageHandler struct {
+	service services.ImageService
+	logger  *zap.Logger
+}
+
+// NewImageHandler creates a new instance of ImageHandler.
+func NewImageHandler(service services.ImageService) ImageHandler {
+	return &imageHandler{
+		service: service,
+		logger:  zap.L(),
+	}
+}
+
+// UploadRecipeImageHandler is the handler for uploading a photo of a recipe with a multipart form.
+func (h *imageHandler) UploadRecipeImageHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var body dto.RecipeImageReqBody
+	err = ctx.ShouldBind(&body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	image, err := h.service.UploadRecipeImage(&input, &body, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, image)
+}
+
+// GetAllRecipeImagesHandler is the handler for getting the photos of a recipe.
+func (h *imageHandler) GetAllRecipeImagesHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	images, err := h.service.GetAllRecipeImages(&input)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, images)
+}
+
+// SetRecipeCoverImageHandler is the handler for making a photo the cover of its recipe.
+func (h *imageHandler) SetRecipeCoverImageHandler(ctx *gin.Context) {
+	var input dto.RecipeImagePathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	image, err := h.service.SetRecipeCoverImage(&input, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, image)
+}
+
+// DeleteRecipeImageHandler is the handler for deleting a photo of a recipe.
+func (h *imageHandler) DeleteRecipeImageHandler(ctx *gin.Context) {
+	var input dto.RecipeImagePathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	err = h.service.DeleteRecipeImage(&input, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// UploadIngredientImageHandler is the handler for uploading the image of an ingredient with a multipart form.
+func (h *imageHandler) UploadIngredientImageHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var body dto.ImageReqBody
+	err = ctx.ShouldBind(&body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	ingredient, err := h.service.UploadIngredientImage(&input, &body, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, ingredient)
+}
+
+// DeleteIngredientImageHandler is the handler for deleting the image of an ingredient.
+func (h *imageHandler) DeleteIngredientImageHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	err = h.service.DeleteIngredientImage(&input, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
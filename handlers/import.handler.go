@@ -0,0 +1,88 @@
+// Package handlers provides handlers for the HTTP API endpoints of the application.
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ImportHandler is the interface for recipe import handlers.
+type ImportHandler interface {
+	ImportRecipeHandler(ctx *gin.Context)
+	CommitRecipeImportHandler(ctx *gin.Context)
+}
+
+// importHandler is the implementation of ImportHandler.
+type importHandler struct {
+	service services.ImportService
+	logger  *zap.Logger
+}
+
+// NewImportHandler creates a new instance of ImportHandler.
+func NewImportHandler(service services.ImportService) ImportHandler {
+	return &importHandler{
+		service: service,
+		logger:  zap.L(),
+	}
+}
+
+// ImportRecipeHandler is the handler for previewing the import of a schema.org recipe uploaded with a multipart form or sent as the raw body.
+// The body is limited to the maximum size of a document before the form is parsed.
+func (h *importHandler) ImportRecipeHandler(ctx *gin.Context) {
+	var query dto.RecipeImportQuery
+	err := ctx.ShouldBindQuery(&query)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, services.MaxImportSize)
+	var content io.Reader = ctx.Request.Body
+	if ctx.ContentType() == gin.MIMEMultipartPOSTForm {
+		var body dto.RecipeImportReqBody
+		err = ctx.ShouldBind(&body)
+		if errors.As(err, new(*http.MaxBytesError)) {
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		file, err := body.File.Open()
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		defer file.Close()
+		content = file
+	}
+	imported, err := h.service.ImportRecipe(content, &query)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, imported)
+}
+
+// CommitRecipeImportHandler is the handler for creating the recipe of a reviewed import preview.
+func (h *importHandler) CommitRecipeImportHandler(ctx *gin.Context) {
+	var body dto.RecipeImportCommitReqBody
+	err := ctx.ShouldBind(&body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	recipe, err := h.service.CommitRecipeImport(&body, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, recipe)
+}
@@ -15,6 +15,7 @@ type IngredientHandlers interface {
 	CreateIngredientHandler(ctx *gin.Context)
 	GetAllIngredients(ctx *gin.Context)
 	GetIngredientByIdHandler(*gin.Context)
+	UpdateIngredientHandler(ctx *gin.Context)
 	DeleteIngredientByIdHandler(ctx *gin.Context)
 }
 
@@ -83,6 +84,29 @@ func (h *ingredientHandlers) GetIngredientByIdHandler(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, ingredient)
 }
 
+// UpdateIngredientHandler replaces the fields of the ingredient with the given ID.
+func (h *ingredientHandlers) UpdateIngredientHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var body dto.IngredientReqBody
+	err = ctx.ShouldBind(&body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	ingredient, err := h.service.UpdateIngredient(input.ID, &body, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, ingredient)
+}
+
 // DeleteIngredientByIdHandler deletes the ingredient with the given ID.
 func (h *ingredientHandlers) DeleteIngredientByIdHandler(ctx *gin.Context) {
 	var input dto.CommonIdPathUri
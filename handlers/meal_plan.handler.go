@@ -0,0 +1,172 @@
+// Package handlers provides handlers for the HTTP API endpoints of the application.
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// The path of the calendar feed of the meal plans, it contains the secret token of the user
+const calendarFeedPath = "/api/v1/mealplans/%s/calendar.ics"
+
+// MealPlanHandler is the interface for meal plan handlers.
+type MealPlanHandler interface {
+	CreateMealPlanHandler(ctx *gin.Context)
+	GetAllMealPlansHandler(ctx *gin.Context)
+	GetMealPlanByIdHandler(ctx *gin.Context)
+	UpdateMealPlanHandler(ctx *gin.Context)
+	DeleteMealPlanByIdHandler(ctx *gin.Context)
+	GetCalendarHandler(ctx *gin.Context)
+	RegenerateCalendarHandler(ctx *gin.Context)
+	ExportCalendarHandler(ctx *gin.Context)
+}
+
+// mealPlanHandler is the implementation of MealPlanHandler.
+type mealPlanHandler struct {
+	service services.MealPlanService
+	logger  *zap.Logger
+}
+
+// NewMealPlanHandler creates a new instance of MealPlanHandler.
+func NewMealPlanHandler(service services.MealPlanService) MealPlanHandler {
+	return &mealPlanHandler{
+		service: service,
+		logger:  zap.L(),
+	}
+}
+
+// CreateMealPlanHandler is the handler for planning a recipe for a meal of the current user.
+func (h *mealPlanHandler) CreateMealPlanHandler(ctx *gin.Context) {
+	var body dto.MealPlanReqBody
+	err := ctx.ShouldBind(&body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	plan, err := h.service.CreateMealPlan(&body, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, plan)
+}
+
+// GetAllMealPlansHandler is the handler for getting the meal plans of the current user during a period.
+func (h *mealPlanHandler) GetAllMealPlansHandler(ctx *gin.Context) {
+	var query dto.MealPlanQuery
+	err := ctx.ShouldBindQuery(&query)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	plans, err := h.service.GetAllMealPlans(&query, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, plans)
+}
+
+// GetMealPlanByIdHandler is the handler for getting a meal plan of the current user by ID.
+func (h *mealPlanHandler) GetMealPlanByIdHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	plan, err := h.service.GetMealPlanById(&input, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, plan)
+}
+
+// UpdateMealPlanHandler is the handler for replacing a meal plan of the current user by ID.
+func (h *mealPlanHandler) UpdateMealPlanHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var body dto.MealPlanReqBody
+	err = ctx.ShouldBind(&body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	plan, err := h.service.UpdateMealPlan(&input, &body, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, plan)
+}
+
+// DeleteMealPlanByIdHandler is the handler for deleting a meal plan of the current user by ID.
+func (h *mealPlanHandler) DeleteMealPlanByIdHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	err = h.service.DeleteMealPlanById(&input, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetCalendarHandler is the handler for getting the URL of the calendar feed of the current user.
+func (h *mealPlanHandler) GetCalendarHandler(ctx *gin.Context) {
+	userId := ctx.GetUint("userId")
+	calendar, err := h.service.GetCalendarToken(userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	calendar.Url = absoluteUrl(ctx, fmt.Sprintf(calendarFeedPath, calendar.Token))
+	ctx.JSON(http.StatusOK, calendar)
+}
+
+// RegenerateCalendarHandler is the handler for replacing the URL of the calendar feed of the current user.
+func (h *mealPlanHandler) RegenerateCalendarHandler(ctx *gin.Context) {
+	userId := ctx.GetUint("userId")
+	calendar, err := h.service.RegenerateCalendarToken(userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	calendar.Url = absoluteUrl(ctx, fmt.Sprintf(calendarFeedPath, calendar.Token))
+	ctx.JSON(http.StatusOK, calendar)
+}
+
+// ExportCalendarHandler is the handler for the iCalendar feed of the meal plans, the user is identified by the secret token.
+func (h *mealPlanHandler) ExportCalendarHandler(ctx *gin.Context) {
+	var input dto.CalendarTokenPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	calendar, err := h.service.ExportCalendar(&input)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(calendar))
+}
@@ -0,0 +1,71 @@
+// Package handlers provides handlers for the HTTP API endpoints of the application.
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// NutritionHandler is the interface for ingredient nutrition handlers.
+type NutritionHandler interface {
+	SaveIngredientNutritionHandler(ctx *gin.Context)
+	DeleteIngredientNutritionHandler(ctx *gin.Context)
+}
+
+// nutritionHandler is the implementation of NutritionHandler.
+type nutritionHandler struct {
+	service services.NutritionService
+	logger  *zap.Logger
+}
+
+// NewNutritionHandler creates a new instance of NutritionHandler.
+func NewNutritionHandler(service services.NutritionService) NutritionHandler {
+	return &nutritionHandler{
+		service: service,
+		logger:  zap.L(),
+	}
+}
+
+// SaveIngredientNutritionHandler is the handler for setting the nutrition facts of an ingredient.
+func (h *nutritionHandler) SaveIngredientNutritionHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var body dto.NutritionReqBody
+	err = ctx.ShouldBind(&body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	ingredient, err := h.service.SaveIngredientNutrition(&input, &body, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, ingredient)
+}
+
+// DeleteIngredientNutritionHandler is the handler for removing the nutrition facts of an ingredient.
+func (h *nutritionHandler) DeleteIngredientNutritionHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	err = h.service.DeleteIngredientNutrition(&input, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,125 @@
+// Package handlers provides handlers for the HTTP API endpoints of the application.
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// PantryHandler is the interface for pantry handlers.
+type PantryHandler interface {
+	GetAllPantryIngredientsHandler(ctx *gin.Context)
+	AddIngredientToPantryHandler(ctx *gin.Context)
+	DeleteIngredientFromPantryHandler(ctx *gin.Context)
+	ReplacePantryHandler(ctx *gin.Context)
+	GetCookableRecipesHandler(ctx *gin.Context)
+}
+
+// pantryHandler is the implementation of PantryHandler.
+type pantryHandler struct {
+	service services.PantryService
+	logger  *zap.Logger
+}
+
+// NewPantryHandler creates a new instance of PantryHandler.
+func NewPantryHandler(service services.PantryService) PantryHandler {
+	return &pantryHandler{
+		service: service,
+		logger:  zap.L(),
+	}
+}
+
+// GetAllPantryIngredientsHandler is the handler for getting the ingredients of the pantry of the current user with pagination.
+func (h *pantryHandler) GetAllPantryIngredientsHandler(ctx *gin.Context) {
+	var query dto.CommonQueryPage
+	err := ctx.ShouldBindQuery(&query)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 10
+	}
+	userId := ctx.GetUint("userId")
+	pageIngredients, err := h.service.GetAllPantryIngredients(&query, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, pageIngredients)
+}
+
+// AddIngredientToPantryHandler is the handler for adding an ingredient to the pantry of the current user.
+func (h *pantryHandler) AddIngredientToPantryHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	ingredient, err := h.service.AddIngredientToPantry(&input, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, ingredient)
+}
+
+// DeleteIngredientFromPantryHandler is the handler for removing an ingredient from the pantry of the current user.
+func (h *pantryHandler) DeleteIngredientFromPantryHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	err = h.service.DeleteIngredientFromPantry(&input, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ReplacePantryHandler is the handler for replacing the content of the pantry of the current user.
+func (h *pantryHandler) ReplacePantryHandler(ctx *gin.Context) {
+	var body dto.PantryReqBody
+	err := ctx.ShouldBind(&body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	err = h.service.ReplacePantry(&body, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetCookableRecipesHandler is the handler for getting the recipes the current user can cook with their pantry with pagination.
+func (h *pantryHandler) GetCookableRecipesHandler(ctx *gin.Context) {
+	var query dto.CookableQuery
+	err := ctx.ShouldBindQuery(&query)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 10
+	}
+	userId := ctx.GetUint("userId")
+	pageRecipes, err := h.service.GetCookableRecipes(&query, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, pageRecipes)
+}
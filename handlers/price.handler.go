@@ -0,0 +1,114 @@
+// Package handlers provides handlers for the HTTP API endpoints of the application.
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// PriceHandler is the interface for ingredient price handlers.
+type PriceHandler interface {
+	CreateIngredientPriceHandler(ctx *gin.Context)
+	GetAllIngredientPricesHandler(ctx *gin.Context)
+	DeleteIngredientPriceHandler(ctx *gin.Context)
+	GetRecipeCostHistoryHandler(ctx *gin.Context)
+}
+
+// priceHandler is the implementation of PriceHandler.
+type priceHandler struct {
+	service services.PriceService
+	logger  *zap.Logger
+}
+
+// NewPriceHandler creates a new instance of PriceHandler.
+func NewPriceHandler(service services.PriceService) PriceHandler {
+	return &priceHandler{
+		service: service,
+		logger:  zap.L(),
+	}
+}
+
+// CreateIngredientPriceHandler is the handler for recording a price of an ingredient.
+func (h *priceHandler) CreateIngredientPriceHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var body dto.IngredientPriceReqBody
+	err = ctx.ShouldBind(&body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	price, err := h.service.CreateIngredientPrice(&input, &body, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, price)
+}
+
+// GetAllIngredientPricesHandler is the handler for getting the price history of an ingredient with pagination.
+func (h *priceHandler) GetAllIngredientPricesHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var query dto.CommonQueryPage
+	err = ctx.ShouldBindQuery(&query)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 10
+	}
+	pagePrices, err := h.service.GetAllIngredientPrices(&input, &query)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, pagePrices)
+}
+
+// DeleteIngredientPriceHandler is the handler for removing a price from the history of an ingredient.
+func (h *priceHandler) DeleteIngredientPriceHandler(ctx *gin.Context) {
+	var input dto.IngredientPricePathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	err = h.service.DeleteIngredientPrice(&input, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetRecipeCostHistoryHandler is the handler for getting how the estimated cost of a recipe changed over time.
+func (h *priceHandler) GetRecipeCostHistoryHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	history, err := h.service.GetRecipeCostHistory(&input)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, history)
+}
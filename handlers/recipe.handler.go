@@ -14,8 +14,13 @@ import (
 type RecipeHandler interface {
 	CreateRecipeHandler(*gin.Context)
 	GetAllRecipeHandler(*gin.Context)
+	SearchRecipeHandler(ctx *gin.Context)
+	GetSimilarRecipesHandler(ctx *gin.Context)
 	GetRecipeByIdHandler(ctx *gin.Context)
+	UpdateRecipeHandler(ctx *gin.Context)
+	PatchRecipeHandler(ctx *gin.Context)
 	DeleteRecipeById(ctx *gin.Context)
+	ForkRecipeHandler(ctx *gin.Context)
 	AddToFavRecipeHandler(*gin.Context)
 	DeleteFavRecipeHandler(ctx *gin.Context)
 	GetAllFavRecipeHandler(ctx *gin.Context)
@@ -52,9 +57,9 @@ func (h *recipeHandler) CreateRecipeHandler(ctx *gin.Context) {
 	ctx.JSON(http.StatusCreated, recipe)
 }
 
-// GetAllRecipeHandler is the handler for getting all recipes.
+// GetAllRecipeHandler is the handler for getting all recipes matching the query filter.
 func (h *recipeHandler) GetAllRecipeHandler(ctx *gin.Context) {
-	var input dto.CommonQueryPage
+	var input dto.RecipeFilterQuery
 	err := ctx.ShouldBindQuery(&input)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -71,7 +76,51 @@ func (h *recipeHandler) GetAllRecipeHandler(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, pageRecipes)
 }
 
-// GetRecipeByIdHandler is the handler for getting a recipe by ID.
+// SearchRecipeHandler is the handler for the recipes full text search.
+func (h *recipeHandler) SearchRecipeHandler(ctx *gin.Context) {
+	var input dto.RecipeSearchQuery
+	err := ctx.ShouldBindQuery(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.PageSize == 0 {
+		input.PageSize = 10
+	}
+	pageRecipes, err := h.service.SearchRecipes(&input)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, pageRecipes)
+}
+
+// GetSimilarRecipesHandler is the handler for getting the recipes similar to a recipe with pagination.
+func (h *recipeHandler) GetSimilarRecipesHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var query dto.CommonQueryPage
+	err = ctx.ShouldBindQuery(&query)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 10
+	}
+	pageRecipes, err := h.service.GetSimilarRecipes(&input, &query)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, pageRecipes)
+}
+
+// GetRecipeByIdHandler is the handler for getting a recipe by ID, optionally scaled to a number of servings.
 func (h *recipeHandler) GetRecipeByIdHandler(ctx *gin.Context) {
 	var input dto.CommonIdPathUri
 	err := ctx.ShouldBindUri(&input)
@@ -79,7 +128,60 @@ func (h *recipeHandler) GetRecipeByIdHandler(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	recipe, err := h.service.GetRecipeById(&input)
+	var query dto.RecipeQuery
+	err = ctx.ShouldBindQuery(&query)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	// the views are counted per client address because the recipes are read without being authenticated
+	recipe, err := h.service.GetRecipeById(&input, &query, ctx.ClientIP())
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, recipe)
+}
+
+// UpdateRecipeHandler is the handler for replacing a recipe by ID.
+func (h *recipeHandler) UpdateRecipeHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var body dto.RecipeReqBody
+	err = ctx.ShouldBind(&body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	recipe, err := h.service.UpdateRecipe(&input, &body, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, recipe)
+}
+
+// PatchRecipeHandler is the handler for partially updating a recipe by ID.
+func (h *recipeHandler) PatchRecipeHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var body dto.RecipePatchReqBody
+	err = ctx.ShouldBind(&body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	recipe, err := h.service.PatchRecipe(&input, &body, userId)
 	if err != nil {
 		gormErrorResponseHandler(ctx, err)
 		return
@@ -95,7 +197,8 @@ func (h *recipeHandler) DeleteRecipeById(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	err = h.service.DeleteRecipeById(&input)
+	userId := ctx.GetUint("userId")
+	err = h.service.DeleteRecipeById(&input, userId)
 	if err != nil {
 		gormErrorResponseHandler(ctx, err)
 		return
@@ -103,6 +206,23 @@ func (h *recipeHandler) DeleteRecipeById(ctx *gin.Context) {
 	ctx.Status(http.StatusNoContent)
 }
 
+// ForkRecipeHandler is the handler for copying a recipe as a new recipe of the current user.
+func (h *recipeHandler) ForkRecipeHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	recipe, err := h.service.ForkRecipe(&input, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, recipe)
+}
+
 // AddToFavRecipeHandler is the handler for adding a recipe to favorites.
 func (h *recipeHandler) AddToFavRecipeHandler(ctx *gin.Context) {
 	var input dto.CommonIdPathUri
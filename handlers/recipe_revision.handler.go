@@ -0,0 +1,113 @@
+// Package handlers provides handlers for the HTTP API endpoints of the application.
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RecipeRevisionHandler is the interface for recipe revision handlers.
+type RecipeRevisionHandler interface {
+	GetAllRecipeRevisionsHandler(ctx *gin.Context)
+	GetRecipeRevisionHandler(ctx *gin.Context)
+	DiffRecipeRevisionsHandler(ctx *gin.Context)
+	RollbackRecipeHandler(ctx *gin.Context)
+}
+
+// recipeRevisionHandler is the implementation of RecipeRevisionHandler.
+type recipeRevisionHandler struct {
+	service services.RecipeRevisionService
+	logger  *zap.Logger
+}
+
+// NewRecipeRevisionHandler creates a new instance of RecipeRevisionHandler.
+func NewRecipeRevisionHandler(service services.RecipeRevisionService) RecipeRevisionHandler {
+	return &recipeRevisionHandler{
+		service: service,
+		logger:  zap.L(),
+	}
+}
+
+// GetAllRecipeRevisionsHandler is the handler for getting the revisions of a recipe with pagination.
+func (h *recipeRevisionHandler) GetAllRecipeRevisionsHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var query dto.CommonQueryPage
+	err = ctx.ShouldBindQuery(&query)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 10
+	}
+	pageRevisions, err := h.service.GetAllRecipeRevisions(&input, &query)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, pageRevisions)
+}
+
+// GetRecipeRevisionHandler is the handler for getting a revision of a recipe by its number.
+func (h *recipeRevisionHandler) GetRecipeRevisionHandler(ctx *gin.Context) {
+	var input dto.RecipeRevisionPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	revision, err := h.service.GetRecipeRevision(&input)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, revision)
+}
+
+// DiffRecipeRevisionsHandler is the handler for comparing two revisions of a recipe.
+func (h *recipeRevisionHandler) DiffRecipeRevisionsHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var query dto.RecipeRevisionDiffQuery
+	err = ctx.ShouldBindQuery(&query)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	diff, err := h.service.DiffRecipeRevisions(&input, &query)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, diff)
+}
+
+// RollbackRecipeHandler is the handler for restoring a revision of a recipe.
+func (h *recipeRevisionHandler) RollbackRecipeHandler(ctx *gin.Context) {
+	var input dto.RecipeRevisionPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	recipe, err := h.service.RollbackRecipe(&input, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, recipe)
+}
@@ -0,0 +1,50 @@
+// Package handlers provides handlers for the HTTP API endpoints of the application.
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RecommendationHandler is the interface for recommendation handlers.
+type RecommendationHandler interface {
+	GetUserRecommendationsHandler(ctx *gin.Context)
+}
+
+// recommendationHandler is the implementation of RecommendationHandler.
+type recommendationHandler struct {
+	service services.RecommendationService
+	logger  *zap.Logger
+}
+
+// NewRecommendationHandler creates a new instance of RecommendationHandler.
+func NewRecommendationHandler(service services.RecommendationService) RecommendationHandler {
+	return &recommendationHandler{
+		service: service,
+		logger:  zap.L(),
+	}
+}
+
+// GetUserRecommendationsHandler is the handler for getting the recipes recommended to the current user with pagination.
+func (h *recommendationHandler) GetUserRecommendationsHandler(ctx *gin.Context) {
+	var query dto.CommonQueryPage
+	err := ctx.ShouldBindQuery(&query)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 10
+	}
+	userId := ctx.GetUint("userId")
+	pageRecipes, err := h.service.GetUserRecommendations(&query, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, pageRecipes)
+}
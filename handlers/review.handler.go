@@ -0,0 +1,97 @@
+// Package handlers provides handlers for the HTTP API endpoints of the application.
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ReviewHandler is the interface for recipe review handlers.
+type ReviewHandler interface {
+	SaveReviewHandler(ctx *gin.Context)
+	GetAllReviewsHandler(ctx *gin.Context)
+	DeleteReviewHandler(ctx *gin.Context)
+}
+
+// reviewHandler is the implementation of ReviewHandler.
+type reviewHandler struct {
+	service services.ReviewService
+	logger  *zap.Logger
+}
+
+// NewReviewHandler creates a new instance of ReviewHandler.
+func NewReviewHandler(service services.ReviewService) ReviewHandler {
+	return &reviewHandler{
+		service: service,
+		logger:  zap.L(),
+	}
+}
+
+// SaveReviewHandler is the handler for rating and reviewing a recipe, the review of the current user is replaced when it exists.
+func (h *reviewHandler) SaveReviewHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var body dto.ReviewReqBody
+	err = ctx.ShouldBind(&body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	review, err := h.service.SaveReview(&input, &body, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, review)
+}
+
+// GetAllReviewsHandler is the handler for getting the reviews of a recipe with pagination.
+func (h *reviewHandler) GetAllReviewsHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var query dto.CommonQueryPage
+	err = ctx.ShouldBindQuery(&query)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 10
+	}
+	pageReviews, err := h.service.GetAllReviews(&input, &query)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, pageReviews)
+}
+
+// DeleteReviewHandler is the handler for deleting the review of a recipe written by the current user.
+func (h *reviewHandler) DeleteReviewHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	err = h.service.DeleteReview(&input, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,166 @@
+// Package handlers provides handlers for the HTTP API endpoints of the application.
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// The content types of the exported shopping lists
+var shoppingListContentTypes = map[string]string{
+	"text":     "text/plain; charset=utf-8",
+	"markdown": "text/markdown; charset=utf-8",
+}
+
+// ShoppingListHandler is the interface for shopping list handlers.
+type ShoppingListHandler interface {
+	GenerateShoppingListHandler(ctx *gin.Context)
+	CreateShoppingListHandler(ctx *gin.Context)
+	GetAllShoppingListsHandler(ctx *gin.Context)
+	GetShoppingListByIdHandler(ctx *gin.Context)
+	DeleteShoppingListByIdHandler(ctx *gin.Context)
+	CheckShoppingListItemHandler(ctx *gin.Context)
+}
+
+// shoppingListHandler is the implementation of ShoppingListHandler.
+type shoppingListHandler struct {
+	service services.ShoppingListService
+	logger  *zap.Logger
+}
+
+// NewShoppingListHandler creates a new instance of ShoppingListHandler.
+func NewShoppingListHandler(service services.ShoppingListService) ShoppingListHandler {
+	return &shoppingListHandler{
+		service: service,
+		logger:  zap.L(),
+	}
+}
+
+// GenerateShoppingListHandler is the handler for previewing the shopping list of a set of recipes without saving it.
+func (h *shoppingListHandler) GenerateShoppingListHandler(ctx *gin.Context) {
+	var body dto.ShoppingListReqBody
+	err := ctx.ShouldBind(&body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	list, err := h.service.GenerateShoppingList(&body)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, list)
+}
+
+// CreateShoppingListHandler is the handler for generating and saving the shopping list of a set of recipes.
+func (h *shoppingListHandler) CreateShoppingListHandler(ctx *gin.Context) {
+	var body dto.ShoppingListReqBody
+	err := ctx.ShouldBind(&body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	list, err := h.service.CreateShoppingList(&body, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, list)
+}
+
+// GetAllShoppingListsHandler is the handler for getting the shopping lists of the current user.
+func (h *shoppingListHandler) GetAllShoppingListsHandler(ctx *gin.Context) {
+	var input dto.CommonQueryPage
+	err := ctx.ShouldBindQuery(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.PageSize == 0 {
+		input.PageSize = 10
+	}
+	userId := ctx.GetUint("userId")
+	pageLists, err := h.service.GetAllShoppingLists(&input, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, pageLists)
+}
+
+// GetShoppingListByIdHandler is the handler for getting a shopping list of the current user, optionally exported as plain text or Markdown.
+func (h *shoppingListHandler) GetShoppingListByIdHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var query dto.ShoppingListQuery
+	err = ctx.ShouldBindQuery(&query)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	if contentType, ok := shoppingListContentTypes[query.Format]; ok {
+		export, err := h.service.ExportShoppingList(&input, query.Format, userId)
+		if err != nil {
+			gormErrorResponseHandler(ctx, err)
+			return
+		}
+		ctx.Data(http.StatusOK, contentType, []byte(export))
+		return
+	}
+	list, err := h.service.GetShoppingListById(&input, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, list)
+}
+
+// DeleteShoppingListByIdHandler is the handler for deleting a shopping list of the current user.
+func (h *shoppingListHandler) DeleteShoppingListByIdHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	err = h.service.DeleteShoppingListById(&input, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// CheckShoppingListItemHandler is the handler for ticking off an item of a shopping list of the current user.
+func (h *shoppingListHandler) CheckShoppingListItemHandler(ctx *gin.Context) {
+	var input dto.ShoppingListItemPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var body dto.ShoppingListItemReqBody
+	err = ctx.ShouldBind(&body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	item, err := h.service.CheckShoppingListItem(&input, &body, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, item)
+}
@@ -0,0 +1,136 @@
+// Package handlers provides handlers for the HTTP API endpoints of the application.
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// StepHandler is the interface for recipe step handlers.
+type StepHandler interface {
+	GetAllStepsHandler(ctx *gin.Context)
+	CreateStepHandler(ctx *gin.Context)
+	UpdateStepHandler(ctx *gin.Context)
+	DeleteStepByIdHandler(ctx *gin.Context)
+	ReorderStepsHandler(ctx *gin.Context)
+}
+
+// stepHandler is the implementation of StepHandler.
+type stepHandler struct {
+	service services.StepService
+	logger  *zap.Logger
+}
+
+// NewStepHandler creates a new instance of StepHandler.
+func NewStepHandler(service services.StepService) StepHandler {
+	return &stepHandler{
+		service: service,
+		logger:  zap.L(),
+	}
+}
+
+// GetAllStepsHandler is the handler for getting the ordered steps of a recipe.
+func (h *stepHandler) GetAllStepsHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	steps, err := h.service.GetAllSteps(&input)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, steps)
+}
+
+// CreateStepHandler is the handler for adding a step to a recipe.
+func (h *stepHandler) CreateStepHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var body dto.RecipeStepReqBody
+	err = ctx.ShouldBind(&body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	step, err := h.service.CreateStep(&input, &body, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, step)
+}
+
+// UpdateStepHandler is the handler for editing a step of a recipe.
+func (h *stepHandler) UpdateStepHandler(ctx *gin.Context) {
+	var input dto.RecipeStepPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var body dto.RecipeStepReqBody
+	err = ctx.ShouldBind(&body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	step, err := h.service.UpdateStep(&input, &body, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, step)
+}
+
+// DeleteStepByIdHandler is the handler for deleting a step of a recipe.
+func (h *stepHandler) DeleteStepByIdHandler(ctx *gin.Context) {
+	var input dto.RecipeStepPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	err = h.service.DeleteStepById(&input, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ReorderStepsHandler is the handler for changing the order of the steps of a recipe.
+func (h *stepHandler) ReorderStepsHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var body dto.RecipeStepsOrderReqBody
+	err = ctx.ShouldBind(&body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	steps, err := h.service.ReorderSteps(&input, &body, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, steps)
+}
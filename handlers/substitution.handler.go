@@ -0,0 +1,112 @@
+// Package handlers provides handlers for the HTTP API endpoints of the application.
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SubstitutionHandler is the interface for ingredient substitution handlers.
+type SubstitutionHandler interface {
+	CreateSubstitutionHandler(ctx *gin.Context)
+	GetAllSubstitutionsHandler(ctx *gin.Context)
+	UpdateSubstitutionHandler(ctx *gin.Context)
+	DeleteSubstitutionHandler(ctx *gin.Context)
+}
+
+// substitutionHandler is the implementation of SubstitutionHandler.
+type substitutionHandler struct {
+	service services.SubstitutionService
+	logger  *zap.Logger
+}
+
+// NewSubstitutionHandler creates a new instance of SubstitutionHandler.
+func NewSubstitutionHandler(service services.SubstitutionService) SubstitutionHandler {
+	return &substitutionHandler{
+		service: service,
+		logger:  zap.L(),
+	}
+}
+
+// CreateSubstitutionHandler is the handler for adding a substitute to an ingredient.
+func (h *substitutionHandler) CreateSubstitutionHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var body dto.SubstitutionReqBody
+	err = ctx.ShouldBind(&body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	substitution, err := h.service.CreateSubstitution(&input, &body, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, substitution)
+}
+
+// GetAllSubstitutionsHandler is the handler for getting the substitutes of an ingredient.
+func (h *substitutionHandler) GetAllSubstitutionsHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	substitutions, err := h.service.GetAllSubstitutions(&input)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, substitutions)
+}
+
+// UpdateSubstitutionHandler is the handler for updating a substitution of an ingredient.
+func (h *substitutionHandler) UpdateSubstitutionHandler(ctx *gin.Context) {
+	var input dto.SubstitutionPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var body dto.SubstitutionUpdateReqBody
+	err = ctx.ShouldBind(&body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	substitution, err := h.service.UpdateSubstitution(&input, &body, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, substitution)
+}
+
+// DeleteSubstitutionHandler is the handler for removing a substitution of an ingredient.
+func (h *substitutionHandler) DeleteSubstitutionHandler(ctx *gin.Context) {
+	var input dto.SubstitutionPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	err = h.service.DeleteSubstitution(&input, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
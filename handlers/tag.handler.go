@@ -0,0 +1,162 @@
+// Package handlers provides handlers for the HTTP API endpoints of the application.
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// TagHandler is the interface for tag handlers.
+type TagHandler interface {
+	CreateTagHandler(ctx *gin.Context)
+	GetAllTagsHandler(ctx *gin.Context)
+	GetTagByIdHandler(ctx *gin.Context)
+	UpdateTagHandler(ctx *gin.Context)
+	DeleteTagByIdHandler(ctx *gin.Context)
+	AddTagToRecipeHandler(ctx *gin.Context)
+	DeleteTagFromRecipeHandler(ctx *gin.Context)
+}
+
+// tagHandler is the implementation of TagHandler.
+type tagHandler struct {
+	service services.TagService
+	logger  *zap.Logger
+}
+
+// NewTagHandler creates a new instance of TagHandler.
+func NewTagHandler(service services.TagService) TagHandler {
+	return &tagHandler{
+		service: service,
+		logger:  zap.L(),
+	}
+}
+
+// CreateTagHandler is the handler for creating a new tag.
+func (h *tagHandler) CreateTagHandler(ctx *gin.Context) {
+	var body dto.TagReqBody
+	err := ctx.ShouldBind(&body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	tag, err := h.service.CreateTag(&body, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, tag)
+}
+
+// GetAllTagsHandler is the handler for getting the tags with their usage count with pagination.
+func (h *tagHandler) GetAllTagsHandler(ctx *gin.Context) {
+	var query dto.TagQuery
+	err := ctx.ShouldBindQuery(&query)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 10
+	}
+	pageTags, err := h.service.GetAllTags(&query)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, pageTags)
+}
+
+// GetTagByIdHandler is the handler for getting a tag by ID.
+func (h *tagHandler) GetTagByIdHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	tag, err := h.service.GetTagById(&input)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, tag)
+}
+
+// UpdateTagHandler is the handler for renaming a tag or changing its category.
+func (h *tagHandler) UpdateTagHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var body dto.TagReqBody
+	err = ctx.ShouldBind(&body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	tag, err := h.service.UpdateTag(&input, &body, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, tag)
+}
+
+// DeleteTagByIdHandler is the handler for deleting a tag, it's removed from the recipes.
+func (h *tagHandler) DeleteTagByIdHandler(ctx *gin.Context) {
+	var input dto.CommonIdPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	err = h.service.DeleteTagById(&input, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// AddTagToRecipeHandler is the handler for attaching a tag to a recipe.
+func (h *tagHandler) AddTagToRecipeHandler(ctx *gin.Context) {
+	var input dto.RecipeTagPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	recipe, err := h.service.AddTagToRecipe(&input, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, recipe)
+}
+
+// DeleteTagFromRecipeHandler is the handler for detaching a tag from a recipe.
+func (h *tagHandler) DeleteTagFromRecipeHandler(ctx *gin.Context) {
+	var input dto.RecipeTagPathUri
+	err := ctx.ShouldBindUri(&input)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userId := ctx.GetUint("userId")
+	err = h.service.DeleteTagFromRecipe(&input, userId)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
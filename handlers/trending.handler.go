@@ -0,0 +1,49 @@
+// Package handlers provides handlers for the HTTP API endpoints of the application.
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// TrendingHandler is the interface for trending recipe handlers.
+type TrendingHandler interface {
+	GetTrendingRecipesHandler(ctx *gin.Context)
+}
+
+// trendingHandler is the implementation of TrendingHandler.
+type trendingHandler struct {
+	service services.TrendingService
+	logger  *zap.Logger
+}
+
+// NewTrendingHandler creates a new instance of TrendingHandler.
+func NewTrendingHandler(service services.TrendingService) TrendingHandler {
+	return &trendingHandler{
+		service: service,
+		logger:  zap.L(),
+	}
+}
+
+// GetTrendingRecipesHandler is the handler for getting the trending recipes over a time window with pagination.
+func (h *trendingHandler) GetTrendingRecipesHandler(ctx *gin.Context) {
+	var query dto.TrendingQuery
+	err := ctx.ShouldBindQuery(&query)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if query.PageSize == 0 {
+		query.PageSize = 10
+	}
+	pageRecipes, err := h.service.GetTrendingRecipes(&query)
+	if err != nil {
+		gormErrorResponseHandler(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, pageRecipes)
+}
@@ -0,0 +1,41 @@
+// This file extracts the JSON-LD documents embedded in the HTML pages
+package importer
+
+import (
+	"bytes"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// extractJsonLd returns the content of the script elements of type application/ld+json in the order of the page
+func extractJsonLd(page []byte) [][]byte {
+	var documents [][]byte
+	tokenizer := html.NewTokenizer(bytes.NewReader(page))
+	inJsonLd := false
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return documents
+		case html.StartTagToken:
+			name, hasAttr := tokenizer.TagName()
+			inJsonLd = false
+			if string(name) != "script" {
+				continue
+			}
+			for hasAttr {
+				var key, value []byte
+				key, value, hasAttr = tokenizer.TagAttr()
+				if string(key) == "type" && strings.EqualFold(strings.TrimSpace(string(value)), "application/ld+json") {
+					inJsonLd = true
+				}
+			}
+		case html.TextToken:
+			if inJsonLd {
+				documents = append(documents, bytes.TrimSpace(bytes.Clone(tokenizer.Text())))
+			}
+		case html.EndTagToken:
+			inJsonLd = false
+		}
+	}
+}
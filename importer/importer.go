@@ -0,0 +1,217 @@
+// Package importer reads the recipes published with the schema.org Recipe vocabulary in JSON-LD.
+// The document is either the JSON-LD itself or an HTML page embedding it in a script element, the way the food blogs
+// publish their recipes for the search engines. The recipe is read without any database access, matching its ingredients
+// with the known ones is left to the caller.
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultServings is the number of servings of an imported recipe without a readable yield
+const DefaultServings = 4
+
+// The errors returned when a document can't be imported
+var (
+	ErrDocumentTooLarge    = fmt.Errorf("the document exceeds the maximum size, document too large")
+	ErrRecipeNotFound      = fmt.Errorf("the document doesn't contain a schema.org Recipe, recipe not acceptable")
+	ErrRecipeNotAcceptable = fmt.Errorf("the imported recipe must have a name and at least one ingredient, recipe not acceptable")
+)
+
+// Recipe is a recipe read from a schema.org Recipe
+type Recipe struct {
+	Name        string            // the name of the recipe
+	Description string            // the description of the recipe, the HTML tags are removed
+	Servings    uint              // the number of servings read from the yield
+	Ingredients []*IngredientLine // the parsed ingredient lines in their order
+	Steps       []string          // the instructions in their order, the sections are flattened
+	Warnings    []string          // the parts of the recipe which couldn't be imported as is
+}
+
+// The patterns used to clean the texts of the recipe
+var (
+	htmlTagPattern    = regexp.MustCompile(`<[^>]*>`)
+	spacesPattern     = regexp.MustCompile(`\s+`)
+	firstIntegerRegex = regexp.MustCompile(`\d+`)
+)
+
+// ReadRecipe reads a document of at most maxSize bytes and returns the first schema.org Recipe it contains.
+// It returns ErrDocumentTooLarge when the document is bigger than maxSize bytes, ErrRecipeNotFound when it contains
+// no recipe and ErrRecipeNotAcceptable when the recipe has no name or no ingredient.
+func ReadRecipe(content io.Reader, maxSize int64) (*Recipe, error) {
+	data, err := io.ReadAll(io.LimitReader(content, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, ErrDocumentTooLarge
+	}
+	var documents [][]byte
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("{")) || bytes.HasPrefix(trimmed, []byte("[")) {
+		documents = [][]byte{trimmed}
+	} else {
+		documents = extractJsonLd(data)
+	}
+	for _, document := range documents {
+		var node interface{}
+		if json.Unmarshal(document, &node) != nil {
+			continue
+		}
+		if recipeNode := findRecipeNode(node); recipeNode != nil {
+			return convertRecipeNode(recipeNode)
+		}
+	}
+	return nil, ErrRecipeNotFound
+}
+
+// findRecipeNode returns the first node typed Recipe, the nodes of the graphs, the lists and the main entities are searched
+func findRecipeNode(node interface{}) map[string]interface{} {
+	switch v := node.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if recipe := findRecipeNode(item); recipe != nil {
+				return recipe
+			}
+		}
+	case map[string]interface{}:
+		if hasType(v, "Recipe") {
+			return v
+		}
+		for _, key := range []string{"@graph", "mainEntity"} {
+			if recipe := findRecipeNode(v[key]); recipe != nil {
+				return recipe
+			}
+		}
+	}
+	return nil
+}
+
+// hasType returns true when the node has the schema.org type, the type can be a list of types
+func hasType(node map[string]interface{}, name string) bool {
+	for _, t := range textValues(node["@type"]) {
+		if t == name || t == "http://schema.org/"+name || t == "https://schema.org/"+name {
+			return true
+		}
+	}
+	return false
+}
+
+// convertRecipeNode converts the schema.org Recipe to a Recipe
+func convertRecipeNode(node map[string]interface{}) (*Recipe, error) {
+	recipe := &Recipe{
+		Name:        truncate(firstText(node["name"]), maxTitleLength),
+		Description: firstText(node["description"]),
+		Warnings:    []string{},
+	}
+	lines := textValues(node["recipeIngredient"])
+	if len(lines) == 0 {
+		// the former name of the property is still used by some sites
+		lines = textValues(node["ingredients"])
+	}
+	for _, line := range lines {
+		if ingredient := ParseIngredientLine(line); ingredient != nil {
+			recipe.Ingredients = append(recipe.Ingredients, ingredient)
+		} else {
+			recipe.Warnings = append(recipe.Warnings, fmt.Sprintf("the ingredient line %q has no ingredient name, it's ignored", line))
+		}
+	}
+	if recipe.Name == "" || len(recipe.Ingredients) == 0 {
+		return nil, ErrRecipeNotAcceptable
+	}
+	if recipe.Description == "" {
+		recipe.Description = recipe.Name
+		recipe.Warnings = append(recipe.Warnings, "the recipe has no description, its name is used")
+	}
+	recipe.Steps = instructionTexts(node["recipeInstructions"])
+	if len(recipe.Steps) == 0 {
+		recipe.Warnings = append(recipe.Warnings, "the recipe has no instructions")
+	}
+	recipe.Servings = servings(node["recipeYield"])
+	if recipe.Servings == 0 {
+		recipe.Servings = DefaultServings
+		recipe.Warnings = append(recipe.Warnings, fmt.Sprintf("the yield of the recipe isn't a number of servings, %d servings are used", DefaultServings))
+	}
+	return recipe, nil
+}
+
+// instructionTexts returns the texts of the instructions: a text with one step per line, a list of texts,
+// HowToStep or HowToSection whose steps are flattened in their order
+func instructionTexts(node interface{}) []string {
+	var steps []string
+	switch v := node.(type) {
+	case string:
+		for _, line := range strings.Split(htmlTagPattern.ReplaceAllString(v, "\n"), "\n") {
+			if text := cleanText(line); text != "" {
+				steps = append(steps, text)
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			steps = append(steps, instructionTexts(item)...)
+		}
+	case map[string]interface{}:
+		if elements, ok := v["itemListElement"]; ok {
+			return instructionTexts(elements)
+		}
+		text := firstText(v["text"])
+		if text == "" {
+			text = firstText(v["name"])
+		}
+		if text != "" {
+			steps = append(steps, text)
+		}
+	}
+	return steps
+}
+
+// servings returns the first number of servings of the yield, 0 when it has none or an unrealistic one
+func servings(node interface{}) uint {
+	for _, text := range textValues(node) {
+		number, err := strconv.Atoi(firstIntegerRegex.FindString(text))
+		if err == nil && number > 0 && number <= 1000 {
+			return uint(number)
+		}
+	}
+	return 0
+}
+
+// textValues returns the cleaned texts of a value which can be a text, a number or a list of them
+func textValues(node interface{}) []string {
+	var texts []string
+	switch v := node.(type) {
+	case string:
+		if text := cleanText(v); text != "" {
+			texts = append(texts, text)
+		}
+	case float64:
+		texts = append(texts, strconv.FormatFloat(v, 'f', -1, 64))
+	case []interface{}:
+		for _, item := range v {
+			texts = append(texts, textValues(item)...)
+		}
+	}
+	return texts
+}
+
+// firstText returns the first cleaned text of a value, the empty string when it has none
+func firstText(node interface{}) string {
+	texts := textValues(node)
+	if len(texts) == 0 {
+		return ""
+	}
+	return texts[0]
+}
+
+// cleanText removes the HTML tags and entities and the repeated spaces of a text
+func cleanText(text string) string {
+	text = html.UnescapeString(htmlTagPattern.ReplaceAllString(text, " "))
+	return strings.TrimSpace(spacesPattern.ReplaceAllString(text, " "))
+}
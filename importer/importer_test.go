@@ -0,0 +1,105 @@
+package importer_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/clementb49/welsh_academy/importer"
+	"github.com/stretchr/testify/assert"
+)
+
+const welshRarebitJsonLd = `{
+	"@context": "https://schema.org",
+	"@graph": [
+		{"@type": "WebPage", "name": "Welsh rarebit - the blog"},
+		{
+			"@type": ["Recipe", "NewsArticle"],
+			"name": "Welsh rarebit",
+			"description": "A <b>cheesy</b> toast &amp; ale sauce",
+			"recipeYield": ["2", "2 servings"],
+			"recipeIngredient": ["200g mature cheddar, grated", "1½ tbsp butter", "2 slices of bread", "Worcestershire sauce (optional)", "100"],
+			"recipeInstructions": [
+				{"@type": "HowToSection", "name": "Sauce", "itemListElement": [
+					{"@type": "HowToStep", "text": "Melt the butter."},
+					{"@type": "HowToStep", "text": "Stir in the cheddar."}
+				]},
+				{"@type": "HowToStep", "name": "Grill the toasts."}
+			]
+		}
+	]
+}`
+
+func TestReadRecipe(t *testing.T) {
+	recipe, err := importer.ReadRecipe(strings.NewReader(welshRarebitJsonLd), 1<<20)
+	assert.NoError(t, err)
+	assert.Equal(t, "Welsh rarebit", recipe.Name)
+	assert.Equal(t, "A cheesy toast & ale sauce", recipe.Description)
+	assert.Equal(t, uint(2), recipe.Servings)
+	assert.Equal(t, []string{"Melt the butter.", "Stir in the cheddar.", "Grill the toasts."}, recipe.Steps)
+	assert.Len(t, recipe.Ingredients, 4)
+	assert.Equal(t, "mature cheddar", recipe.Ingredients[0].Name)
+	assert.True(t, recipe.Ingredients[3].Optional)
+	// the line without ingredient name is ignored with a warning
+	assert.Len(t, recipe.Warnings, 1)
+	// test happy path: the recipe is embedded in an HTML page with a text yield and instructions
+	page := `<html><head>
+	<script type="application/ld+json">{"@type": "Organization", "name": "The blog"}</script>
+	<script type="application/ld+json">{"@type": "Recipe", "name": "Cawl", "recipeYield": "serves many",
+		"recipeIngredient": ["1kg lamb neck"], "recipeInstructions": "Brown the lamb.\nAdd the leeks."}</script>
+	</head><body><h1>Cawl</h1></body></html>`
+	recipe, err = importer.ReadRecipe(strings.NewReader(page), 1<<20)
+	assert.NoError(t, err)
+	assert.Equal(t, "Cawl", recipe.Name)
+	assert.Equal(t, "Cawl", recipe.Description)
+	assert.Equal(t, uint(importer.DefaultServings), recipe.Servings)
+	assert.Equal(t, []string{"Brown the lamb.", "Add the leeks."}, recipe.Steps)
+	assert.Len(t, recipe.Warnings, 2)
+	// test error: the document is bigger than the maximum size
+	_, err = importer.ReadRecipe(strings.NewReader(welshRarebitJsonLd), 10)
+	assert.ErrorIs(t, err, importer.ErrDocumentTooLarge)
+	// test error: the document doesn't contain a recipe
+	_, err = importer.ReadRecipe(strings.NewReader("<html><body>welsh rarebit</body></html>"), 1<<20)
+	assert.ErrorIs(t, err, importer.ErrRecipeNotFound)
+	// test error: the recipe has no ingredient
+	_, err = importer.ReadRecipe(strings.NewReader(`{"@type": "Recipe", "name": "Toast"}`), 1<<20)
+	assert.ErrorIs(t, err, importer.ErrRecipeNotAcceptable)
+}
+
+func TestParseIngredientLine(t *testing.T) {
+	line := importer.ParseIngredientLine("200g mature cheddar, grated")
+	assert.Equal(t, 200.0, line.Quantity)
+	assert.Equal(t, "g", line.Unit)
+	assert.Equal(t, "mature cheddar", line.Name)
+	assert.Equal(t, "grated", line.Note)
+	line = importer.ParseIngredientLine("1 ½ cups of milk (warm)")
+	assert.Equal(t, 1.5, line.Quantity)
+	assert.Equal(t, "cup", line.Unit)
+	assert.Equal(t, "milk", line.Name)
+	assert.Equal(t, "warm", line.Note)
+	line = importer.ParseIngredientLine("2 fl oz ale")
+	assert.Equal(t, 2.0, line.Quantity)
+	assert.Equal(t, "fl_oz", line.Unit)
+	assert.Equal(t, "ale", line.Name)
+	// the lower bound of a range is kept and the pieces have no unit
+	line = importer.ParseIngredientLine("2-3 eggs")
+	assert.Equal(t, 2.0, line.Quantity)
+	assert.Equal(t, "", line.Unit)
+	assert.Equal(t, "eggs", line.Name)
+	line = importer.ParseIngredientLine("a pinch of cayenne pepper, optional")
+	assert.Equal(t, 1.0, line.Quantity)
+	assert.Equal(t, "pinch", line.Unit)
+	assert.Equal(t, "cayenne pepper", line.Name)
+	assert.True(t, line.Optional)
+	line = importer.ParseIngredientLine("Salt to taste")
+	assert.Equal(t, 0.0, line.Quantity)
+	assert.Equal(t, "Salt", line.Name)
+	assert.Equal(t, "to taste", line.Note)
+	// test error: the line has no ingredient name
+	assert.Nil(t, importer.ParseIngredientLine("3/4"))
+}
+
+func TestNameKeys(t *testing.T) {
+	assert.Equal(t, []string{"eggs", "egg"}, importer.NameKeys("Eggs"))
+	assert.Equal(t, []string{"cherries", "cherry"}, importer.NameKeys("cherries"))
+	assert.Equal(t, []string{"leek", "leeks"}, importer.NameKeys(" leek "))
+}
@@ -0,0 +1,185 @@
+// This file parses the free text ingredient lines of the imported recipes in quantity, unit, name and note
+package importer
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/clementb49/welsh_academy/units"
+)
+
+// The maximum lengths of the title of a recipe, the name of an ingredient and the note of an ingredient line in the database
+const (
+	maxTitleLength = 200
+	maxNameLength  = 100
+	maxNoteLength  = 200
+)
+
+// IngredientLine is an ingredient line of an imported recipe
+type IngredientLine struct {
+	Text     string  // the line as published
+	Quantity float64 // the quantity, 0 when the line has none (e.g. salt to taste)
+	Unit     string  // the symbol of the known unit of the quantity, empty for a number of pieces
+	Name     string  // the name of the ingredient
+	Note     string  // the preparation note written in parentheses or after a comma
+	Optional bool    // the line is marked as optional
+}
+
+// The unicode vulgar fractions used in the quantities
+var vulgarFractions = strings.NewReplacer(
+	"¼", " 1/4", "½", " 1/2", "¾", " 3/4", "⅓", " 1/3", "⅔", " 2/3", "⅛", " 1/8", "⅜", " 3/8", "⅝", " 5/8", "⅞", " 7/8", "⁄", "/",
+)
+
+// The patterns used to split the ingredient lines
+var (
+	parenthesesPattern = regexp.MustCompile(`\(([^)]*)\)`)
+	optionalPattern    = regexp.MustCompile(`(?i)(^|[\s,(])optional([\s,)]|$)`)
+	gluedUnitPattern   = regexp.MustCompile(`^(\d+(?:[.,]\d+)?)([a-zA-Z]+)$`)
+	rangePattern       = regexp.MustCompile(`^(\d+(?:[.,]\d+)?(?:/\d+)?)[-–](\d+(?:[.,]\d+)?(?:/\d+)?)$`)
+)
+
+// ParseIngredientLine splits an ingredient line like "200g mature cheddar, grated" in quantity, unit, name and note.
+// The quantity can be written with decimals, fractions, unicode fractions or a range whose lower bound is kept.
+// It returns nil when the line has no ingredient name.
+func ParseIngredientLine(text string) *IngredientLine {
+	line := &IngredientLine{Text: cleanText(text)}
+	main := line.Text
+	if optionalPattern.MatchString(main) {
+		line.Optional = true
+		main = optionalPattern.ReplaceAllString(main, "$1$2")
+	}
+	var notes []string
+	for _, match := range parenthesesPattern.FindAllStringSubmatch(main, -1) {
+		notes = append(notes, match[1])
+	}
+	main = parenthesesPattern.ReplaceAllString(main, " ")
+	if before, after, found := strings.Cut(main, ","); found {
+		main = before
+		notes = append([]string{after}, notes...)
+	}
+	tokens := strings.Fields(vulgarFractions.Replace(main))
+	line.Quantity, tokens = parseQuantity(tokens)
+	line.Unit, tokens = parseUnit(tokens)
+	if len(tokens) > 1 && strings.EqualFold(tokens[0], "of") {
+		tokens = tokens[1:]
+	}
+	name := strings.Join(tokens, " ")
+	if strings.HasSuffix(strings.ToLower(name), " to taste") {
+		name = strings.TrimSpace(name[:len(name)-len(" to taste")])
+		notes = append(notes, "to taste")
+	}
+	line.Name = truncate(name, maxNameLength)
+	if line.Name == "" {
+		return nil
+	}
+	var cleanedNotes []string
+	for _, note := range notes {
+		if note = strings.Trim(cleanText(note), " ,;"); note != "" {
+			cleanedNotes = append(cleanedNotes, note)
+		}
+	}
+	line.Note = truncate(strings.Join(cleanedNotes, ", "), maxNoteLength)
+	return line
+}
+
+// parseQuantity returns the quantity written at the start of the tokens and the remaining tokens,
+// a mixed number like "1 1/2" is added up and only the lower bound of a range like "2-3" or "2 to 3" is kept
+func parseQuantity(tokens []string) (float64, []string) {
+	if len(tokens) == 0 {
+		return 0, tokens
+	}
+	if match := gluedUnitPattern.FindStringSubmatch(tokens[0]); match != nil {
+		tokens = append([]string{match[1], match[2]}, tokens[1:]...)
+	}
+	if match := rangePattern.FindStringSubmatch(tokens[0]); match != nil {
+		tokens = append([]string{match[1]}, tokens[1:]...)
+	}
+	if len(tokens) > 1 && (strings.EqualFold(tokens[0], "a") || strings.EqualFold(tokens[0], "an")) {
+		return 1, tokens[1:]
+	}
+	quantity, ok := parseNumber(tokens[0])
+	if !ok {
+		return 0, tokens
+	}
+	tokens = tokens[1:]
+	if len(tokens) > 0 && strings.Contains(tokens[0], "/") {
+		if fraction, ok := parseNumber(tokens[0]); ok {
+			quantity += fraction
+			tokens = tokens[1:]
+		}
+	}
+	if len(tokens) > 1 && (tokens[0] == "-" || tokens[0] == "–" || strings.EqualFold(tokens[0], "to")) {
+		if _, ok := parseNumber(tokens[1]); ok {
+			tokens = tokens[2:]
+		}
+	}
+	return quantity, tokens
+}
+
+// parseNumber parses an integer, a decimal with a dot or a comma, or a fraction
+func parseNumber(token string) (float64, bool) {
+	if numerator, denominator, found := strings.Cut(token, "/"); found {
+		n, err := strconv.ParseFloat(numerator, 64)
+		if err != nil {
+			return 0, false
+		}
+		d, err := strconv.ParseFloat(denominator, 64)
+		if err != nil || d == 0 {
+			return 0, false
+		}
+		return n / d, true
+	}
+	number, err := strconv.ParseFloat(strings.Replace(token, ",", ".", 1), 64)
+	if err != nil || number < 0 {
+		return 0, false
+	}
+	return number, true
+}
+
+// parseUnit returns the symbol of the unit written at the start of the tokens and the remaining tokens.
+// The units of two words are tried first, the oven temperature units are ignored and a word is never
+// taken as unit when it's the last one because it's the ingredient (e.g. "2 eggs").
+func parseUnit(tokens []string) (string, []string) {
+	for words := 2; words >= 1; words-- {
+		if len(tokens) <= words {
+			continue
+		}
+		unit, ok := units.Lookup(strings.Join(tokens[:words], " "))
+		if !ok || unit.Kind == units.Temperature {
+			continue
+		}
+		if unit.Symbol == "piece" {
+			return "", tokens[words:]
+		}
+		return unit.Symbol, tokens[words:]
+	}
+	return "", tokens
+}
+
+// NameKeys returns the lower case forms of the ingredient name used to find it among the known ingredients,
+// the singular and the plural forms are included so "eggs" matches "egg"
+func NameKeys(name string) []string {
+	lower := strings.ToLower(strings.TrimSpace(name))
+	keys := []string{lower}
+	switch {
+	case strings.HasSuffix(lower, "ies"):
+		keys = append(keys, strings.TrimSuffix(lower, "ies")+"y")
+	case strings.HasSuffix(lower, "oes"):
+		keys = append(keys, strings.TrimSuffix(lower, "es"))
+	case strings.HasSuffix(lower, "s") && !strings.HasSuffix(lower, "ss"):
+		keys = append(keys, strings.TrimSuffix(lower, "s"))
+	default:
+		keys = append(keys, lower+"s")
+	}
+	return keys
+}
+
+// truncate shortens the text to at most length characters
+func truncate(text string, length int) string {
+	runes := []rune(text)
+	if len(runes) <= length {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:length]))
+}
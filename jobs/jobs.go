@@ -0,0 +1,43 @@
+// Package jobs provides the background jobs run periodically by the application.
+package jobs
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Every runs the job in a new goroutine at once then every interval, a failed run is logged and the job is run again
+// at the next tick. The job is disabled when the interval is zero or negative.
+func Every(name string, interval time.Duration, job func() error) {
+	logger := zap.L().With(zap.String("job", name))
+	if interval <= 0 {
+		logger.Info("Background job disabled")
+		return
+	}
+	logger.Info("Scheduling background job", zap.Duration("interval", interval))
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			run(logger, job)
+			<-ticker.C
+		}
+	}()
+}
+
+// run runs the job once and logs its duration, a panic is logged as an error so the next runs still happen
+func run(logger *zap.Logger, job func() error) {
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Background job panicked", zap.Error(fmt.Errorf("%v", r)))
+		}
+	}()
+	if err := job(); err != nil {
+		logger.Error("Background job failed", zap.Error(err), zap.Duration("duration", time.Since(start)))
+		return
+	}
+	logger.Debug("Background job done", zap.Duration("duration", time.Since(start)))
+}
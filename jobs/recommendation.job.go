@@ -0,0 +1,20 @@
+// Package jobs provides the background jobs run periodically by the application.
+package jobs
+
+import (
+	"time"
+
+	"github.com/clementb49/welsh_academy/repositories"
+	"github.com/clementb49/welsh_academy/services"
+	"gorm.io/gorm"
+)
+
+// InitRecommendationJob schedules the computation of the recipe recommendations from the favorites of the users
+func InitRecommendationJob(db *gorm.DB, interval time.Duration) {
+	// Create a new recommendation repository using the provided database instance
+	recommendationRepository := repositories.NewRecommendationRepository(db)
+	// Create a new recommendation service using the repository, the refresh doesn't build any response so it doesn't need a media storage
+	recommendationService := services.NewRecommendationService(recommendationRepository, nil)
+
+	Every("recommendations", interval, recommendationService.RefreshRecommendations)
+}
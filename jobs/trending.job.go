@@ -0,0 +1,20 @@
+// Package jobs provides the background jobs run periodically by the application.
+package jobs
+
+import (
+	"time"
+
+	"github.com/clementb49/welsh_academy/repositories"
+	"github.com/clementb49/welsh_academy/services"
+	"gorm.io/gorm"
+)
+
+// InitTrendingJob schedules the computation of the trending recipes from the recent favorites and views
+func InitTrendingJob(db *gorm.DB, interval time.Duration) {
+	// Create a new trending repository using the provided database instance
+	trendingRepository := repositories.NewTrendingRepository(db)
+	// Create a new trending service using the repository, the refresh doesn't build any response so it doesn't need a media storage
+	trendingService := services.NewTrendingService(trendingRepository, nil)
+
+	Every("trending", interval, trendingService.RefreshTrendingRecipes)
+}
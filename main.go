@@ -1,9 +1,12 @@
 package main
 
 import (
+	"net/url"
 	"time"
 
 	"github.com/clementb49/welsh_academy/config"
+	"github.com/clementb49/welsh_academy/jobs"
+	"github.com/clementb49/welsh_academy/media"
 	"github.com/clementb49/welsh_academy/middlewares"
 	"github.com/clementb49/welsh_academy/models"
 	"github.com/clementb49/welsh_academy/routes"
@@ -34,8 +37,14 @@ func main() {
 	dsn := waCfg.DbCfg.BuildDsn()
 	db := initGorm(logger, dsn)
 
+	// Set up the join tables with extra fields before using them.
+	setupJoinTables(db, logger)
 	// Migrate the database schema.
 	migrateDb(db, logger)
+	// Start the jobs running periodically in the background.
+	startBackgroundJobs(db, waCfg, logger)
+	// Store the uploaded images in the media directory.
+	localStorage := media.NewLocalStorage(waCfg.MediaDir, waCfg.MediaUrl)
 	logger.Info("Initializing HTTP server...")
 	// Create a new Gin HTTP server.
 	eng := gin.New()
@@ -44,6 +53,8 @@ func main() {
 	logger.Info("server initialized")
 	// Register the API routes.
 	registerApiRoutes(db, eng, logger)
+	// Serve the uploaded images.
+	registerMediaRoute(eng, localStorage, waCfg.MediaUrl, logger)
 	// Initialize the endless HTTP server with the Gin HTTP server.
 	srv := endless.NewServer(":8000", eng)
 	srv.ErrorLog = zap.NewStdLog(logger)
@@ -89,17 +100,44 @@ func initGorm(logger *zap.Logger, dsn string) *gorm.DB {
 	return db
 }
 
+// Set up the models used as join tables of many2many associations.
+func setupJoinTables(db *gorm.DB, logger *zap.Logger) {
+	// Use the favorite model as the join table of the favorites so the date they are added is recorded.
+	err := db.SetupJoinTable(&models.User{}, "FavRecipes", &models.FavoriteRecipe{})
+	if err != nil {
+		logger.Sugar().Fatalf("Unable to set up the favorites join table: %w", err)
+	}
+	err = db.SetupJoinTable(&models.Recipe{}, "LikedUser", &models.FavoriteRecipe{})
+	if err != nil {
+		logger.Sugar().Fatalf("Unable to set up the favorites join table: %w", err)
+	}
+}
+
 // Migrate the database schema.
 func migrateDb(db *gorm.DB, logger *zap.Logger) {
 	logger.Info("Begin database migration ...")
 	// Auto-migrate the database schema for the specified models.
-	err := db.AutoMigrate(&models.User{}, &models.Ingredient{}, &models.Recipe{})
+	err := db.AutoMigrate(&models.User{}, &models.Ingredient{}, &models.Recipe{}, &models.RecipeIngredient{}, &models.RecipeStep{}, &models.RecipeRevision{},
+		&models.IngredientNutrition{}, &models.IngredientPrice{}, &models.IngredientSubstitution{},
+		&models.ShoppingList{}, &models.ShoppingListItem{}, &models.MealPlan{}, &models.Review{}, &models.Comment{}, &models.Tag{},
+		&models.FavoriteRecipe{}, &models.RecipeView{}, &models.RecipeRecommendation{}, &models.RecipeTrend{}, &models.Collection{}, &models.CollectionRecipe{},
+		&models.RecipeImage{}, &models.IngredientImage{})
 	if err != nil {
 		logger.Sugar().Fatalf("The database migration encounter the folowing error: %w", err)
 	}
 	logger.Info("Database migration terminated successfully")
 }
 
+// Start the background jobs with the intervals of the configuration.
+func startBackgroundJobs(db *gorm.DB, waCfg *config.WaConfig, logger *zap.Logger) {
+	logger.Info("Starting background jobs ...")
+	// Compute the recipe recommendations from the favorites of the users
+	jobs.InitRecommendationJob(db, waCfg.RecommendationInterval)
+	// Compute the trending recipes from the recent favorites and views
+	jobs.InitTrendingJob(db, waCfg.TrendingInterval)
+	logger.Info("Background jobs started")
+}
+
 // register the API route in the gin framework
 func registerApiRoutes(db *gorm.DB, eng *gin.Engine, logger *zap.Logger) {
 	logger.Info("Registering API routes ...")
@@ -108,9 +146,40 @@ func registerApiRoutes(db *gorm.DB, eng *gin.Engine, logger *zap.Logger) {
 	authApiRouter := eng.Group("/api/v1")
 	// Apply an authentication middleware to the authenticated API router
 	authApiRouter.Use(middlewares.Auth())
-	// Register the API routes for ingredients, recipes, and users
+	// Register the API routes.
 	routes.InitIngredientRoute(db, unauthApiRouter, authApiRouter)
+	routes.InitNutritionRoute(db, unauthApiRouter, authApiRouter)
+	routes.InitPriceRoute(db, unauthApiRouter, authApiRouter)
+	routes.InitSubstitutionRoute(db, unauthApiRouter, authApiRouter)
+	routes.InitPantryRoute(db, unauthApiRouter, authApiRouter)
+	routes.InitRecommendationRoute(db, unauthApiRouter, authApiRouter)
+	routes.InitTrendingRoute(db, unauthApiRouter, authApiRouter)
 	routes.InitRecipeRoute(db, unauthApiRouter, authApiRouter)
+	routes.InitStepRoute(db, unauthApiRouter, authApiRouter)
+	routes.InitRecipeRevisionRoute(db, unauthApiRouter, authApiRouter)
+	routes.InitImageRoute(db, unauthApiRouter, authApiRouter)
+	routes.InitImportRoute(db, unauthApiRouter, authApiRouter)
+	routes.InitTagRoute(db, unauthApiRouter, authApiRouter)
+	routes.InitReviewRoute(db, unauthApiRouter, authApiRouter)
+	routes.InitCommentRoute(db, unauthApiRouter, authApiRouter)
+	routes.InitCollectionRoute(db, unauthApiRouter, authApiRouter)
+	routes.InitShoppingListRoute(db, unauthApiRouter, authApiRouter)
+	routes.InitMealPlanRoute(db, unauthApiRouter, authApiRouter)
 	routes.InitUserRoutes(db, unauthApiRouter, authApiRouter)
 	logger.Info("API routes registered")
 }
+
+// Serve the files of the local storage at the path of the media URL, the directory listing is disabled.
+// The files aren't served when the media URL is on another host.
+func registerMediaRoute(eng *gin.Engine, storage *media.LocalStorage, mediaUrl string, logger *zap.Logger) {
+	mediaPath, err := url.Parse(mediaUrl)
+	if err != nil {
+		logger.Sugar().Fatalf("Invalid media URL %s: %w", mediaUrl, err)
+	}
+	if mediaPath.Host != "" {
+		logger.Info("Uploaded images served by another host", zap.String("url", mediaUrl))
+		return
+	}
+	eng.Static(mediaPath.Path, storage.Dir())
+	logger.Info("Uploaded images served", zap.String("path", mediaPath.Path), zap.String("dir", storage.Dir()))
+}
@@ -0,0 +1,150 @@
+// This file validates the uploaded images and generates their thumbnails with the standard library
+package media
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // registers the GIF decoder
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+)
+
+// ThumbnailSize is the maximum width and height of the thumbnails in pixels, the ratio of the image is kept
+const ThumbnailSize = 320
+
+// maxImagePixels is the maximum number of pixels of an image, it protects the server from the images decompressed in huge bitmaps
+const maxImagePixels = 16_000_000
+
+// The errors returned when an uploaded image is refused
+var (
+	ErrImageTooLarge      = fmt.Errorf("the image exceeds the maximum size, image too large")
+	ErrImageNotAcceptable = fmt.Errorf("the image must be a valid JPEG, PNG or GIF file, image not acceptable")
+)
+
+// The extensions of the files of the accepted image types
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// Image is an uploaded image with its thumbnail, the uploaded content is kept unchanged
+type Image struct {
+	Content              []byte // the uploaded file
+	ContentType          string // the MIME type detected from the content
+	Extension            string // the extension of the file of the content type
+	Width                int    // the width of the image in pixels
+	Height               int    // the height of the image in pixels
+	Thumbnail            []byte // the resized image, a JPEG for the JPEG images and a PNG for the others to keep their transparency
+	ThumbnailContentType string // the MIME type of the thumbnail
+	ThumbnailExtension   string // the extension of the file of the thumbnail
+}
+
+// ReadImage reads an uploaded image of at most maxSize bytes and generates its thumbnail.
+// The type is detected from the content, the declared type and the file name aren't trusted.
+// It returns ErrImageTooLarge when the image is bigger than maxSize bytes or maxImagePixels pixels,
+// and ErrImageNotAcceptable when it isn't a valid JPEG, PNG or GIF image.
+func ReadImage(content io.Reader, maxSize int64) (*Image, error) {
+	data, err := io.ReadAll(io.LimitReader(content, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, ErrImageTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	extension, ok := imageExtensions[contentType]
+	if !ok {
+		return nil, ErrImageNotAcceptable
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width <= 0 || config.Height <= 0 {
+		return nil, ErrImageNotAcceptable
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return nil, ErrImageTooLarge
+	}
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrImageNotAcceptable
+	}
+	img := &Image{
+		Content:     data,
+		ContentType: contentType,
+		Extension:   extension,
+		Width:       config.Width,
+		Height:      config.Height,
+	}
+	var thumbnail bytes.Buffer
+	resized := Resize(decoded, ThumbnailSize)
+	if contentType == "image/jpeg" {
+		err = jpeg.Encode(&thumbnail, resized, &jpeg.Options{Quality: 85})
+		img.ThumbnailContentType = "image/jpeg"
+	} else {
+		err = png.Encode(&thumbnail, resized)
+		img.ThumbnailContentType = "image/png"
+	}
+	if err != nil {
+		return nil, err
+	}
+	img.Thumbnail = thumbnail.Bytes()
+	img.ThumbnailExtension = imageExtensions[img.ThumbnailContentType]
+	return img, nil
+}
+
+// Resize returns the image reduced to fit in a square of size pixels, the ratio is kept and the small images aren't enlarged.
+// Each pixel of the result is the average of the pixels it covers in the image, they're read from the image without copying it.
+func Resize(src image.Image, size int) *image.RGBA {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	dstWidth, dstHeight := width, height
+	if width > size || height > size {
+		if width >= height {
+			dstWidth, dstHeight = size, max(1, height*size/width)
+		} else {
+			dstWidth, dstHeight = max(1, width*size/height), size
+		}
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	if dstWidth == width && dstHeight == height {
+		draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+		return dst
+	}
+	for y := 0; y < dstHeight; y++ {
+		y0, y1 := y*height/dstHeight, max((y+1)*height/dstHeight, y*height/dstHeight+1)
+		for x := 0; x < dstWidth; x++ {
+			x0, x1 := x*width/dstWidth, max((x+1)*width/dstWidth, x*width/dstWidth+1)
+			var sum [4]uint64
+			for sy := bounds.Min.Y + y0; sy < bounds.Min.Y+y1; sy++ {
+				for sx := bounds.Min.X + x0; sx < bounds.Min.X+x1; sx++ {
+					r, g, b, a := src.At(sx, sy).RGBA()
+					sum[0] += uint64(r)
+					sum[1] += uint64(g)
+					sum[2] += uint64(b)
+					sum[3] += uint64(a)
+				}
+			}
+			count := uint64((y1 - y0) * (x1 - x0))
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(sum[0] / count >> 8),
+				G: uint8(sum[1] / count >> 8),
+				B: uint8(sum[2] / count >> 8),
+				A: uint8(sum[3] / count >> 8),
+			})
+		}
+	}
+	return dst
+}
+
+// max returns the greatest of the two integers
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,94 @@
+// This file implements the storage keeping the media files in a local directory
+package media
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrInvalidPath is returned when a path leaves the directory of the storage
+var ErrInvalidPath = fmt.Errorf("the path must be relative to the storage, invalid path")
+
+// LocalStorage keeps the media files in a directory of the local filesystem, the directory is served at the base URL
+type LocalStorage struct {
+	dir     string
+	baseUrl string
+}
+
+// NewLocalStorage returns a storage writing in the directory, the directory is created with the first file
+func NewLocalStorage(dir string, baseUrl string) *LocalStorage {
+	return &LocalStorage{
+		dir:     dir,
+		baseUrl: strings.TrimSuffix(baseUrl, "/"),
+	}
+}
+
+// Dir returns the directory of the files
+func (s *LocalStorage) Dir() string {
+	return s.dir
+}
+
+// Save writes the file in a temporary file then renames it, so the file is never served partially written
+func (s *LocalStorage) Save(path string, content io.Reader) error {
+	name, err := s.localPath(path)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(name), 0o755)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(tmp, content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0o644)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), name)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// Delete removes the file, nothing is done when it doesn't exist
+func (s *LocalStorage) Delete(path string) error {
+	name, err := s.localPath(path)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Url returns the URL of the file under the base URL, the empty path gives an empty URL
+func (s *LocalStorage) Url(path string) string {
+	if path == "" {
+		return ""
+	}
+	return s.baseUrl + "/" + path
+}
+
+// localPath returns the name of the file in the directory, the paths leaving the directory are refused
+func (s *LocalStorage) localPath(path string) (string, error) {
+	name := filepath.FromSlash(path)
+	if !filepath.IsLocal(name) {
+		return "", ErrInvalidPath
+	}
+	return filepath.Join(s.dir, name), nil
+}
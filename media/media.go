@@ -0,0 +1,13 @@
+// Package media stores the images uploaded by the users and prepares them to be served.
+// The files are written through a Storage identified by slash-separated paths, the local storage keeps them
+// in a directory served by the application.
+package media
+
+import "io"
+
+// Storage is the interface of the places where the media files are kept
+type Storage interface {
+	Save(path string, content io.Reader) error // writes the file, an existing file is replaced
+	Delete(path string) error                  // removes the file, nothing is done when it doesn't exist
+	Url(path string) string                    // returns the public URL of the file, the empty path gives an empty URL
+}
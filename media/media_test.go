@@ -0,0 +1,100 @@
+package media_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/clementb49/welsh_academy/media"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestImage(width, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	return img
+}
+
+func TestReadImage(t *testing.T) {
+	var content bytes.Buffer
+	assert.NoError(t, png.Encode(&content, newTestImage(800, 400)))
+	img, err := media.ReadImage(bytes.NewReader(content.Bytes()), 1<<20)
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", img.ContentType)
+	assert.Equal(t, ".png", img.Extension)
+	assert.Equal(t, 800, img.Width)
+	assert.Equal(t, 400, img.Height)
+	assert.Equal(t, content.Bytes(), img.Content)
+	thumbnail, err := png.Decode(bytes.NewReader(img.Thumbnail))
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, media.ThumbnailSize, media.ThumbnailSize/2), thumbnail.Bounds())
+	// test happy path: the thumbnail of a JPEG image is a JPEG image
+	content.Reset()
+	assert.NoError(t, jpeg.Encode(&content, newTestImage(300, 900), nil))
+	img, err = media.ReadImage(bytes.NewReader(content.Bytes()), 1<<20)
+	assert.NoError(t, err)
+	assert.Equal(t, "image/jpeg", img.ThumbnailContentType)
+	thumbnail, err = jpeg.Decode(bytes.NewReader(img.Thumbnail))
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 106, media.ThumbnailSize), thumbnail.Bounds())
+	// test error: the image is bigger than the maximum size
+	_, err = media.ReadImage(bytes.NewReader(content.Bytes()), int64(content.Len()-1))
+	assert.ErrorIs(t, err, media.ErrImageTooLarge)
+	// test error: the file isn't an image
+	_, err = media.ReadImage(strings.NewReader("<html><body>welsh rarebit</body></html>"), 1<<20)
+	assert.ErrorIs(t, err, media.ErrImageNotAcceptable)
+	// test error: the image is truncated
+	_, err = media.ReadImage(bytes.NewReader(content.Bytes()[:100]), 1<<20)
+	assert.ErrorIs(t, err, media.ErrImageNotAcceptable)
+}
+
+func TestResize(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x++ {
+		src.Set(x, 0, color.RGBA{R: 200, A: 255})
+		src.Set(x, 1, color.RGBA{B: 100, A: 255})
+	}
+	// test happy path: each pixel is the average of the pixels it covers
+	dst := media.Resize(src, 2)
+	assert.Equal(t, image.Rect(0, 0, 2, 1), dst.Bounds())
+	assert.Equal(t, color.RGBA{R: 100, B: 50, A: 255}, dst.RGBAAt(1, 0))
+	// test happy path: the pixels are read in the bounds of the image
+	dst = media.Resize(src.SubImage(image.Rect(0, 1, 4, 2)), 2)
+	assert.Equal(t, image.Rect(0, 0, 2, 1), dst.Bounds())
+	assert.Equal(t, color.RGBA{B: 100, A: 255}, dst.RGBAAt(0, 0))
+	// test happy path: the small images aren't enlarged
+	dst = media.Resize(src, 10)
+	assert.Equal(t, src.Bounds(), dst.Bounds())
+}
+
+func TestLocalStorage(t *testing.T) {
+	dir := t.TempDir()
+	storage := media.NewLocalStorage(dir, "/uploads/")
+	err := storage.Save("recipes/1/photo.jpg", strings.NewReader("photo"))
+	assert.NoError(t, err)
+	content, err := os.ReadFile(filepath.Join(dir, "recipes", "1", "photo.jpg"))
+	assert.NoError(t, err)
+	assert.Equal(t, "photo", string(content))
+	assert.Equal(t, "/uploads/recipes/1/photo.jpg", storage.Url("recipes/1/photo.jpg"))
+	assert.Equal(t, "", storage.Url(""))
+	// test happy path: the deleted or missing files are ignored
+	assert.NoError(t, storage.Delete("recipes/1/photo.jpg"))
+	assert.NoError(t, storage.Delete("recipes/1/photo.jpg"))
+	_, err = os.Stat(filepath.Join(dir, "recipes", "1", "photo.jpg"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	// test error: the path leaves the directory
+	err = storage.Save("../photo.jpg", strings.NewReader("photo"))
+	assert.ErrorIs(t, err, media.ErrInvalidPath)
+	err = storage.Delete("/etc/passwd")
+	assert.ErrorIs(t, err, media.ErrInvalidPath)
+}
@@ -0,0 +1,53 @@
+// package which contains database model definition
+package models
+
+// Allergens is the set of the regulated allergens contained by an ingredient, each allergen is a bit of the set
+type Allergens uint32
+
+// The 14 allergens which must be declared on the food in the UK and in the EU
+const (
+	AllergenCelery Allergens = 1 << iota
+	AllergenGluten
+	AllergenCrustaceans
+	AllergenEggs
+	AllergenFish
+	AllergenLupin
+	AllergenMilk
+	AllergenMolluscs
+	AllergenMustard
+	AllergenNuts
+	AllergenPeanuts
+	AllergenSesame
+	AllergenSoya
+	AllergenSulphites
+)
+
+// AllergenNames lists the names of the allergens exchanged by the API in the order of their bit
+var AllergenNames = []string{
+	"celery", "gluten", "crustaceans", "eggs", "fish", "lupin", "milk",
+	"molluscs", "mustard", "nuts", "peanuts", "sesame", "soya", "sulphites",
+}
+
+// AllergensFromNames returns the set of the named allergens, the unknown names are ignored
+func AllergensFromNames(names []string) Allergens {
+	var allergens Allergens
+	for _, name := range names {
+		for i, allergenName := range AllergenNames {
+			if name == allergenName {
+				allergens |= 1 << i
+			}
+		}
+	}
+	return allergens
+}
+
+// Names returns the names of the allergens of the set in the order of AllergenNames
+func (a Allergens) Names() []string {
+	names := make([]string, 0)
+	for i, name := range AllergenNames {
+		if a&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
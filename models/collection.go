@@ -0,0 +1,30 @@
+// package which contains database model definition
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Struct to store a collection of recipes curated by a user, it embed the gorm model strut which define common fields.
+// A public collection is listed for every user, a private one is only readable by its owner or with its share link.
+type Collection struct {
+	gorm.Model
+	Title       string              `gorm:"type:varchar(100);not null"`                           // the title of the collection
+	Description string              `gorm:"not null;default:''"`                                  // the optional description of the collection
+	UserID      uint                `gorm:"not null;index"`                                       // the reference of the user who owns the collection
+	User        *User               `gorm:"foreignKey:UserID"`                                    // the user who owns the collection
+	Public      bool                `gorm:"not null;default:false;index"`                         // the collection is listed for every user
+	ShareToken  *string             `gorm:"type:varchar(64);uniqueIndex"`                         // the secret token of the share link, nil when the collection isn't shared
+	Recipes     []*CollectionRecipe `gorm:"foreignKey:CollectionID;constraint:OnDelete:CASCADE;"` // the ordered recipes of the collection
+}
+
+// Struct to store a recipe of a collection with its position in the collection
+type CollectionRecipe struct {
+	CollectionID uint      `gorm:"primaryKey"`          // the reference of the collection
+	RecipeID     uint      `gorm:"primaryKey;index"`    // the reference of the recipe
+	Recipe       *Recipe   `gorm:"foreignKey:RecipeID"` // the recipe in the collection
+	Position     uint      `gorm:"not null;default:0"`  // the position of the recipe in the collection starting at 1
+	CreatedAt    time.Time // the date the recipe was added to the collection
+}
@@ -0,0 +1,17 @@
+// package which contains database model definition
+package models
+
+import "gorm.io/gorm"
+
+// Struct to store a comment on a recipe, it embed the gorm model strut which define common fields.
+// A reply references its parent comment, the deleted comments are kept to not break the threads.
+type Comment struct {
+	gorm.Model
+	RecipeID uint       `gorm:"not null;index"`         // the reference of the commented recipe
+	UserID   uint       `gorm:"not null"`               // the reference of the user who wrote the comment
+	User     *User      `gorm:"foreignKey:UserID"`      // the user who wrote the comment
+	ParentID *uint      `gorm:"index"`                  // the reference of the answered comment, nil for a new thread
+	Replies  []*Comment `gorm:"foreignKey:ParentID"`    // the answers to the comment
+	Text     string     `gorm:"not null"`               // the text of the comment
+	Pinned   bool       `gorm:"not null;default:false"` // the author of the recipe pinned the comment at the top of the list
+}
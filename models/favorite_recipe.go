@@ -0,0 +1,17 @@
+// package which contains database model definition
+package models
+
+import "time"
+
+// Struct to store a favorite recipe of a user, it's the join table between the users and their favorite recipes
+// which records when the recipe was added to the favorites
+type FavoriteRecipe struct {
+	UserID    uint      `gorm:"primaryKey"` // the reference of the user who likes the recipe
+	RecipeID  uint      `gorm:"primaryKey"` // the reference of the favorite recipe
+	CreatedAt time.Time `gorm:"index"`      // the date the recipe was added to the favorites, empty for the favorites added before it was recorded
+}
+
+// TableName keeps the name of the former many2many join table so the existing favorites are preserved
+func (FavoriteRecipe) TableName() string {
+	return "wac_favorites_recipes"
+}
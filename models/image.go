@@ -0,0 +1,30 @@
+// package which contains database model definition
+package models
+
+import "time"
+
+// Struct to store the files of an uploaded image, it's embedded in the recipe and ingredient images
+type Image struct {
+	Path          string `gorm:"type:varchar(255);not null"` // the storage path of the uploaded file
+	ThumbnailPath string `gorm:"type:varchar(255);not null"` // the storage path of the thumbnail
+	ContentType   string `gorm:"type:varchar(50);not null"`  // the MIME type of the uploaded file
+	Width         int    `gorm:"not null;default:0"`         // the width of the image in pixels
+	Height        int    `gorm:"not null;default:0"`         // the height of the image in pixels
+	Size          int64  `gorm:"not null;default:0"`         // the size of the uploaded file in bytes
+}
+
+// Struct to store a photo of a recipe, a recipe with photos has one cover photo
+type RecipeImage struct {
+	ID        uint      `gorm:"primaryKey"`             // the identifier of the photo
+	RecipeID  uint      `gorm:"not null;index"`         // the reference of the recipe
+	Image     Image     `gorm:"embedded"`               // the files of the photo
+	Cover     bool      `gorm:"not null;default:false"` // the photo illustrates the recipe in the lists
+	CreatedAt time.Time // the date the photo was uploaded
+}
+
+// Struct to store the image of an ingredient, an ingredient has at most one image
+type IngredientImage struct {
+	IngredientID uint      `gorm:"primaryKey"` // the reference of the ingredient
+	Image        Image     `gorm:"embedded"`   // the files of the image
+	UpdatedAt    time.Time // the date the image was uploaded
+}
@@ -6,7 +6,18 @@ import "gorm.io/gorm"
 // Struct to store the ingredient, it embed the gorm model strut which define common fields
 type Ingredient struct {
 	gorm.Model
-	Name    string    `gorm:"type:varchar(100);uninque;not null"` // ingedient name
-	Type    string    `gorm:"type:varchar(100);not null"`         // ingredient type
-	Recipes []*Recipe `gorm:"many2many:ingredients_recipes;"`     // Reference of each recipe which use this ingredient
+	Name        string               `gorm:"type:varchar(100);uninque;not null"` // ingedient name
+	Type        string               `gorm:"type:varchar(100);not null"`         // ingredient type
+	Density     float64              `gorm:"not null;default:0"`                 // the density in g/ml used to convert the volumes to weights, 0 when it's unknown
+	Allergens   Allergens            `gorm:"not null;default:0"`                 // the regulated allergens contained by the ingredient
+	PieceWeight float64              `gorm:"not null;default:0"`                 // the weight in g of one piece used to weigh the counted quantities, 0 when it's unknown
+	Nutrition   *IngredientNutrition `gorm:"foreignKey:IngredientID"`            // the nutrition facts for 100 g, nil when they are unknown
+	Price       *IngredientPrice     `gorm:"foreignKey:IngredientID"`            // the latest reference price, nil when it's unknown or not loaded
+	Image       *IngredientImage     `gorm:"foreignKey:IngredientID"`            // the image of the ingredient, nil when it has none
+	Recipes     []*RecipeIngredient  `gorm:"foreignKey:IngredientID"`            // Reference of each recipe line which use this ingredient
+	// the substitutions replacing this ingredient and the ones where it's the substitute, only the bidirectional ones of the latter can replace it
+	Substitutes         []*IngredientSubstitution `gorm:"foreignKey:IngredientID"`
+	ReplacedIngredients []*IngredientSubstitution `gorm:"foreignKey:SubstituteID"`
+	// full text search document generated from the name, it's used to find the recipes using a searched ingredient
+	SearchVector string `gorm:"type:tsvector GENERATED ALWAYS AS (to_tsvector('english', coalesce(name, ''))) STORED;index:,type:gin;->:false"`
 }
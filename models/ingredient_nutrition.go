@@ -0,0 +1,17 @@
+// package which contains database model definition
+package models
+
+import "time"
+
+// Struct to store the nutrition facts of an ingredient for 100 g, an ingredient has at most one nutrition record
+type IngredientNutrition struct {
+	IngredientID uint      `gorm:"primaryKey"`         // the reference of the ingredient
+	EnergyKcal   float64   `gorm:"not null;default:0"` // the energy in kcal
+	Fat          float64   `gorm:"not null;default:0"` // the fat in g
+	Saturates    float64   `gorm:"not null;default:0"` // the saturated fat in g
+	Carbohydrate float64   `gorm:"not null;default:0"` // the carbohydrate in g
+	Sugars       float64   `gorm:"not null;default:0"` // the sugars in g
+	Protein      float64   `gorm:"not null;default:0"` // the protein in g
+	Salt         float64   `gorm:"not null;default:0"` // the salt in g
+	UpdatedAt    time.Time // the date of the last update of the nutrition facts
+}
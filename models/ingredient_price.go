@@ -0,0 +1,19 @@
+// package which contains database model definition
+package models
+
+import "time"
+
+// Struct to store a reference price of an ingredient, the prices are never updated to keep the price history.
+// The price is paid for the quantity of the unit, e.g. 8.50 GBP for 1 kg.
+type IngredientPrice struct {
+	ID           uint `gorm:"primarykey"`
+	CreatedAt    time.Time
+	IngredientID uint      `gorm:"not null;index:idx_price_ingredient_recorded,priority:1"`           // the reference of the priced ingredient
+	Price        float64   `gorm:"not null"`                                                          // the price paid for the quantity
+	Currency     string    `gorm:"type:varchar(3);not null"`                                          // the ISO 4217 code of the currency
+	Quantity     float64   `gorm:"not null;default:1"`                                                // the quantity bought for the price
+	Unit         string    `gorm:"type:varchar(20);not null;default:''"`                              // the unit of the quantity, empty for a number of pieces
+	RecordedAt   time.Time `gorm:"not null;index:idx_price_ingredient_recorded,priority:2,sort:desc"` // the date the price was seen
+	CreatorID    uint      `gorm:"not null"`                                                          // the reference of the user who recorded the price
+	Creator      *User     `gorm:"foreignKey:CreatorID"`
+}
@@ -0,0 +1,21 @@
+// package which contains database model definition
+package models
+
+import "time"
+
+// Struct to store that an ingredient can be replaced by another one in the recipes (e.g. caerphilly by lancashire).
+// The ratio is the quantity of substitute used for one unit of the ingredient, a bidirectional substitution also lets
+// the substitute be replaced by the ingredient with the inverse ratio. The substitutions are hard deleted.
+type IngredientSubstitution struct {
+	ID            uint `gorm:"primarykey"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	IngredientID  uint        `gorm:"not null;uniqueIndex:idx_substitution_pair,priority:1"`       // the reference of the replaced ingredient
+	Ingredient    *Ingredient `gorm:"foreignKey:IngredientID"`                                     // the replaced ingredient
+	SubstituteID  uint        `gorm:"not null;uniqueIndex:idx_substitution_pair,priority:2;index"` // the reference of the substitute
+	Substitute    *Ingredient `gorm:"foreignKey:SubstituteID"`                                     // the ingredient used instead
+	Ratio         float64     `gorm:"not null;default:1"`                                          // the quantity of substitute for one unit of the ingredient
+	Note          string      `gorm:"type:varchar(200);not null;default:''"`                       // how to use the substitute (e.g. add a pinch of salt)
+	Bidirectional bool        `gorm:"not null;default:false"`                                      // the substitute can also be replaced by the ingredient
+	CreatorID     uint        `gorm:"not null"`                                                    // the reference of the user who added the substitution
+}
@@ -0,0 +1,33 @@
+// package which contains database model definition
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DateFormat is the format of the dates without time exchanged by the API
+const DateFormat = "2006-01-02"
+
+// The meal slots of a day, in their order
+const (
+	MealSlotBreakfast = "breakfast"
+	MealSlotLunch     = "lunch"
+	MealSlotDinner    = "dinner"
+)
+
+// MealSlots lists the meal slots of a day in their order
+var MealSlots = []string{MealSlotBreakfast, MealSlotLunch, MealSlotDinner}
+
+// Struct to store a recipe planned by a user for a meal, it embed the gorm model strut which define common fields
+type MealPlan struct {
+	gorm.Model
+	UserID   uint      `gorm:"not null;index:idx_meal_plan_user_date"`           // the reference of the user who plans the meal
+	RecipeID uint      `gorm:"not null"`                                         // the reference of the planned recipe
+	Recipe   *Recipe   `gorm:"foreignKey:RecipeID"`                              // the planned recipe
+	Date     time.Time `gorm:"type:date;not null;index:idx_meal_plan_user_date"` // the day of the meal in the time zone of the application
+	Slot     string    `gorm:"type:varchar(10);not null"`                        // the meal slot: breakfast, lunch or dinner
+	Servings uint      `gorm:"not null;default:0"`                               // the number of servings to cook, 0 to use the servings of the recipe
+	Note     string    `gorm:"type:varchar(200);not null;default:''"`            // a free note (e.g. cook the day before)
+}
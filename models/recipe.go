@@ -6,10 +6,25 @@ import "gorm.io/gorm"
 // Struct to store the recipe, it embed the gorm model strut which define common fields
 type Recipe struct {
 	gorm.Model
-	Title       string        `gorm:"type:varchar(200);unique;not null"` // the recipe title
-	Description string        `gorm:"not null"`                          // text for the recipe description
-	Difficulty  uint8         `gorm:"not null;check:difficulty <= 5"`    // the defficuty of the recipe
-	Ingredients []*Ingredient `gorm:"many2many:ingredients_recipes;"`    // the ingredient required to make the recipe
-	LikedUser   []*User       `gorm:"many2many:favorites_recipes;"`      // the users who liked the recipe
-	AuthorID    uint64        // the refence of the user who created the recipe
+	Title               string              `gorm:"type:varchar(200);unique;not null"`     // the recipe title
+	Description         string              `gorm:"not null"`                              // text for the recipe description
+	Difficulty          uint8               `gorm:"not null;check:difficulty <= 5"`        // the defficuty of the recipe
+	Servings            uint                `gorm:"not null;default:4;check:servings > 0"` // the number of servings made with the ingredient quantities
+	OvenTemperature     *float64            // the optional oven temperature
+	OvenTemperatureUnit string              `gorm:"type:varchar(3);not null;default:''"` // the unit of the oven temperature: C, F or gas
+	Ingredients         []*RecipeIngredient `gorm:"foreignKey:RecipeID"`                 // the ingredient lines required to make the recipe
+	Steps               []*RecipeStep       `gorm:"foreignKey:RecipeID"`                 // the ordered preparation steps of the recipe
+	LikedUser           []*User             `gorm:"many2many:favorites_recipes;"`        // the users who liked the recipe
+	Tags                []*Tag              `gorm:"many2many:tags_recipes;"`             // the tags classifying the recipe
+	Images              []*RecipeImage      `gorm:"foreignKey:RecipeID"`                 // the photos of the recipe, the cover first
+	AuthorID            uint64              // the refence of the user who created the recipe
+	RatingAverage       float64             `gorm:"not null;default:0"`  // the average rating of the reviews, it's updated with the reviews
+	RatingCount         uint                `gorm:"not null;default:0"`  // the number of reviews, it's updated with the reviews
+	CommentCount        uint                `gorm:"not null;default:0"`  // the number of comments which aren't deleted, it's updated with the comments
+	ParentID            *uint               `gorm:"index"`               // the reference of the recipe this one was forked from
+	Parent              *Recipe             `gorm:"foreignKey:ParentID"` // the recipe this one was forked from
+	Forks               []*Recipe           `gorm:"foreignKey:ParentID"` // the recipes forked from this one
+	ForkCount           uint                `gorm:"not null;default:0"`  // the number of forks which aren't deleted, it's updated with the forks
+	// full text search document generated from the title and the description, it's only used by the search queries
+	SearchVector string `gorm:"type:tsvector GENERATED ALWAYS AS (setweight(to_tsvector('english', coalesce(title, '')), 'A') || setweight(to_tsvector('english', coalesce(description, '')), 'B')) STORED;index:,type:gin;->:false"`
 }
@@ -0,0 +1,20 @@
+// package which contains database model definition
+package models
+
+// Struct to store an ingredient line of a recipe, it's the join table between the recipes and the ingredients
+// which records how much of the ingredient is used by the recipe
+type RecipeIngredient struct {
+	RecipeID     uint        `gorm:"primaryKey"`                            // the reference of the recipe
+	IngredientID uint        `gorm:"primaryKey"`                            // the reference of the ingredient
+	Ingredient   *Ingredient `gorm:"foreignKey:IngredientID"`               // the ingredient used by the recipe
+	Position     uint        `gorm:"not null;default:0"`                    // the position of the line in the ingredient list of the recipe
+	Quantity     float64     `gorm:"not null;default:0"`                    // the quantity of ingredient, 0 when it's not specified (e.g. salt to taste)
+	Unit         string      `gorm:"type:varchar(20);not null;default:''"`  // the unit of the quantity, empty for a number of pieces
+	Note         string      `gorm:"type:varchar(200);not null;default:''"` // the preparation note (e.g. grated)
+	Optional     bool        `gorm:"not null;default:false"`                // the ingredient can be omitted
+}
+
+// TableName keeps the name of the former many2many join table so the existing ingredient lines are preserved
+func (RecipeIngredient) TableName() string {
+	return "wac_ingredients_recipes"
+}
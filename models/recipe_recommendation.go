@@ -0,0 +1,13 @@
+// package which contains database model definition
+package models
+
+import "time"
+
+// Struct to store a recipe recommended to a user from the favorites of the users with the same tastes.
+// The recommendations are computed periodically in the background, all of them are replaced at every computation.
+type RecipeRecommendation struct {
+	UserID    uint      `gorm:"primaryKey;autoIncrement:false"`       // the reference of the user the recipe is recommended to
+	RecipeID  uint      `gorm:"primaryKey;autoIncrement:false;index"` // the reference of the recommended recipe
+	Score     float64   `gorm:"not null"`                             // the relevance of the recommendation, the higher the better
+	CreatedAt time.Time // the date the recommendation was computed
+}
@@ -0,0 +1,67 @@
+// package which contains database model definition
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Struct to store an immutable snapshot of a recipe, a revision is saved each time the recipe is created or updated.
+// It doesn't embed the gorm model strut because a revision is never updated nor deleted.
+type RecipeRevision struct {
+	ID                  uint                      `gorm:"primarykey"`
+	CreatedAt           time.Time                 // the date the recipe was saved
+	RecipeID            uint                      `gorm:"not null;uniqueIndex:idx_revision_recipe_number"` // the reference of the recipe
+	Number              uint                      `gorm:"not null;uniqueIndex:idx_revision_recipe_number"` // the number of the revision in the history of the recipe, from 1
+	EditorID            uint                      `gorm:"not null"`                                        // the reference of the user who saved the recipe
+	Title               string                    `gorm:"type:varchar(200);not null"`                      // the recipe title
+	Description         string                    `gorm:"not null"`                                        // text for the recipe description
+	Difficulty          uint8                     `gorm:"not null"`                                        // the defficuty of the recipe
+	Servings            uint                      `gorm:"not null"`                                        // the number of servings made with the ingredient quantities
+	OvenTemperature     *float64                  // the optional oven temperature
+	OvenTemperatureUnit string                    `gorm:"type:varchar(3);not null;default:''"` // the unit of the oven temperature
+	Ingredients         RecipeRevisionIngredients `gorm:"type:jsonb;not null"`                 // the ingredient lines of the recipe in their order
+}
+
+// Struct to store an ingredient line in a recipe revision, the name of the ingredient is kept to read the revision
+// even when the ingredient is renamed
+type RecipeRevisionIngredient struct {
+	IngredientID uint    `json:"ingredient_id"`
+	Name         string  `json:"name"`
+	Quantity     float64 `json:"quantity"`
+	Unit         string  `json:"unit"`
+	Note         string  `json:"note"`
+	Optional     bool    `json:"optional"`
+}
+
+// RecipeRevisionIngredients is the list of the ingredient lines of a revision stored as a JSON document
+type RecipeRevisionIngredients []*RecipeRevisionIngredient
+
+// Value encodes the ingredient lines in JSON to store them in the database
+func (l RecipeRevisionIngredients) Value() (driver.Value, error) {
+	if l == nil {
+		l = RecipeRevisionIngredients{}
+	}
+	buf, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return string(buf), nil
+}
+
+// Scan decodes the ingredient lines read from the database
+func (l *RecipeRevisionIngredients) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	case nil:
+		*l = nil
+		return nil
+	default:
+		return errors.New("unsupported type for the recipe revision ingredients")
+	}
+}
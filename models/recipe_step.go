@@ -0,0 +1,14 @@
+// package which contains database model definition
+package models
+
+import "gorm.io/gorm"
+
+// Struct to store a preparation step of a recipe, it embed the gorm model strut which define common fields
+type RecipeStep struct {
+	gorm.Model
+	RecipeID    uint          `gorm:"not null;index"` // the reference of the recipe
+	Position    uint          `gorm:"not null"`       // the number of the step in the recipe, starting at 1
+	Text        string        `gorm:"not null"`       // the instruction of the step
+	Duration    *uint         // the optional duration of the step in minutes
+	Ingredients []*Ingredient `gorm:"many2many:recipe_steps_ingredients;"` // the recipe ingredients used during the step
+}
@@ -0,0 +1,15 @@
+// package which contains database model definition
+package models
+
+import "time"
+
+// Struct to store the trending score of a recipe over a time window, e.g. 7d.
+// The scores are computed periodically in the background from the favorites and the views, all of them are replaced at every computation.
+type RecipeTrend struct {
+	Period        string    `gorm:"type:varchar(3);primaryKey;index:idx_trend_period_score,priority:1"` // the time window of the score: 24h, 7d or 30d
+	RecipeID      uint      `gorm:"primaryKey;autoIncrement:false;index"`                               // the reference of the trending recipe
+	Score         float64   `gorm:"not null;index:idx_trend_period_score,priority:2,sort:desc"`         // the time-decayed score, the recent events weight more
+	FavoriteCount uint      `gorm:"not null;default:0"`                                                 // the number of favorites added during the window
+	ViewCount     uint      `gorm:"not null;default:0"`                                                 // the number of views during the window
+	CreatedAt     time.Time // the date the score was computed
+}
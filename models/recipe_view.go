@@ -0,0 +1,13 @@
+// package which contains database model definition
+package models
+
+import "time"
+
+// Struct to store a view of a recipe, the views older than the longest trending window are purged.
+// A viewer is only counted once per hour for a recipe.
+type RecipeView struct {
+	ID        uint      `gorm:"primarykey"`
+	RecipeID  uint      `gorm:"not null;index:idx_recipe_view_viewer,priority:1"`                             // the reference of the viewed recipe
+	Viewer    string    `gorm:"type:varchar(64);not null;default:'';index:idx_recipe_view_viewer,priority:2"` // the hash identifying the viewer
+	CreatedAt time.Time `gorm:"index;index:idx_recipe_view_viewer,priority:3"`                                // the date the recipe was viewed
+}
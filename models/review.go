@@ -0,0 +1,15 @@
+// package which contains database model definition
+package models
+
+import "gorm.io/gorm"
+
+// Struct to store the review of a recipe by a user, a user reviews a recipe only once.
+// It embed the gorm model strut which define common fields, the reviews are deleted permanently so the user can review the recipe again.
+type Review struct {
+	gorm.Model
+	UserID   uint   `gorm:"not null;uniqueIndex:idx_review_user_recipe"`       // the reference of the user who wrote the review
+	User     *User  `gorm:"foreignKey:UserID"`                                 // the user who wrote the review
+	RecipeID uint   `gorm:"not null;uniqueIndex:idx_review_user_recipe;index"` // the reference of the reviewed recipe
+	Rating   uint8  `gorm:"not null;check:rating BETWEEN 1 AND 5"`             // the number of stars from 1 to 5
+	Text     string `gorm:"not null;default:''"`                               // the optional written review
+}
@@ -0,0 +1,24 @@
+// package which contains database model definition
+package models
+
+import "gorm.io/gorm"
+
+// Struct to store a shopping list saved by a user, it embed the gorm model strut which define common fields
+type ShoppingList struct {
+	gorm.Model
+	Title  string              `gorm:"type:varchar(100);not null"`                             // the title of the list
+	UserID uint                `gorm:"not null;index"`                                         // the reference of the user who owns the list
+	Items  []*ShoppingListItem `gorm:"foreignKey:ShoppingListID;constraint:OnDelete:CASCADE;"` // the ingredients to buy
+}
+
+// Struct to store an ingredient to buy, the quantities of the recipes using the same ingredient are merged in a single item
+type ShoppingListItem struct {
+	gorm.Model
+	ShoppingListID uint        `gorm:"not null;index"`                       // the reference of the shopping list
+	IngredientID   uint        `gorm:"not null"`                             // the reference of the ingredient to buy
+	Ingredient     *Ingredient `gorm:"foreignKey:IngredientID"`              // the ingredient to buy
+	Aisle          string      `gorm:"type:varchar(100);not null"`           // the aisle of the shop, it's the ingredient type
+	Quantity       float64     `gorm:"not null;default:0"`                   // the quantity to buy, 0 when it's not specified
+	Unit           string      `gorm:"type:varchar(20);not null;default:''"` // the unit of the quantity, empty for a number of pieces
+	Checked        bool        `gorm:"not null;default:false"`               // the item has been ticked off
+}
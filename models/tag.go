@@ -0,0 +1,35 @@
+// package which contains database model definition
+package models
+
+import "gorm.io/gorm"
+
+// The categories of the tags, the free-form tags can be created by every user
+// and the curated categories are managed by the administrators
+const (
+	TagCategoryFree     = "tag"
+	TagCategoryCourse   = "course"
+	TagCategoryCuisine  = "cuisine"
+	TagCategoryOccasion = "occasion"
+)
+
+// CuratedTagCategories lists the categories of tags managed by the administrators
+var CuratedTagCategories = []string{TagCategoryCourse, TagCategoryCuisine, TagCategoryOccasion}
+
+// IsCuratedTagCategory returns true when the tags of the category are managed by the administrators
+func IsCuratedTagCategory(category string) bool {
+	for _, curated := range CuratedTagCategories {
+		if category == curated {
+			return true
+		}
+	}
+	return false
+}
+
+// Struct to store a tag used to classify the recipes, it embed the gorm model strut which define common fields.
+// The tags are deleted permanently so their name can be used again.
+type Tag struct {
+	gorm.Model
+	Name      string `gorm:"type:varchar(50);not null;uniqueIndex:idx_tag_category_name"`               // the tag name in lower case
+	Category  string `gorm:"type:varchar(20);not null;default:'tag';uniqueIndex:idx_tag_category_name"` // the category of the tag: tag, course, cuisine or occasion
+	CreatorID uint   `gorm:"not null"`                                                                  // the reference of the user who created the tag
+}
@@ -0,0 +1,256 @@
+// package repositories defines interfaces for managing recipe collection data in the database
+package repositories
+
+import (
+	"fmt"
+
+	"github.com/clementb49/welsh_academy/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CollectionRepository is an interface that defines functions for managing the recipe collections of the users in the database.
+// The collections are modified with their owner so a user can't modify the collections of another user,
+// the other users read the public collections or the collections shared with their share token.
+type CollectionRepository interface {
+	CreateCollection(collection *models.Collection) (*models.Collection, error)
+	GetAllCollections(userId uint, pageSize, pageNumber int) ([]*models.Collection, int64, error)
+	GetCollectionById(userId, collectionId uint) (*models.Collection, error)
+	UpdateCollection(collection *models.Collection) (*models.Collection, error)
+	DeleteCollectionById(userId, collectionId uint) error
+	AddRecipeToCollection(userId, collectionId, recipeId uint) (*models.Collection, error)
+	DeleteRecipeFromCollection(userId, collectionId, recipeId uint) (*models.Collection, error)
+	ReorderCollectionRecipes(userId, collectionId uint, recipesId []uint) (*models.Collection, error)
+	SetCollectionShareToken(userId, collectionId uint, token *string) (*models.Collection, error)
+	GetAllPublicCollections(ownerId uint, pageSize, pageNumber int) ([]*models.Collection, int64, error)
+	GetPublicCollectionById(collectionId uint) (*models.Collection, error)
+	GetCollectionByShareToken(token string) (*models.Collection, error)
+}
+
+// Queries used to manage the recipes of a collection, the recipes are positioned from 1
+const (
+	addRecipeToCollectionQuery = "INSERT INTO wac_collection_recipes (collection_id, recipe_id, position, created_at) " +
+		"SELECT ?, ?, COALESCE(MAX(position), 0) + 1, NOW() FROM wac_collection_recipes WHERE collection_id = ? ON CONFLICT DO NOTHING"
+	deleteDeletedCollectionRecipesQuery = "DELETE FROM wac_collection_recipes WHERE collection_id = ? AND recipe_id IN (SELECT id FROM wac_recipes WHERE deleted_at IS NOT NULL)"
+)
+
+// ErrCollectionOrderNotAcceptable is returned when the new order doesn't contain exactly all the recipes of the collection
+var ErrCollectionOrderNotAcceptable = fmt.Errorf("the new order must contain each recipe of the collection once, order not acceptable")
+
+// NewCollectionRepository returns a new instance of the CollectionRepository interface
+func NewCollectionRepository(db *gorm.DB) CollectionRepository {
+	return &repository{
+		db:     db,
+		logger: zap.L(),
+	}
+}
+
+// preloadCollectionRecipes loads the owner of the collections and their recipes in their order,
+// the recipes deleted since they were added aren't loaded
+func preloadCollectionRecipes(db *gorm.DB) *gorm.DB {
+	return db.Preload("User").Preload("Recipes", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position").Order("recipe_id")
+	}).Preload("Recipes.Recipe")
+}
+
+// CreateCollection inserts the collection without recipes
+func (r *repository) CreateCollection(collection *models.Collection) (*models.Collection, error) {
+	result := r.db.Omit(clause.Associations).Create(collection)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return r.GetCollectionById(collection.UserID, collection.ID)
+}
+
+// GetAllCollections returns a page of the collections of the user, the most recent first
+func (r *repository) GetAllCollections(userId uint, pageSize, pageNumber int) ([]*models.Collection, int64, error) {
+	return r.getCollectionsPage(r.db.Model(&models.Collection{}).Where("user_id = ?", userId), pageSize, pageNumber)
+}
+
+// GetCollectionById returns a collection of the user by ID with its recipes
+func (r *repository) GetCollectionById(userId, collectionId uint) (*models.Collection, error) {
+	var collection *models.Collection
+	result := preloadCollectionRecipes(r.db).Where("user_id = ?", userId).First(&collection, collectionId)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+// UpdateCollection saves the title, the description and the visibility of a collection of its owner
+func (r *repository) UpdateCollection(collection *models.Collection) (*models.Collection, error) {
+	result := r.db.Model(collection).Where("user_id = ?", collection.UserID).
+		Select("Title", "Description", "Public", "UpdatedAt").Updates(collection)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return r.GetCollectionById(collection.UserID, collection.ID)
+}
+
+// DeleteCollectionById deletes a collection of the user by ID with its recipe memberships, the recipes aren't modified
+func (r *repository) DeleteCollectionById(userId, collectionId uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var collection *models.Collection
+		result := tx.Where("user_id = ?", userId).First(&collection, collectionId)
+		if err := result.Error; err != nil {
+			return err
+		}
+		result = tx.Where("collection_id = ?", collection.ID).Delete(&models.CollectionRecipe{})
+		if err := result.Error; err != nil {
+			return err
+		}
+		return tx.Delete(collection).Error
+	})
+}
+
+// AddRecipeToCollection adds the recipe at the end of a collection of the user, nothing is done when it's already there
+func (r *repository) AddRecipeToCollection(userId, collectionId, recipeId uint) (*models.Collection, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var collection *models.Collection
+		result := tx.Where("user_id = ?", userId).First(&collection, collectionId)
+		if err := result.Error; err != nil {
+			return err
+		}
+		return tx.Exec(addRecipeToCollectionQuery, collection.ID, recipeId, collection.ID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetCollectionById(userId, collectionId)
+}
+
+// DeleteRecipeFromCollection removes the recipe from a collection of the user and moves up the next recipes,
+// it returns gorm.ErrRecordNotFound when the recipe isn't in the collection
+func (r *repository) DeleteRecipeFromCollection(userId, collectionId, recipeId uint) (*models.Collection, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var collection *models.Collection
+		result := tx.Where("user_id = ?", userId).First(&collection, collectionId)
+		if err := result.Error; err != nil {
+			return err
+		}
+		var member *models.CollectionRecipe
+		result = tx.Where("collection_id = ? AND recipe_id = ?", collection.ID, recipeId).First(&member)
+		if err := result.Error; err != nil {
+			return err
+		}
+		result = tx.Where("collection_id = ? AND recipe_id = ?", collection.ID, recipeId).Delete(&models.CollectionRecipe{})
+		if err := result.Error; err != nil {
+			return err
+		}
+		result = tx.Model(&models.CollectionRecipe{}).
+			Where("collection_id = ? AND position > ?", collection.ID, member.Position).
+			UpdateColumn("position", gorm.Expr("position - 1"))
+		return result.Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetCollectionById(userId, collectionId)
+}
+
+// ReorderCollectionRecipes sets the position of each recipe of a collection of the user to its index in recipesId starting at 1,
+// the recipes deleted since they were added are removed from the collection first
+func (r *repository) ReorderCollectionRecipes(userId, collectionId uint, recipesId []uint) (*models.Collection, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var collection *models.Collection
+		result := tx.Where("user_id = ?", userId).First(&collection, collectionId)
+		if err := result.Error; err != nil {
+			return err
+		}
+		result = tx.Exec(deleteDeletedCollectionRecipesQuery, collection.ID)
+		if err := result.Error; err != nil {
+			return err
+		}
+		var members []*models.CollectionRecipe
+		result = tx.Where("collection_id = ?", collection.ID).Find(&members)
+		if err := result.Error; err != nil {
+			return err
+		}
+		if len(uniqueValues(recipesId)) != len(recipesId) || len(recipesId) != len(members) {
+			return ErrCollectionOrderNotAcceptable
+		}
+		positions := make(map[uint]uint, len(recipesId))
+		for i, id := range recipesId {
+			positions[id] = uint(i + 1)
+		}
+		for _, member := range members {
+			position, ok := positions[member.RecipeID]
+			if !ok {
+				return ErrCollectionOrderNotAcceptable
+			}
+			result = tx.Model(&models.CollectionRecipe{}).
+				Where("collection_id = ? AND recipe_id = ?", member.CollectionID, member.RecipeID).
+				UpdateColumn("position", position)
+			if err := result.Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetCollectionById(userId, collectionId)
+}
+
+// SetCollectionShareToken replaces the share token of a collection of the user, the collection isn't shared anymore when token is nil
+func (r *repository) SetCollectionShareToken(userId, collectionId uint, token *string) (*models.Collection, error) {
+	result := r.db.Model(&models.Collection{}).Where("id = ? AND user_id = ?", collectionId, userId).Update("share_token", token)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return r.GetCollectionById(userId, collectionId)
+}
+
+// GetAllPublicCollections returns a page of the public collections, the most recent first,
+// only the collections of the owner are returned when ownerId isn't 0
+func (r *repository) GetAllPublicCollections(ownerId uint, pageSize, pageNumber int) ([]*models.Collection, int64, error) {
+	db := r.db.Model(&models.Collection{}).Where("public = ?", true)
+	if ownerId != 0 {
+		db = db.Where("user_id = ?", ownerId)
+	}
+	return r.getCollectionsPage(db, pageSize, pageNumber)
+}
+
+// GetPublicCollectionById returns a public collection by ID with its recipes
+func (r *repository) GetPublicCollectionById(collectionId uint) (*models.Collection, error) {
+	var collection *models.Collection
+	result := preloadCollectionRecipes(r.db).Where("public = ?", true).First(&collection, collectionId)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+// GetCollectionByShareToken returns the collection shared with the token with its recipes, even when it's private
+func (r *repository) GetCollectionByShareToken(token string) (*models.Collection, error) {
+	var collection *models.Collection
+	result := preloadCollectionRecipes(r.db).Where("share_token = ?", token).First(&collection)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+// getCollectionsPage returns a page of the collections selected by db with their recipes, the most recent first
+func (r *repository) getCollectionsPage(db *gorm.DB, pageSize, pageNumber int) ([]*models.Collection, int64, error) {
+	var collections []*models.Collection
+	var totalCollections int64
+	db = db.Session(&gorm.Session{})
+	err := db.Count(&totalCollections).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	err = preloadCollectionRecipes(db).Order("created_at DESC").Order("id").Offset(pageNumber * pageSize).Limit(pageSize).Find(&collections).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return collections, totalCollections, nil
+}
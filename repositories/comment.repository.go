@@ -0,0 +1,159 @@
+// package repositories defines interfaces for managing recipe comment data in the database
+package repositories
+
+import (
+	"fmt"
+
+	"github.com/clementb49/welsh_academy/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CommentRepository is an interface that defines functions for managing the comment threads of the recipes in the database.
+// The number of comments of the recipe is updated in the same transaction as its comments.
+type CommentRepository interface {
+	CreateComment(comment *models.Comment) (*models.Comment, error)
+	GetAllComments(recipeId uint, pageSize, pageNumber int) ([]*models.Comment, int64, error)
+	GetCommentById(recipeId, commentId uint) (*models.Comment, error)
+	UpdateComment(comment *models.Comment) (*models.Comment, error)
+	DeleteComment(comment *models.Comment) error
+}
+
+// ErrCommentParentNotAcceptable is returned when the answered comment doesn't exist or belongs to another recipe
+var ErrCommentParentNotAcceptable = fmt.Errorf("the answered comment isn't a comment of the recipe, comment not acceptable")
+
+// Queries used to read the comment threads, the deleted comments are kept when they have replies
+const (
+	// Condition selecting the first comment of the threads
+	commentThreadCondition = "recipe_id = ? AND parent_id IS NULL AND (deleted_at IS NULL OR EXISTS (SELECT 1 FROM wac_comments r WHERE r.parent_id = wac_comments.id))"
+	// Recursive query returning the ID of all the replies of the given comments, whatever their depth
+	commentRepliesQuery = "WITH RECURSIVE replies AS (SELECT id FROM wac_comments WHERE parent_id IN ? " +
+		"UNION ALL SELECT c.id FROM wac_comments c JOIN replies ON c.parent_id = replies.id) SELECT id FROM replies"
+	// Query counting the comments of a recipe
+	updateRecipeCommentCountQuery = "UPDATE wac_recipes SET comment_count = (SELECT COUNT(*) FROM wac_comments WHERE recipe_id = ? AND deleted_at IS NULL) WHERE id = ?"
+)
+
+// NewCommentRepository returns a new instance of the CommentRepository interface
+func NewCommentRepository(db *gorm.DB) CommentRepository {
+	return &repository{
+		db:     db,
+		logger: zap.L(),
+	}
+}
+
+// CreateComment inserts the comment, a reply must answer a comment of the same recipe which isn't deleted
+func (r *repository) CreateComment(input *models.Comment) (*models.Comment, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if input.ParentID != nil {
+			var nbParents int64
+			result := tx.Model(&models.Comment{}).Where("id = ? AND recipe_id = ?", *input.ParentID, input.RecipeID).Count(&nbParents)
+			if err := result.Error; err != nil {
+				return err
+			}
+			if nbParents == 0 {
+				return ErrCommentParentNotAcceptable
+			}
+		}
+		result := tx.Omit(clause.Associations).Create(input)
+		if err := result.Error; err != nil {
+			return err
+		}
+		return updateRecipeCommentCount(tx, input.RecipeID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetCommentById(input.RecipeID, input.ID)
+}
+
+// updateRecipeCommentCount counts the comments of the recipe which aren't deleted
+func updateRecipeCommentCount(tx *gorm.DB, recipeId uint) error {
+	return tx.Exec(updateRecipeCommentCountQuery, recipeId, recipeId).Error
+}
+
+// GetAllComments returns a page of the comment threads of the recipe, the pinned threads first then the most recent.
+// The replies of each thread are loaded with a recursive query and nested in their parent in the order they were written.
+func (r *repository) GetAllComments(recipeId uint, pageSize, pageNumber int) ([]*models.Comment, int64, error) {
+	var threads []*models.Comment
+	var totalThreads int64
+	db := r.db.Unscoped().Model(&models.Comment{}).Where(commentThreadCondition, recipeId).Session(&gorm.Session{})
+	err := db.Count(&totalThreads).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	err = db.Preload("User").Order("pinned DESC").Order("created_at DESC").Order("id").
+		Offset(pageNumber * pageSize).Limit(pageSize).Find(&threads).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(threads) == 0 {
+		return threads, totalThreads, nil
+	}
+	threadsId := make([]uint, len(threads))
+	for i, thread := range threads {
+		threadsId[i] = thread.ID
+	}
+	var repliesId []uint
+	err = r.db.Raw(commentRepliesQuery, threadsId).Scan(&repliesId).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	var replies []*models.Comment
+	if len(repliesId) > 0 {
+		err = r.db.Unscoped().Preload("User").Where("id IN ?", repliesId).Order("created_at").Order("id").Find(&replies).Error
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	nestCommentReplies(threads, replies)
+	return threads, totalThreads, nil
+}
+
+// nestCommentReplies adds each reply to the replies of its parent, the replies must be ordered by creation
+func nestCommentReplies(threads []*models.Comment, replies []*models.Comment) {
+	commentsById := make(map[uint]*models.Comment, len(threads)+len(replies))
+	for _, comment := range threads {
+		comment.Replies = make([]*models.Comment, 0)
+		commentsById[comment.ID] = comment
+	}
+	for _, comment := range replies {
+		comment.Replies = make([]*models.Comment, 0)
+		commentsById[comment.ID] = comment
+	}
+	for _, comment := range replies {
+		if parent, ok := commentsById[*comment.ParentID]; ok {
+			parent.Replies = append(parent.Replies, comment)
+		}
+	}
+}
+
+// GetCommentById returns a comment of the recipe by ID with its user, the deleted comments aren't returned
+func (r *repository) GetCommentById(recipeId, commentId uint) (*models.Comment, error) {
+	var comment *models.Comment
+	result := r.db.Preload("User").Where("recipe_id = ?", recipeId).First(&comment, commentId)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// UpdateComment saves the text and the pinned flag of the comment
+func (r *repository) UpdateComment(input *models.Comment) (*models.Comment, error) {
+	result := r.db.Model(input).Select("Text", "Pinned").Updates(input)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return input, nil
+}
+
+// DeleteComment soft deletes the comment, its replies are kept
+func (r *repository) DeleteComment(input *models.Comment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(input)
+		if err := result.Error; err != nil {
+			return err
+		}
+		return updateRecipeCommentCount(tx, input.RecipeID)
+	})
+}
@@ -0,0 +1,151 @@
+// package repositories defines interfaces for managing recipe and ingredient image data in the database
+package repositories
+
+import (
+	"github.com/clementb49/welsh_academy/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ImageRepository is an interface that defines functions for managing the photos of the recipes and the images of the ingredients.
+// The files of the images are kept in the media storage, only their paths are saved in the database.
+type ImageRepository interface {
+	CreateRecipeImage(image *models.RecipeImage) (*models.RecipeImage, error)
+	CountRecipeImages(recipeId uint) (int64, error)
+	GetAllRecipeImages(recipeId uint) ([]*models.RecipeImage, error)
+	SetRecipeCoverImage(recipeId, imageId uint) (*models.RecipeImage, error)
+	DeleteRecipeImage(recipeId, imageId uint) (*models.RecipeImage, error)
+	SaveIngredientImage(image *models.IngredientImage) (*models.IngredientImage, error)
+	DeleteIngredientImage(ingredientId uint) error
+}
+
+// NewImageRepository returns a new instance of the ImageRepository interface
+func NewImageRepository(db *gorm.DB) ImageRepository {
+	return &repository{
+		db:     db,
+		logger: zap.L(),
+	}
+}
+
+// orderRecipeImages orders the photos of a recipe, the cover first then the oldest first
+func orderRecipeImages(db *gorm.DB) *gorm.DB {
+	return db.Order("cover DESC").Order("created_at").Order("id")
+}
+
+// CreateRecipeImage inserts the photo of the recipe, the photo becomes the cover when it's asked or when the recipe has no cover yet
+func (r *repository) CreateRecipeImage(image *models.RecipeImage) (*models.RecipeImage, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var covers int64
+		err := tx.Model(&models.RecipeImage{}).Where("recipe_id = ? AND cover = ?", image.RecipeID, true).Count(&covers).Error
+		if err != nil {
+			return err
+		}
+		if image.Cover && covers > 0 {
+			err = tx.Model(&models.RecipeImage{}).Where("recipe_id = ? AND cover = ?", image.RecipeID, true).UpdateColumn("cover", false).Error
+			if err != nil {
+				return err
+			}
+		}
+		image.Cover = image.Cover || covers == 0
+		return tx.Create(image).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return image, nil
+}
+
+// CountRecipeImages returns the number of photos of the recipe
+func (r *repository) CountRecipeImages(recipeId uint) (int64, error) {
+	var totalImages int64
+	err := r.db.Model(&models.RecipeImage{}).Where("recipe_id = ?", recipeId).Count(&totalImages).Error
+	if err != nil {
+		return 0, err
+	}
+	return totalImages, nil
+}
+
+// GetAllRecipeImages returns the photos of the recipe, the cover first
+func (r *repository) GetAllRecipeImages(recipeId uint) ([]*models.RecipeImage, error) {
+	var images []*models.RecipeImage
+	err := orderRecipeImages(r.db).Where("recipe_id = ?", recipeId).Find(&images).Error
+	if err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+// SetRecipeCoverImage makes the photo the cover of its recipe, the former cover stays a photo of the recipe
+func (r *repository) SetRecipeCoverImage(recipeId, imageId uint) (*models.RecipeImage, error) {
+	var image *models.RecipeImage
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("recipe_id = ?", recipeId).First(&image, imageId)
+		if err := result.Error; err != nil {
+			return err
+		}
+		result = tx.Model(&models.RecipeImage{}).Where("recipe_id = ? AND id <> ?", recipeId, imageId).UpdateColumn("cover", false)
+		if err := result.Error; err != nil {
+			return err
+		}
+		image.Cover = true
+		return tx.Model(image).UpdateColumn("cover", true).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return image, nil
+}
+
+// DeleteRecipeImage deletes the photo of the recipe and returns it so its files can be removed,
+// the oldest remaining photo becomes the cover when the cover is deleted
+func (r *repository) DeleteRecipeImage(recipeId, imageId uint) (*models.RecipeImage, error) {
+	var image *models.RecipeImage
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("recipe_id = ?", recipeId).First(&image, imageId)
+		if err := result.Error; err != nil {
+			return err
+		}
+		result = tx.Delete(image)
+		if err := result.Error; err != nil {
+			return err
+		}
+		if !image.Cover {
+			return nil
+		}
+		var next *models.RecipeImage
+		result = orderRecipeImages(tx).Where("recipe_id = ?", recipeId).Limit(1).Find(&next)
+		if err := result.Error; err != nil || result.RowsAffected == 0 {
+			return err
+		}
+		return tx.Model(next).UpdateColumn("cover", true).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return image, nil
+}
+
+// SaveIngredientImage creates the image of the ingredient or replaces the existing one
+func (r *repository) SaveIngredientImage(input *models.IngredientImage) (*models.IngredientImage, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "ingredient_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"path", "thumbnail_path", "content_type", "width", "height", "size", "updated_at"}),
+	}).Create(input)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return input, nil
+}
+
+// DeleteIngredientImage deletes the image of the ingredient, it returns gorm.ErrRecordNotFound when the ingredient has none
+func (r *repository) DeleteIngredientImage(ingredientId uint) error {
+	result := r.db.Delete(&models.IngredientImage{}, ingredientId)
+	if err := result.Error; err != nil {
+		return err
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
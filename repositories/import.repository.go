@@ -0,0 +1,88 @@
+// package repositories defines interfaces for managing imported recipe data in the database
+package repositories
+
+import (
+	"github.com/clementb49/welsh_academy/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ImportRepository is an interface that defines functions for saving the recipes imported from other sites.
+type ImportRepository interface {
+	GetIngredientsByName(names []string) ([]*models.Ingredient, error)
+	CountRecipesByTitle(title string) (int64, error)
+	ImportRecipe(recipe *models.Recipe) (*models.Recipe, error)
+}
+
+// NewImportRepository returns a new instance of the ImportRepository interface
+func NewImportRepository(db *gorm.DB) ImportRepository {
+	return &repository{
+		db:     db,
+		logger: zap.L(),
+	}
+}
+
+// GetIngredientsByName returns the ingredients whose lower case name is one of the names, the oldest first
+func (r *repository) GetIngredientsByName(names []string) ([]*models.Ingredient, error) {
+	var ingredients []*models.Ingredient
+	if len(names) == 0 {
+		return ingredients, nil
+	}
+	result := r.db.Where("LOWER(name) IN ?", uniqueValues(names)).Order("id").Find(&ingredients)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return ingredients, nil
+}
+
+// CountRecipesByTitle returns the number of recipes with the title, the titles are unique so it's 0 or 1.
+// The deleted recipes are counted because they keep their title.
+func (r *repository) CountRecipesByTitle(title string) (int64, error) {
+	var nbRecipes int64
+	err := r.db.Unscoped().Model(&models.Recipe{}).Where("title = ?", title).Count(&nbRecipes).Error
+	if err != nil {
+		return 0, err
+	}
+	return nbRecipes, nil
+}
+
+// ImportRecipe inserts the imported recipe with its ingredient lines and its steps in a single transaction.
+// The ingredients of the lines without ingredient ID are created first, so nothing is saved when the recipe is refused.
+func (r *repository) ImportRecipe(recipe *models.Recipe) (*models.Recipe, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, line := range recipe.Ingredients {
+			if line.IngredientID != 0 || line.Ingredient == nil {
+				continue
+			}
+			result := tx.Omit(clause.Associations).Create(line.Ingredient)
+			if err := result.Error; err != nil {
+				return err
+			}
+			line.IngredientID = line.Ingredient.ID
+		}
+		result := tx.Omit(clause.Associations).Create(recipe)
+		if err := result.Error; err != nil {
+			return err
+		}
+		err := saveRecipeIngredients(tx, recipe)
+		if err != nil {
+			return err
+		}
+		for i, step := range recipe.Steps {
+			step.RecipeID = recipe.ID
+			step.Position = uint(i + 1)
+		}
+		if len(recipe.Steps) > 0 {
+			result = tx.Omit(clause.Associations).Create(recipe.Steps)
+			if err := result.Error; err != nil {
+				return err
+			}
+		}
+		return saveRecipeRevision(tx, recipe, uint(recipe.AuthorID))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return recipe, nil
+}
@@ -12,6 +12,7 @@ type IngredientRepository interface {
 	CreateIngredient(input *models.Ingredient) (*models.Ingredient, error)               // Create a new ingredient
 	GetAllIngredients(pageSize int, pageNumber int) ([]*models.Ingredient, int64, error) // Get all ingredients with pagination
 	GetIngredientById(ingredientId uint) (*models.Ingredient, error)                       // Get an ingredient by ID
+	UpdateIngredient(input *models.Ingredient) (*models.Ingredient, error)               // Update an ingredient
 	DeleteIngredientById(ingredientId uint) error                                        // Delete an ingredient by ID
 }
 
@@ -42,7 +43,7 @@ func (r *repository) GetAllIngredients(pageSize int, pageNumber int) ([]*models.
 	if err := result.Error; err != nil {
 		return nil, 0, err
 	}
-	result = r.db.Offset(pageNumber * pageSize).Limit(pageSize).Find(&ingredients)
+	result = r.db.Preload("Nutrition").Preload("Price", preloadLatestIngredientPrice).Preload("Image").Offset(pageNumber * pageSize).Limit(pageSize).Find(&ingredients)
 	if err := result.Error; err != nil {
 		return nil, 0, err
 	}
@@ -52,18 +53,29 @@ func (r *repository) GetAllIngredients(pageSize int, pageNumber int) ([]*models.
 // GetIngredientById returns an ingredient by ID
 func (r *repository) GetIngredientById(ingredientId uint) (*models.Ingredient, error) {
 	var ingredient *models.Ingredient
-	result := r.db.First(&ingredient, ingredientId)
+	result := r.db.Preload("Nutrition").Preload("Price", preloadLatestIngredientPrice).Preload("Image").First(&ingredient, ingredientId)
 	if err := result.Error; err != nil {
 		return nil, err
 	}
 	return ingredient, nil
 }
 
-// DeleteIngredientById deletes an ingredient by ID
-func (r *repository) DeleteIngredientById(ingredientId uint) error {
-	result := r.db.Delete(&models.Ingredient{}, ingredientId)
+// UpdateIngredient saves the fields of the ingredient
+func (r *repository) UpdateIngredient(input *models.Ingredient) (*models.Ingredient, error) {
+	result := r.db.Model(input).Select("Name", "Type", "Density", "PieceWeight", "Allergens").Updates(input)
 	if err := result.Error; err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	return input, nil
+}
+
+// DeleteIngredientById deletes an ingredient by ID with its image
+func (r *repository) DeleteIngredientById(ingredientId uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.Ingredient{}, ingredientId)
+		if err := result.Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.IngredientImage{}, ingredientId).Error
+	})
 }
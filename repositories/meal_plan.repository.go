@@ -0,0 +1,88 @@
+// package repositories defines interfaces for managing meal plan data in the database
+package repositories
+
+import (
+	"time"
+
+	"github.com/clementb49/welsh_academy/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MealPlanRepository is an interface that defines functions for managing the meal plans of the users in the database.
+// The meal plans are always looked up with their owner so a user can't access the plans of another user.
+type MealPlanRepository interface {
+	CreateMealPlan(plan *models.MealPlan) (*models.MealPlan, error)
+	GetAllMealPlans(userId uint, from, to time.Time) ([]*models.MealPlan, error)
+	GetMealPlanById(userId, planId uint) (*models.MealPlan, error)
+	UpdateMealPlan(plan *models.MealPlan) (*models.MealPlan, error)
+	DeleteMealPlanById(userId, planId uint) error
+}
+
+// NewMealPlanRepository returns a new instance of the MealPlanRepository interface
+func NewMealPlanRepository(db *gorm.DB) MealPlanRepository {
+	return &repository{
+		db:     db,
+		logger: zap.L(),
+	}
+}
+
+// preloadMealPlanRecipe loads the planned recipe, even when it's deleted
+func preloadMealPlanRecipe(db *gorm.DB) *gorm.DB {
+	return db.Preload("Recipe", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	})
+}
+
+// CreateMealPlan inserts the meal plan, the recipe isn't modified
+func (r *repository) CreateMealPlan(plan *models.MealPlan) (*models.MealPlan, error) {
+	result := r.db.Omit(clause.Associations).Create(plan)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return r.GetMealPlanById(plan.UserID, plan.ID)
+}
+
+// GetAllMealPlans returns the meal plans of the user between the two dates included, ordered by date
+func (r *repository) GetAllMealPlans(userId uint, from, to time.Time) ([]*models.MealPlan, error) {
+	var plans []*models.MealPlan
+	result := preloadMealPlanRecipe(r.db).
+		Where("user_id = ? AND date BETWEEN ? AND ?", userId, from.Format(models.DateFormat), to.Format(models.DateFormat)).
+		Order("date").Order("id").Find(&plans)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return plans, nil
+}
+
+// GetMealPlanById returns a meal plan of the user by ID with its recipe
+func (r *repository) GetMealPlanById(userId, planId uint) (*models.MealPlan, error) {
+	var plan *models.MealPlan
+	result := preloadMealPlanRecipe(r.db).Where("user_id = ?", userId).First(&plan, planId)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// UpdateMealPlan saves the meal plan, the recipe isn't modified
+func (r *repository) UpdateMealPlan(plan *models.MealPlan) (*models.MealPlan, error) {
+	result := r.db.Omit(clause.Associations).Save(plan)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return r.GetMealPlanById(plan.UserID, plan.ID)
+}
+
+// DeleteMealPlanById deletes a meal plan of the user by ID
+func (r *repository) DeleteMealPlanById(userId, planId uint) error {
+	result := r.db.Where("user_id = ?", userId).Delete(&models.MealPlan{}, planId)
+	if err := result.Error; err != nil {
+		return err
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
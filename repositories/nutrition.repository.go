@@ -0,0 +1,47 @@
+// package repositories defines interfaces for managing ingredient nutrition data in the database
+package repositories
+
+import (
+	"github.com/clementb49/welsh_academy/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NutritionRepository is an interface that defines functions for managing the nutrition facts of the ingredients in the database
+type NutritionRepository interface {
+	SaveIngredientNutrition(nutrition *models.IngredientNutrition) (*models.IngredientNutrition, error)
+	DeleteIngredientNutrition(ingredientId uint) error
+}
+
+// NewNutritionRepository returns a new instance of the NutritionRepository interface
+func NewNutritionRepository(db *gorm.DB) NutritionRepository {
+	return &repository{
+		db:     db,
+		logger: zap.L(),
+	}
+}
+
+// SaveIngredientNutrition creates the nutrition facts of the ingredient or replaces the existing ones
+func (r *repository) SaveIngredientNutrition(input *models.IngredientNutrition) (*models.IngredientNutrition, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "ingredient_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"energy_kcal", "fat", "saturates", "carbohydrate", "sugars", "protein", "salt", "updated_at"}),
+	}).Create(input)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return input, nil
+}
+
+// DeleteIngredientNutrition deletes the nutrition facts of the ingredient, it returns gorm.ErrRecordNotFound when the ingredient has none
+func (r *repository) DeleteIngredientNutrition(ingredientId uint) error {
+	result := r.db.Delete(&models.IngredientNutrition{}, ingredientId)
+	if err := result.Error; err != nil {
+		return err
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
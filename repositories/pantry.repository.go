@@ -0,0 +1,217 @@
+// package repositories defines interfaces for managing user pantry data in the database
+package repositories
+
+import (
+	"fmt"
+
+	"github.com/clementb49/welsh_academy/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// PantryRepository is an interface that defines functions for managing the ingredients the users have at home
+// and finding the recipes they can cook with them
+type PantryRepository interface {
+	GetAllPantryIngredients(userId uint, pageSize, pageNumber int) ([]*models.Ingredient, int64, error)
+	AddIngredientToPantry(userId, ingredientId uint) error
+	DeleteIngredientFromPantry(userId, ingredientId uint) error
+	ReplacePantry(userId uint, ingredientsId []uint) error
+	GetCookableRecipes(userId uint, filter *CookableFilter, pageSize, pageNumber int) ([]*CookableRecipe, int64, error)
+}
+
+// Queries used to manage the pantry of a user
+const (
+	pantryJoinQuery                 = "JOIN wac_pantries_ingredients ON wac_pantries_ingredients.ingredient_id = wac_ingredients.id AND wac_pantries_ingredients.user_id = ?"
+	addIngredientToPantryQuery      = "INSERT INTO wac_pantries_ingredients (user_id, ingredient_id) VALUES (?, ?) ON CONFLICT DO NOTHING"
+	deleteIngredientFromPantryQuery = "DELETE FROM wac_pantries_ingredients WHERE user_id = ? AND ingredient_id = ?"
+	deletePantryQuery               = "DELETE FROM wac_pantries_ingredients WHERE user_id = ?"
+	fillPantryQuery                 = "INSERT INTO wac_pantries_ingredients (user_id, ingredient_id) SELECT ?, id FROM wac_ingredients WHERE id IN ? AND deleted_at IS NULL"
+)
+
+// Queries used to rank the recipes by the number of their required ingredients available in the pantry of a user.
+// The optional ingredients are ignored, a recipe without any available ingredient isn't cookable.
+// The %s placeholder is the condition checking if the ingredient line is available, the @user parameter is the user ID.
+const (
+	cookableRecipesQuery = "SELECT recipe_id, COUNT(*) AS ingredient_count, COUNT(*) FILTER (WHERE available) AS available_count FROM " +
+		"(SELECT ir.recipe_id, (%s) AS available FROM wac_ingredients_recipes ir JOIN wac_recipes r ON r.id = ir.recipe_id AND r.deleted_at IS NULL " +
+		"WHERE ir.optional = false) l GROUP BY recipe_id HAVING COUNT(*) FILTER (WHERE available) > 0"
+	cookableMaxMissingCondition = " AND COUNT(*) FILTER (WHERE NOT available) <= @maxMissing"
+	cookableRecipesOrder        = " ORDER BY COUNT(*) FILTER (WHERE NOT available), COUNT(*) FILTER (WHERE available) DESC, recipe_id LIMIT @limit OFFSET @offset"
+	missingIngredientsQuery     = "SELECT ir.recipe_id, ir.ingredient_id FROM wac_ingredients_recipes ir WHERE ir.recipe_id IN @recipes AND ir.optional = false AND NOT (%s)"
+	// the ingredient line is available when its ingredient is in the pantry
+	pantryAvailableCondition = "ir.ingredient_id IN (SELECT ingredient_id FROM wac_pantries_ingredients WHERE user_id = @user)"
+	// the ingredient line is also available when one of its substitutes is in the pantry
+	substituteAvailableCondition = " OR ir.ingredient_id IN (SELECT s.ingredient_id FROM wac_ingredient_substitutions s " +
+		"JOIN wac_pantries_ingredients p ON p.ingredient_id = s.substitute_id AND p.user_id = @user " +
+		"UNION SELECT s.substitute_id FROM wac_ingredient_substitutions s " +
+		"JOIN wac_pantries_ingredients p ON p.ingredient_id = s.ingredient_id AND p.user_id = @user WHERE s.bidirectional)"
+)
+
+// ErrPantryIngredientNotAcceptable is returned when the pantry is filled with an ingredient which doesn't exist
+var ErrPantryIngredientNotAcceptable = fmt.Errorf("the pantry contains an unknown ingredient, pantry not acceptable")
+
+// CookableFilter defines the recipes kept by the cookable recipes search
+type CookableFilter struct {
+	MaxMissing       *uint // the maximum number of missing ingredients, all the recipes using an available ingredient are kept when it's nil
+	AllowSubstitutes bool  // when true an ingredient is available when one of its substitutes is in the pantry
+}
+
+// CookableRecipe is a recipe returned with the number of its required ingredients available in the pantry and the missing ones,
+// an ingredient replaced by a substitute of the pantry isn't missing when the substitutes are allowed
+type CookableRecipe struct {
+	Recipe             *models.Recipe       // the recipe using the pantry ingredients
+	IngredientCount    int                  // the number of required ingredients of the recipe
+	AvailableCount     int                  // the number of required ingredients available in the pantry
+	MissingIngredients []*models.Ingredient // the required ingredients missing from the pantry in the recipe order
+}
+
+// NewPantryRepository returns a new instance of the PantryRepository interface
+func NewPantryRepository(db *gorm.DB) PantryRepository {
+	return &repository{
+		db:     db,
+		logger: zap.L(),
+	}
+}
+
+// GetAllPantryIngredients returns a page of the ingredients in the pantry of the user ordered by name
+func (r *repository) GetAllPantryIngredients(userId uint, pageSize, pageNumber int) ([]*models.Ingredient, int64, error) {
+	var ingredients []*models.Ingredient
+	var totalIngredients int64
+	db := r.db.Model(&models.Ingredient{}).Joins(pantryJoinQuery, userId).Session(&gorm.Session{})
+	err := db.Count(&totalIngredients).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	err = db.Preload("Nutrition").Preload("Price", preloadLatestIngredientPrice).Preload("Image").
+		Order("name").Order("wac_ingredients.id").Offset(pageNumber * pageSize).Limit(pageSize).Find(&ingredients).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return ingredients, totalIngredients, nil
+}
+
+// AddIngredientToPantry adds the ingredient to the pantry of the user, nothing is done when it's already there
+func (r *repository) AddIngredientToPantry(userId, ingredientId uint) error {
+	return r.db.Exec(addIngredientToPantryQuery, userId, ingredientId).Error
+}
+
+// DeleteIngredientFromPantry removes the ingredient from the pantry of the user, it returns gorm.ErrRecordNotFound when it isn't there
+func (r *repository) DeleteIngredientFromPantry(userId, ingredientId uint) error {
+	result := r.db.Exec(deleteIngredientFromPantryQuery, userId, ingredientId)
+	if err := result.Error; err != nil {
+		return err
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ReplacePantry replaces the content of the pantry of the user with the ingredients, the ingredients listed twice are added once.
+// It returns ErrPantryIngredientNotAcceptable when one of them doesn't exist.
+func (r *repository) ReplacePantry(userId uint, ingredientsId []uint) error {
+	// one row is inserted per distinct ingredient so the duplicates would be counted as unknown ingredients
+	ingredientsId = uniqueValues(ingredientsId)
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(deletePantryQuery, userId).Error
+		if err != nil {
+			return err
+		}
+		if len(ingredientsId) == 0 {
+			return nil
+		}
+		result := tx.Exec(fillPantryQuery, userId, ingredientsId)
+		if err := result.Error; err != nil {
+			return err
+		}
+		if result.RowsAffected != int64(len(ingredientsId)) {
+			return ErrPantryIngredientNotAcceptable
+		}
+		return nil
+	})
+}
+
+// GetCookableRecipes returns a page of the recipes using the ingredients of the pantry of the user, the recipes missing
+// the fewest ingredients come first then the ones using the most available ingredients.
+// The recipes are ranked in the database, only the recipes of the page are loaded.
+func (r *repository) GetCookableRecipes(userId uint, filter *CookableFilter, pageSize, pageNumber int) ([]*CookableRecipe, int64, error) {
+	available := pantryAvailableCondition
+	if filter != nil && filter.AllowSubstitutes {
+		available += substituteAvailableCondition
+	}
+	query := fmt.Sprintf(cookableRecipesQuery, available)
+	args := map[string]interface{}{"user": userId}
+	if filter != nil && filter.MaxMissing != nil {
+		query += cookableMaxMissingCondition
+		args["maxMissing"] = *filter.MaxMissing
+	}
+	var totalRecipes int64
+	err := r.db.Raw("SELECT COUNT(*) FROM ("+query+") c", args).Scan(&totalRecipes).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	var rows []struct {
+		RecipeID        uint
+		IngredientCount int
+		AvailableCount  int
+	}
+	args["limit"] = pageSize
+	args["offset"] = pageNumber * pageSize
+	err = r.db.Raw(query+cookableRecipesOrder, args).Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(rows) == 0 {
+		return []*CookableRecipe{}, totalRecipes, nil
+	}
+	recipesId := make([]uint, len(rows))
+	for i, row := range rows {
+		recipesId[i] = row.RecipeID
+	}
+	var recipes []*models.Recipe
+	err = preloadRecipeDetails(r.db).Find(&recipes, recipesId).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	var missingRows []struct {
+		RecipeID     uint
+		IngredientID uint
+	}
+	args["recipes"] = recipesId
+	err = r.db.Raw(fmt.Sprintf(missingIngredientsQuery, available), args).Scan(&missingRows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	missing := make(map[uint]map[uint]bool, len(rows))
+	for _, row := range missingRows {
+		if missing[row.RecipeID] == nil {
+			missing[row.RecipeID] = make(map[uint]bool)
+		}
+		missing[row.RecipeID][row.IngredientID] = true
+	}
+	recipesById := make(map[uint]*models.Recipe, len(recipes))
+	for _, recipe := range recipes {
+		recipesById[recipe.ID] = recipe
+	}
+	results := make([]*CookableRecipe, 0, len(rows))
+	for _, row := range rows {
+		recipe, ok := recipesById[row.RecipeID]
+		if !ok {
+			continue
+		}
+		result := &CookableRecipe{
+			Recipe:             recipe,
+			IngredientCount:    row.IngredientCount,
+			AvailableCount:     row.AvailableCount,
+			MissingIngredients: make([]*models.Ingredient, 0),
+		}
+		// the lines are loaded in their order so the missing ingredients keep the recipe order
+		for _, line := range recipe.Ingredients {
+			if missing[recipe.ID][line.IngredientID] && line.Ingredient != nil {
+				result.MissingIngredients = append(result.MissingIngredients, line.Ingredient)
+			}
+		}
+		results = append(results, result)
+	}
+	return results, totalRecipes, nil
+}
@@ -0,0 +1,95 @@
+// package repositories defines interfaces for managing ingredient price data in the database
+package repositories
+
+import (
+	"github.com/clementb49/welsh_academy/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// PriceRepository is an interface that defines functions for managing the price history of the ingredients in the database
+type PriceRepository interface {
+	CreateIngredientPrice(price *models.IngredientPrice) (*models.IngredientPrice, error)
+	GetAllIngredientPrices(ingredientId uint, pageSize, pageNumber int) ([]*models.IngredientPrice, int64, error)
+	GetIngredientPriceById(priceId uint) (*models.IngredientPrice, error)
+	DeleteIngredientPriceById(priceId uint) error
+	GetRecipeIngredientPrices(recipeId uint) ([]*models.IngredientPrice, error)
+}
+
+// Queries used to select the latest price of the ingredients and the prices of the ingredients used by a recipe,
+// the latest price is looked up for each price of the loaded ingredients with the index on the ingredient and the date
+const (
+	latestIngredientPriceCondition = "id = (SELECT latest.id FROM wac_ingredient_prices latest WHERE latest.ingredient_id = wac_ingredient_prices.ingredient_id " +
+		"ORDER BY latest.recorded_at DESC, latest.id DESC LIMIT 1)"
+	recipeIngredientPricesCondition = "ingredient_id IN (SELECT ingredient_id FROM wac_ingredients_recipes WHERE recipe_id = ?)"
+)
+
+// NewPriceRepository returns a new instance of the PriceRepository interface
+func NewPriceRepository(db *gorm.DB) PriceRepository {
+	return &repository{
+		db:     db,
+		logger: zap.L(),
+	}
+}
+
+// preloadLatestIngredientPrice restricts the preloaded prices to the latest price of each ingredient
+func preloadLatestIngredientPrice(db *gorm.DB) *gorm.DB {
+	return db.Where(latestIngredientPriceCondition)
+}
+
+// CreateIngredientPrice records a new price of an ingredient
+func (r *repository) CreateIngredientPrice(input *models.IngredientPrice) (*models.IngredientPrice, error) {
+	result := r.db.Omit("Creator").Create(input)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return input, nil
+}
+
+// GetAllIngredientPrices returns a page of the price history of an ingredient, the latest first
+func (r *repository) GetAllIngredientPrices(ingredientId uint, pageSize, pageNumber int) ([]*models.IngredientPrice, int64, error) {
+	var prices []*models.IngredientPrice
+	var totalPrices int64
+	db := r.db.Model(&models.IngredientPrice{}).Where("ingredient_id = ?", ingredientId).Session(&gorm.Session{})
+	err := db.Count(&totalPrices).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	err = db.Order("recorded_at DESC").Order("id DESC").Offset(pageNumber * pageSize).Limit(pageSize).Find(&prices).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return prices, totalPrices, nil
+}
+
+// GetIngredientPriceById returns a price by ID
+func (r *repository) GetIngredientPriceById(priceId uint) (*models.IngredientPrice, error) {
+	var price *models.IngredientPrice
+	result := r.db.First(&price, priceId)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return price, nil
+}
+
+// DeleteIngredientPriceById removes a price from the history of its ingredient
+func (r *repository) DeleteIngredientPriceById(priceId uint) error {
+	result := r.db.Delete(&models.IngredientPrice{}, priceId)
+	if err := result.Error; err != nil {
+		return err
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetRecipeIngredientPrices returns the whole price history of the ingredients used by the recipe, the oldest first
+func (r *repository) GetRecipeIngredientPrices(recipeId uint) ([]*models.IngredientPrice, error) {
+	var prices []*models.IngredientPrice
+	result := r.db.Where(recipeIngredientPricesCondition, recipeId).Order("recorded_at").Order("id").Find(&prices)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
@@ -3,29 +3,153 @@ package repositories
 
 import (
 	"fmt"
+	"strings"
+	"unicode"
 
 	"github.com/clementb49/welsh_academy/models" // import models package for Recipe and Ingredient structs
 	"go.uber.org/zap"                            // import logging package
 	"gorm.io/gorm"                               // import gorm package for database operations
+	"gorm.io/gorm/clause"
 )
 
 // RecipeRepository is an interface that defines functions for managing Recipe data in the database
 type RecipeRepository interface {
-	CreateRecipe(recipe *models.Recipe, ingredientsid []uint, userId uint) (*models.Recipe, error)
-	GetAllRecipes(pageSize int, pageNumber int) ([]*models.Recipe, int64, error)
+	CreateRecipe(recipe *models.Recipe) (*models.Recipe, error)
+	GetAllRecipes(filter *RecipeFilter, pageSize int, pageNumber int) ([]*models.Recipe, int64, error)
+	SearchRecipes(query string, pageSize int, pageNumber int) ([]*RecipeSearchResult, int64, error)
+	GetSimilarRecipes(recipeId uint, pageSize int, pageNumber int) ([]*SimilarRecipe, int64, error)
 	GetRecipeById(recipeId uint) (*models.Recipe, error)
+	AddRecipeView(recipeId uint, viewer string) error
+	UpdateRecipe(recipe *models.Recipe, replaceIngredients bool, editorId uint) (*models.Recipe, error)
 	DeleteRecipeById(recipeId uint) error
+	ForkRecipe(recipeId uint, userId uint) (*models.Recipe, error)
 	AddToFavRecipe(userId, recipeId uint) (*models.Recipe, error)
 	DeleteFavRecipe(userId, recipeId uint) error
 	GetAllFavRecipes(pageSize int, pageNumber int, userId uint) ([]*models.Recipe, int64, error)
 }
 
+// Query recording a view of a recipe unless the viewer already viewed it during the last hour
+const addRecipeViewQuery = "INSERT INTO wac_recipe_views (recipe_id, viewer, created_at) SELECT ?, ?, NOW() WHERE NOT EXISTS " +
+	"(SELECT 1 FROM wac_recipe_views WHERE recipe_id = ? AND viewer = ? AND created_at > NOW() - INTERVAL '1 hour')"
+
 // Join query to link recipe and user for favorite recipe
 const favoriteJoinQuery = "JOIN wac_favorites_recipes ON wac_favorites_recipes.recipe_id = wac_recipes.id AND wac_favorites_recipes.user_id = ?"
 
+// Sub queries used to filter the recipes on the ingredients they use
+const (
+	recipeWithIngredientsQuery       = "wac_recipes.id IN (SELECT recipe_id FROM wac_ingredients_recipes WHERE ingredient_id IN ?)"
+	recipeWithAllIngredientsQuery    = "wac_recipes.id IN (SELECT recipe_id FROM wac_ingredients_recipes WHERE ingredient_id IN ? GROUP BY recipe_id HAVING COUNT(DISTINCT ingredient_id) = ?)"
+	recipeWithoutIngredientsQuery    = "wac_recipes.id NOT IN (SELECT recipe_id FROM wac_ingredients_recipes WHERE ingredient_id IN ?)"
+	recipeWithIngredientTypesQuery   = "wac_recipes.id IN (SELECT ir.recipe_id FROM wac_ingredients_recipes ir JOIN wac_ingredients i ON i.id = ir.ingredient_id AND i.deleted_at IS NULL WHERE i.type IN ?)"
+	recipeWithAllIngredientTypeQuery = "wac_recipes.id IN (SELECT ir.recipe_id FROM wac_ingredients_recipes ir JOIN wac_ingredients i ON i.id = ir.ingredient_id AND i.deleted_at IS NULL WHERE i.type IN ? GROUP BY ir.recipe_id HAVING COUNT(DISTINCT i.type) = ?)"
+	recipeWithoutIngredientTypeQuery = "wac_recipes.id NOT IN (SELECT ir.recipe_id FROM wac_ingredients_recipes ir JOIN wac_ingredients i ON i.id = ir.ingredient_id AND i.deleted_at IS NULL WHERE i.type IN ?)"
+)
+
+// Sub query used to filter the recipes using the ingredients or an ingredient they can replace
+const recipeWithIngredientsOrSubstitutesQuery = "wac_recipes.id IN (SELECT recipe_id FROM wac_ingredients_recipes WHERE ingredient_id IN ? OR ingredient_id IN (" + replaceableIngredientsQuery + "))"
+
+// Sub query used to filter out the recipes containing one of the allergens, the deleted ingredients still used by the recipes are checked
+const recipeWithoutAllergensQuery = "wac_recipes.id NOT IN (SELECT ir.recipe_id FROM wac_ingredients_recipes ir JOIN wac_ingredients i ON i.id = ir.ingredient_id WHERE i.allergens & ? <> 0)"
+
+// Sub queries used to filter the recipes on their tags
+const (
+	recipeWithTagsQuery    = "wac_recipes.id IN (SELECT recipe_id FROM wac_tags_recipes WHERE tag_id IN ?)"
+	recipeWithAllTagsQuery = "wac_recipes.id IN (SELECT recipe_id FROM wac_tags_recipes WHERE tag_id IN ? GROUP BY recipe_id HAVING COUNT(DISTINCT tag_id) = ?)"
+)
+
+// Queries used by the full text search, the ? placeholder is always the tsquery. The recipe match its title and its description
+// or the name of one of its ingredient. The ingredient matches increase the rank with the same weight as a 'C' label.
+const (
+	recipeSearchCondition = "(wac_recipes.search_vector @@ to_tsquery('english', ?) OR " + recipeSearchIngredientQuery + ")"
+	recipeSearchSelect    = "wac_recipes.id, ts_rank(wac_recipes.search_vector, to_tsquery('english', ?)) + CASE WHEN " + recipeSearchIngredientQuery + " THEN 0.2 ELSE 0 END AS rank, ts_headline('english', " + recipeSearchEscapedDescription + ", to_tsquery('english', ?), ?) AS snippet"
+	// Description escaped for HTML before its headline is generated, so the snippet can be rendered as HTML with only the <mark> tags
+	recipeSearchEscapedDescription = "replace(replace(replace(wac_recipes.description, '&', '&amp;'), '<', '&lt;'), '>', '&gt;')"
+	// Sub query checking if one of the recipe ingredients match the tsquery
+	recipeSearchIngredientQuery = "wac_recipes.id IN (SELECT ir.recipe_id FROM wac_ingredients_recipes ir JOIN wac_ingredients i ON i.id = ir.ingredient_id AND i.deleted_at IS NULL WHERE i.search_vector @@ to_tsquery('english', ?))"
+	// Options used to generate the highlighted snippet of the description
+	recipeSearchHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2"
+)
+
+// RecipeSearchResult is a recipe returned by the full text search with its rank and its highlighted snippet
+type RecipeSearchResult struct {
+	Recipe  *models.Recipe // the recipe matching the search
+	Rank    float64        // the relevance of the recipe, the higher the better
+	Snippet string         // HTML-safe extract of the description where the searched terms are surrounded by <mark> tags
+}
+
+// Queries used to rank the recipes by similarity with a recipe, the @recipe parameter is the ID of the compared recipe.
+// The ingredient similarity is a Jaccard index where every ingredient is weighted by its rarity: an ingredient used by
+// few recipes, like laverbread, weights more than a common one, like butter. The tag similarity is the Jaccard index of
+// the tags. The score is a weighted sum of both, the recipes sharing no ingredient and no tag are left out.
+const (
+	similarRecipesQuery = "WITH ingredient_weights AS (" + ingredientWeightsQuery + "), " +
+		"recipe_weights AS (SELECT ir.recipe_id, SUM(w.weight) AS total FROM wac_ingredients_recipes ir JOIN ingredient_weights w ON w.ingredient_id = ir.ingredient_id GROUP BY ir.recipe_id), " +
+		"shared_ingredients AS (SELECT o.recipe_id, SUM(w.weight) AS shared FROM wac_ingredients_recipes s " +
+		"JOIN wac_ingredients_recipes o ON o.ingredient_id = s.ingredient_id AND o.recipe_id <> s.recipe_id " +
+		"JOIN ingredient_weights w ON w.ingredient_id = s.ingredient_id WHERE s.recipe_id = @recipe GROUP BY o.recipe_id), " +
+		"recipe_tags AS (SELECT recipe_id, COUNT(*) AS total FROM wac_tags_recipes GROUP BY recipe_id), " +
+		"shared_tags AS (SELECT o.recipe_id, COUNT(*) AS shared FROM wac_tags_recipes s " +
+		"JOIN wac_tags_recipes o ON o.tag_id = s.tag_id AND o.recipe_id <> s.recipe_id WHERE s.recipe_id = @recipe GROUP BY o.recipe_id), " +
+		"similar_recipes AS (SELECT r.id AS recipe_id, " +
+		"COALESCE(si.shared / (sw.total + rw.total - si.shared), 0) AS ingredient_similarity, " +
+		"COALESCE(CAST(st.shared AS float) / (stt.total + rt.total - st.shared), 0) AS tag_similarity " +
+		"FROM wac_recipes r LEFT JOIN shared_ingredients si ON si.recipe_id = r.id LEFT JOIN recipe_weights rw ON rw.recipe_id = r.id " +
+		"LEFT JOIN shared_tags st ON st.recipe_id = r.id LEFT JOIN recipe_tags rt ON rt.recipe_id = r.id " +
+		"CROSS JOIN (SELECT COALESCE(SUM(total), 0) AS total FROM recipe_weights WHERE recipe_id = @recipe) sw " +
+		"CROSS JOIN (SELECT COUNT(*) AS total FROM wac_tags_recipes WHERE recipe_id = @recipe) stt " +
+		"WHERE r.deleted_at IS NULL AND r.id <> @recipe AND (si.recipe_id IS NOT NULL OR st.recipe_id IS NOT NULL)) "
+	similarRecipesCount = "SELECT COUNT(*) FROM similar_recipes"
+	similarRecipesPage  = "SELECT recipe_id, ingredient_similarity, tag_similarity, " +
+		"0.8 * ingredient_similarity + 0.2 * tag_similarity AS score FROM similar_recipes " +
+		"ORDER BY score DESC, recipe_id LIMIT @limit OFFSET @offset"
+	// the weight of every ingredient is its inverse document frequency among the recipes which aren't deleted
+	ingredientWeightsQuery = "SELECT ir.ingredient_id, LN(CAST((SELECT COUNT(*) FROM wac_recipes WHERE deleted_at IS NULL) AS float) / COUNT(*)) + 1 AS weight " +
+		"FROM wac_ingredients_recipes ir JOIN wac_recipes r ON r.id = ir.recipe_id AND r.deleted_at IS NULL GROUP BY ir.ingredient_id"
+)
+
+// SimilarRecipe is a recipe returned with its similarity with another recipe, the similarities are between 0 and 1
+type SimilarRecipe struct {
+	Recipe               *models.Recipe // the recipe similar to the compared one
+	Score                float64        // the overall similarity, 80% from the ingredients and 20% from the tags
+	IngredientSimilarity float64        // the similarity of the ingredients weighted by their rarity
+	TagSimilarity        float64        // the similarity of the tags
+}
+
+// RecipeFilter defines the criteria used to filter the recipe listing, an empty criteria is ignored
+type RecipeFilter struct {
+	IncludeIngredients []uint           // ingredients the recipe must use
+	ExcludeIngredients []uint           // ingredients the recipe must not use
+	IncludeTypes       []string         // ingredient types the recipe must use
+	ExcludeTypes       []string         // ingredient types the recipe must not use
+	IncludeTags        []uint           // tags classifying the recipe
+	ExcludeAllergens   models.Allergens // allergens the recipe must not contain
+	MatchAll           bool             // when true the recipe must use all the included ingredients, types and tags, otherwise one of them is enough
+	AllowSubstitutes   bool             // when true a recipe using an ingredient which can be replaced by an included ingredient is kept
+	Sort               string           // the order of the recipes: newest, oldest, rating, reviews or title, by ID when it's empty
+}
+
+// The orders of the recipe listing indexed by the sort criteria of the filter, the ID makes the pages stable
+var recipeSortOrders = map[string]string{
+	"newest":  "wac_recipes.created_at DESC, wac_recipes.id DESC",
+	"oldest":  "wac_recipes.created_at, wac_recipes.id",
+	"rating":  "wac_recipes.rating_average DESC, wac_recipes.rating_count DESC, wac_recipes.id",
+	"reviews": "wac_recipes.rating_count DESC, wac_recipes.rating_average DESC, wac_recipes.id",
+	"title":   "wac_recipes.title, wac_recipes.id",
+}
+
+// The fields omitted when the recipe is updated: the associations are saved separately
+// and the counters are maintained with the tables they count
+var recipeUpdateOmits = []string{clause.Associations, "RatingAverage", "RatingCount", "CommentCount", "ForkCount"}
+
+// Query counting the forks of a recipe
+const updateRecipeForkCountQuery = "UPDATE wac_recipes SET fork_count = (SELECT COUNT(*) FROM wac_recipes f WHERE f.parent_id = ? AND f.deleted_at IS NULL) WHERE id = ?"
+
 // ErrRecipeNotAcceptable is an error that is returned when a Recipe cannot be created due to missing Ingredients in the database
 var ErrRecipeNotAcceptable = fmt.Errorf("missing ingredient in the database, recipe not acceptable")
 
+// ErrRecipeDuplicatedIngredient is an error that is returned when a Recipe uses the same Ingredient on several lines
+var ErrRecipeDuplicatedIngredient = fmt.Errorf("the same ingredient is used on several lines, recipe not acceptable")
+
 // NewRecipeRepository is a function that returns an implementation of RecipeRepository using the given database connection
 func NewRecipeRepository(db *gorm.DB) RecipeRepository {
 	return &repository{
@@ -34,61 +158,470 @@ func NewRecipeRepository(db *gorm.DB) RecipeRepository {
 	}
 }
 
-// CreateRecipe is a function that creates a new Recipe record in the database
-func (r *repository) CreateRecipe(input *models.Recipe, ingredientsId []uint, userId uint) (*models.Recipe, error) {
-	db := r.db.Model(input)
+// CreateRecipe is a function that creates a new Recipe record with its ingredient lines in the database,
+// the recipe is saved as its first revision
+func (r *repository) CreateRecipe(input *models.Recipe) (*models.Recipe, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Omit(clause.Associations).Create(input)
+		if err := result.Error; err != nil {
+			return err
+		}
+		err := saveRecipeIngredients(tx, input)
+		if err != nil {
+			return err
+		}
+		return saveRecipeRevision(tx, input, uint(input.AuthorID))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return input, nil
+}
+
+// saveRecipeIngredients checks that all the ingredients used by the recipe lines exist and saves the lines,
+// the ingredient of each line is loaded to be returned with the recipe
+func saveRecipeIngredients(tx *gorm.DB, recipe *models.Recipe) error {
+	ingredientsId := make([]uint, len(recipe.Ingredients))
+	for i, line := range recipe.Ingredients {
+		ingredientsId[i] = line.IngredientID
+	}
+	if len(uniqueValues(ingredientsId)) != len(ingredientsId) {
+		return ErrRecipeDuplicatedIngredient
+	}
 	var ingredients []*models.Ingredient
-	result := r.db.Where("id IN ?", ingredientsId).Find(&ingredients)
+	result := tx.Where("id IN ?", ingredientsId).Find(&ingredients)
 	if err := result.Error; err != nil {
-		return nil, err
+		return err
 	}
 	if result.RowsAffected != int64(len(ingredientsId)) {
-		return nil, ErrRecipeNotAcceptable
+		return ErrRecipeNotAcceptable
+	}
+	if len(recipe.Ingredients) == 0 {
+		return nil
 	}
-	input.Ingredients = ingredients
-	result = db.Debug().Save(input)
+	for _, line := range recipe.Ingredients {
+		line.RecipeID = recipe.ID
+	}
+	result = tx.Omit(clause.Associations).Create(recipe.Ingredients)
 	if err := result.Error; err != nil {
-		return nil, err
+		return err
 	}
-	return input, nil
+	ingredientsById := make(map[uint]*models.Ingredient, len(ingredients))
+	for _, ingredient := range ingredients {
+		ingredientsById[ingredient.ID] = ingredient
+	}
+	for _, line := range recipe.Ingredients {
+		line.Ingredient = ingredientsById[line.IngredientID]
+	}
+	return nil
+}
+
+// preloadRecipeDetails loads the ingredient lines, the tags and the photos of the recipes
+func preloadRecipeDetails(db *gorm.DB) *gorm.DB {
+	return preloadRecipeImages(preloadRecipeTags(preloadRecipeIngredients(db)))
+}
+
+// preloadRecipeIngredients loads the ingredient lines of the recipes in their order with the nutrition facts, the latest price,
+// the image and the substitutes of their ingredient, the deleted ingredients are still loaded to not break the recipes using them
+func preloadRecipeIngredients(db *gorm.DB) *gorm.DB {
+	return db.Preload("Ingredients", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position")
+	}).Preload("Ingredients.Ingredient", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).Preload("Ingredients.Ingredient.Nutrition").Preload("Ingredients.Ingredient.Price", preloadLatestIngredientPrice).
+		Preload("Ingredients.Ingredient.Image").
+		Preload("Ingredients.Ingredient.Substitutes.Substitute").
+		Preload("Ingredients.Ingredient.ReplacedIngredients", "bidirectional = ?", true).
+		Preload("Ingredients.Ingredient.ReplacedIngredients.Ingredient")
+}
+
+// preloadRecipeTags loads the tags of the recipes ordered by category and name
+func preloadRecipeTags(db *gorm.DB) *gorm.DB {
+	return db.Preload("Tags", func(db *gorm.DB) *gorm.DB {
+		return db.Order("category").Order("name")
+	})
+}
+
+// preloadRecipeImages loads the photos of the recipes, the cover first
+func preloadRecipeImages(db *gorm.DB) *gorm.DB {
+	return db.Preload("Images", orderRecipeImages)
 }
 
-// GetAllRecipes returns all recipes with pagination
-func (r *repository) GetAllRecipes(pageSize int, pageNumber int) ([]*models.Recipe, int64, error) {
+// GetAllRecipes returns all recipes matching the filter with pagination
+func (r *repository) GetAllRecipes(filter *RecipeFilter, pageSize int, pageNumber int) ([]*models.Recipe, int64, error) {
 	var recipes []*models.Recipe
 	var totalRecipes int64
-	db := r.db.Model(&models.Recipe{})
+	db := applyRecipeFilter(r.db.Model(&models.Recipe{}), filter).Session(&gorm.Session{})
 	result := db.Count(&totalRecipes)
 	if err := result.Error; err != nil {
 		return nil, 0, err
 	}
-	result = r.db.Offset(pageNumber * pageSize).Limit(pageSize).Find(&recipes)
+	result = preloadRecipeDetails(db).Order(recipeOrder(filter)).Offset(pageNumber * pageSize).Limit(pageSize).Find(&recipes)
 	if err := result.Error; err != nil {
 		return nil, 0, err
 	}
 	return recipes, totalRecipes, nil
 }
 
-// GetRecipeById return a recipe by ID
+// recipeOrder returns the order of the recipe listing defined by the filter
+func recipeOrder(filter *RecipeFilter) string {
+	if filter != nil {
+		if order, ok := recipeSortOrders[filter.Sort]; ok {
+			return order
+		}
+	}
+	return "wac_recipes.id"
+}
+
+// applyRecipeFilter adds to the recipe query the conditions defined by the filter
+func applyRecipeFilter(db *gorm.DB, filter *RecipeFilter) *gorm.DB {
+	if filter == nil {
+		return db
+	}
+	if ingredientsId := uniqueValues(filter.IncludeIngredients); len(ingredientsId) > 0 && filter.AllowSubstitutes {
+		if filter.MatchAll {
+			// each ingredient must be used or replaced so they are checked one by one
+			for _, ingredientId := range ingredientsId {
+				ids := []uint{ingredientId}
+				db = db.Where(recipeWithIngredientsOrSubstitutesQuery, ids, ids, ids)
+			}
+		} else {
+			db = db.Where(recipeWithIngredientsOrSubstitutesQuery, ingredientsId, ingredientsId, ingredientsId)
+		}
+	} else if len(ingredientsId) > 0 {
+		if filter.MatchAll {
+			db = db.Where(recipeWithAllIngredientsQuery, ingredientsId, len(ingredientsId))
+		} else {
+			db = db.Where(recipeWithIngredientsQuery, ingredientsId)
+		}
+	}
+	if len(filter.ExcludeIngredients) > 0 {
+		db = db.Where(recipeWithoutIngredientsQuery, filter.ExcludeIngredients)
+	}
+	if types := uniqueValues(filter.IncludeTypes); len(types) > 0 {
+		if filter.MatchAll {
+			db = db.Where(recipeWithAllIngredientTypeQuery, types, len(types))
+		} else {
+			db = db.Where(recipeWithIngredientTypesQuery, types)
+		}
+	}
+	if len(filter.ExcludeTypes) > 0 {
+		db = db.Where(recipeWithoutIngredientTypeQuery, filter.ExcludeTypes)
+	}
+	if filter.ExcludeAllergens != 0 {
+		db = db.Where(recipeWithoutAllergensQuery, filter.ExcludeAllergens)
+	}
+	if tagsId := uniqueValues(filter.IncludeTags); len(tagsId) > 0 {
+		if filter.MatchAll {
+			db = db.Where(recipeWithAllTagsQuery, tagsId, len(tagsId))
+		} else {
+			db = db.Where(recipeWithTagsQuery, tagsId)
+		}
+	}
+	return db
+}
+
+// SearchRecipes returns the recipes matching the full text search ordered by relevance with pagination
+func (r *repository) SearchRecipes(query string, pageSize int, pageNumber int) ([]*RecipeSearchResult, int64, error) {
+	tsQuery := buildPrefixTsQuery(query)
+	if tsQuery == "" {
+		return []*RecipeSearchResult{}, 0, nil
+	}
+	var totalRecipes int64
+	db := r.db.Model(&models.Recipe{}).Where(recipeSearchCondition, tsQuery, tsQuery).Session(&gorm.Session{})
+	err := db.Count(&totalRecipes).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	var rows []struct {
+		ID      uint
+		Rank    float64
+		Snippet string
+	}
+	err = db.Select(recipeSearchSelect, tsQuery, tsQuery, tsQuery, recipeSearchHeadlineOptions).
+		Order("rank DESC").Order("wac_recipes.id").
+		Offset(pageNumber * pageSize).Limit(pageSize).Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	recipesId := make([]uint, len(rows))
+	for i, row := range rows {
+		recipesId[i] = row.ID
+	}
+	var recipes []*models.Recipe
+	err = preloadRecipeDetails(r.db).Find(&recipes, recipesId).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	recipesById := make(map[uint]*models.Recipe, len(recipes))
+	for _, recipe := range recipes {
+		recipesById[recipe.ID] = recipe
+	}
+	results := make([]*RecipeSearchResult, 0, len(rows))
+	for _, row := range rows {
+		if recipe, ok := recipesById[row.ID]; ok {
+			results = append(results, &RecipeSearchResult{Recipe: recipe, Rank: row.Rank, Snippet: row.Snippet})
+		}
+	}
+	return results, totalRecipes, nil
+}
+
+// GetSimilarRecipes returns a page of the recipes sharing ingredients or tags with the recipe, the most similar first.
+// The recipes are ranked in the database, only the recipes of the page are loaded.
+func (r *repository) GetSimilarRecipes(recipeId uint, pageSize int, pageNumber int) ([]*SimilarRecipe, int64, error) {
+	args := map[string]interface{}{"recipe": recipeId}
+	var totalRecipes int64
+	err := r.db.Raw(similarRecipesQuery+similarRecipesCount, args).Scan(&totalRecipes).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	var rows []struct {
+		RecipeID             uint
+		IngredientSimilarity float64
+		TagSimilarity        float64
+		Score                float64
+	}
+	args["limit"] = pageSize
+	args["offset"] = pageNumber * pageSize
+	err = r.db.Raw(similarRecipesQuery+similarRecipesPage, args).Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(rows) == 0 {
+		return []*SimilarRecipe{}, totalRecipes, nil
+	}
+	recipesId := make([]uint, len(rows))
+	for i, row := range rows {
+		recipesId[i] = row.RecipeID
+	}
+	var recipes []*models.Recipe
+	err = preloadRecipeDetails(r.db).Find(&recipes, recipesId).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	recipesById := make(map[uint]*models.Recipe, len(recipes))
+	for _, recipe := range recipes {
+		recipesById[recipe.ID] = recipe
+	}
+	results := make([]*SimilarRecipe, 0, len(rows))
+	for _, row := range rows {
+		if recipe, ok := recipesById[row.RecipeID]; ok {
+			results = append(results, &SimilarRecipe{
+				Recipe:               recipe,
+				Score:                row.Score,
+				IngredientSimilarity: row.IngredientSimilarity,
+				TagSimilarity:        row.TagSimilarity,
+			})
+		}
+	}
+	return results, totalRecipes, nil
+}
+
+// buildPrefixTsQuery converts a free text search into a tsquery where every word is matched as a prefix,
+// the characters which are not letters or digits are dropped so the user input can't break the tsquery syntax
+func buildPrefixTsQuery(query string) string {
+	words := strings.FieldsFunc(query, func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c)
+	})
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+// GetRecipeById return a recipe by ID with its ingredient lines, its preparation steps, the recipe it was forked from and its forks
 func (r *repository) GetRecipeById(recipeId uint) (*models.Recipe, error) {
 	var recipe *models.Recipe
-	result := r.db.First(&recipe, recipeId)
+	result := preloadRecipeLineage(preloadOrderedSteps(preloadRecipeDetails(r.db))).First(&recipe, recipeId)
 	if err := result.Error; err != nil {
 		return nil, err
 	}
 	return recipe, nil
 }
 
-// DeleteRecipeById delete a recipe by ID
+// AddRecipeView records a view of the recipe by the viewer, the views are used to compute the trending recipes
+// and the viewer is counted once per hour
+func (r *repository) AddRecipeView(recipeId uint, viewer string) error {
+	return r.db.Exec(addRecipeViewQuery, recipeId, viewer, recipeId, viewer).Error
+}
+
+// UpdateRecipe saves the recipe fields, the ingredient lines are replaced by the recipe ones only when replaceIngredients is true.
+// The rating and the number of comments of the recipe aren't saved because they are maintained with the reviews and the comments.
+// The saved recipe is added to its revisions as edited by the editor, its ingredient lines must be loaded when they aren't replaced.
+func (r *repository) UpdateRecipe(input *models.Recipe, replaceIngredients bool, editorId uint) (*models.Recipe, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := saveRecipeFirstRevision(tx, input.ID)
+		if err != nil {
+			return err
+		}
+		result := tx.Omit(recipeUpdateOmits...).Save(input)
+		if err := result.Error; err != nil {
+			return err
+		}
+		if replaceIngredients {
+			result = tx.Where("recipe_id = ?", input.ID).Delete(&models.RecipeIngredient{})
+			if err := result.Error; err != nil {
+				return err
+			}
+			err = saveRecipeIngredients(tx, input)
+			if err != nil {
+				return err
+			}
+			err = deleteRemovedStepIngredients(tx, input.ID)
+			if err != nil {
+				return err
+			}
+		}
+		return saveRecipeRevision(tx, input, editorId)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return input, nil
+}
+
+// preloadRecipeLineage loads the title and the author of the recipe it was forked from and of its forks
+func preloadRecipeLineage(db *gorm.DB) *gorm.DB {
+	return db.Preload("Parent", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "title", "author_id")
+	}).Preload("Forks", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "title", "author_id", "parent_id").Order("id")
+	})
+}
+
+// DeleteRecipeById delete a recipe by ID with its photos, the number of forks of the recipe it was forked from is updated
 func (r *repository) DeleteRecipeById(recipeId uint) error {
-	result := r.db.Delete(&models.Recipe{}, recipeId)
-	if err := result.Error; err != nil {
-		return err
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var recipe *models.Recipe
+		result := tx.Select("id", "parent_id").First(&recipe, recipeId)
+		if err := result.Error; err != nil {
+			return err
+		}
+		result = tx.Delete(recipe)
+		if err := result.Error; err != nil {
+			return err
+		}
+		result = tx.Where("recipe_id = ?", recipeId).Delete(&models.RecipeImage{})
+		if err := result.Error; err != nil {
+			return err
+		}
+		if recipe.ParentID == nil {
+			return nil
+		}
+		return tx.Exec(updateRecipeForkCountQuery, *recipe.ParentID, *recipe.ParentID).Error
+	})
+}
+
+// ForkRecipe copies the recipe with its ingredient lines, its preparation steps and its tags as a new recipe of the user.
+// The fork gets a unique title derived from the original one and starts its own revision history.
+func (r *repository) ForkRecipe(recipeId uint, userId uint) (*models.Recipe, error) {
+	var fork *models.Recipe
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var original *models.Recipe
+		result := preloadOrderedSteps(preloadRecipeDetails(tx)).First(&original, recipeId)
+		if err := result.Error; err != nil {
+			return err
+		}
+		title, err := uniqueForkTitle(tx, original.Title)
+		if err != nil {
+			return err
+		}
+		fork = &models.Recipe{
+			Title:               title,
+			Description:         original.Description,
+			Difficulty:          original.Difficulty,
+			Servings:            original.Servings,
+			OvenTemperature:     original.OvenTemperature,
+			OvenTemperatureUnit: original.OvenTemperatureUnit,
+			AuthorID:            uint64(userId),
+			ParentID:            &original.ID,
+		}
+		result = tx.Omit(clause.Associations).Create(fork)
+		if err := result.Error; err != nil {
+			return err
+		}
+		// the lines are copied as is, a fork keeps the deleted ingredients used by the original recipe
+		fork.Ingredients = make([]*models.RecipeIngredient, len(original.Ingredients))
+		for i, line := range original.Ingredients {
+			fork.Ingredients[i] = &models.RecipeIngredient{
+				RecipeID:     fork.ID,
+				IngredientID: line.IngredientID,
+				Ingredient:   line.Ingredient,
+				Position:     line.Position,
+				Quantity:     line.Quantity,
+				Unit:         line.Unit,
+				Note:         line.Note,
+				Optional:     line.Optional,
+			}
+		}
+		if len(fork.Ingredients) > 0 {
+			result = tx.Omit(clause.Associations).Create(fork.Ingredients)
+			if err := result.Error; err != nil {
+				return err
+			}
+		}
+		for _, step := range original.Steps {
+			forkStep := &models.RecipeStep{
+				RecipeID:    fork.ID,
+				Position:    step.Position,
+				Text:        step.Text,
+				Duration:    step.Duration,
+				Ingredients: step.Ingredients,
+			}
+			result = tx.Omit("Ingredients.*").Create(forkStep)
+			if err := result.Error; err != nil {
+				return err
+			}
+		}
+		for _, tag := range original.Tags {
+			result = tx.Exec(addTagToRecipeQuery, fork.ID, tag.ID)
+			if err := result.Error; err != nil {
+				return err
+			}
+		}
+		err = saveRecipeRevision(tx, fork, userId)
+		if err != nil {
+			return err
+		}
+		return tx.Exec(updateRecipeForkCountQuery, original.ID, original.ID).Error
+	})
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return r.GetRecipeById(fork.ID)
+}
+
+// uniqueForkTitle returns the title of the original recipe followed by the fork suffix and a number when it's already used.
+// The deleted recipes are checked because their title is still in the unique index.
+func uniqueForkTitle(tx *gorm.DB, title string) (string, error) {
+	for n := 1; ; n++ {
+		suffix := " (fork)"
+		if n > 1 {
+			suffix = fmt.Sprintf(" (fork %d)", n)
+		}
+		candidate := truncateRunes(title, 200-len(suffix)) + suffix
+		var nbRecipes int64
+		result := tx.Unscoped().Model(&models.Recipe{}).Where("title = ?", candidate).Count(&nbRecipes)
+		if err := result.Error; err != nil {
+			return "", err
+		}
+		if nbRecipes == 0 {
+			return candidate, nil
+		}
+	}
+}
+
+// truncateRunes cuts the text to at most max characters
+func truncateRunes(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max])
 }
 
-// AddToFavRecipe add the recipe to the user favorite
+// AddToFavRecipe add the recipe to the user favorite, only the join row is written so the counters of the recipe
+// maintained by the reviews, the comments and the forks aren't overwritten
 func (r *repository) AddToFavRecipe(userId, recipeId uint) (*models.Recipe, error) {
 	var recipe *models.Recipe
 	result := r.db.First(&recipe, recipeId)
@@ -104,10 +637,6 @@ func (r *repository) AddToFavRecipe(userId, recipeId uint) (*models.Recipe, erro
 	if err != nil {
 		return nil, err
 	}
-	result = r.db.Save(&recipe)
-	if err := result.Error; err != nil {
-		return nil, err
-	}
 	return recipe, nil
 }
 
@@ -127,10 +656,6 @@ func (r *repository) DeleteFavRecipe(userId, recipeId uint) error {
 	if err != nil {
 		return err
 	}
-	result = r.db.Save(&recipe)
-	if err := result.Error; err != nil {
-		return err
-	}
 	return nil
 }
 
@@ -143,7 +668,7 @@ func (r *repository) GetAllFavRecipes(pageSize int, pageNumber int, userId uint)
 	if err != nil {
 		return nil, 0, err
 	}
-	err = db.Offset(pageNumber * pageSize).Limit(pageSize).Find(&recipes).Error
+	err = preloadRecipeDetails(db).Offset(pageNumber * pageSize).Limit(pageSize).Find(&recipes).Error
 	if err != nil {
 		return nil, 0, err
 	}
@@ -0,0 +1,113 @@
+// package repositories defines interfaces for managing recipe revision data in the database
+package repositories
+
+import (
+	"github.com/clementb49/welsh_academy/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// RecipeRevisionRepository is an interface that defines functions for reading the revision history of the recipes in the database.
+// The revisions are saved by the recipe repository in the same transaction as the recipe.
+type RecipeRevisionRepository interface {
+	GetAllRecipeRevisions(recipeId uint, pageSize, pageNumber int) ([]*models.RecipeRevision, int64, error)
+	GetRecipeRevision(recipeId, number uint) (*models.RecipeRevision, error)
+}
+
+// NewRecipeRevisionRepository returns a new instance of the RecipeRevisionRepository interface
+func NewRecipeRevisionRepository(db *gorm.DB) RecipeRevisionRepository {
+	return &repository{
+		db:     db,
+		logger: zap.L(),
+	}
+}
+
+// GetAllRecipeRevisions returns a page of the revisions of the recipe, the most recent first
+func (r *repository) GetAllRecipeRevisions(recipeId uint, pageSize, pageNumber int) ([]*models.RecipeRevision, int64, error) {
+	var revisions []*models.RecipeRevision
+	var totalRevisions int64
+	db := r.db.Model(&models.RecipeRevision{}).Where("recipe_id = ?", recipeId).Session(&gorm.Session{})
+	err := db.Count(&totalRevisions).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	err = db.Order("number DESC").Offset(pageNumber * pageSize).Limit(pageSize).Find(&revisions).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return revisions, totalRevisions, nil
+}
+
+// GetRecipeRevision returns a revision of the recipe by its number
+func (r *repository) GetRecipeRevision(recipeId, number uint) (*models.RecipeRevision, error) {
+	var revision *models.RecipeRevision
+	result := r.db.Where("recipe_id = ? AND number = ?", recipeId, number).First(&revision)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+// saveRecipeRevision saves a snapshot of the recipe with its ingredient lines as the next revision of the recipe.
+// It must be called in the transaction saving the recipe: the row of the recipe is locked by its update
+// so the concurrent updates can't get the same number.
+func saveRecipeRevision(tx *gorm.DB, recipe *models.Recipe, editorId uint) error {
+	lastNumber, err := lastRecipeRevisionNumber(tx, recipe.ID)
+	if err != nil {
+		return err
+	}
+	return tx.Create(newRecipeRevision(recipe, editorId, lastNumber+1)).Error
+}
+
+// saveRecipeFirstRevision saves the stored version of the recipe as its first revision when it has none,
+// the recipes created before the revision history keep their original version this way
+func saveRecipeFirstRevision(tx *gorm.DB, recipeId uint) error {
+	lastNumber, err := lastRecipeRevisionNumber(tx, recipeId)
+	if err != nil || lastNumber > 0 {
+		return err
+	}
+	var stored *models.Recipe
+	err = preloadRecipeDetails(tx).First(&stored, recipeId).Error
+	if err != nil {
+		return err
+	}
+	revision := newRecipeRevision(stored, uint(stored.AuthorID), 1)
+	revision.CreatedAt = stored.UpdatedAt
+	return tx.Create(revision).Error
+}
+
+// lastRecipeRevisionNumber returns the number of the last revision of the recipe, 0 when it has no revision
+func lastRecipeRevisionNumber(tx *gorm.DB, recipeId uint) (uint, error) {
+	var lastNumber uint
+	err := tx.Model(&models.RecipeRevision{}).Select("COALESCE(MAX(number), 0)").Where("recipe_id = ?", recipeId).Scan(&lastNumber).Error
+	return lastNumber, err
+}
+
+// newRecipeRevision copies the fields and the ingredient lines of the recipe in a revision
+func newRecipeRevision(recipe *models.Recipe, editorId uint, number uint) *models.RecipeRevision {
+	ingredients := make(models.RecipeRevisionIngredients, len(recipe.Ingredients))
+	for i, line := range recipe.Ingredients {
+		ingredients[i] = &models.RecipeRevisionIngredient{
+			IngredientID: line.IngredientID,
+			Quantity:     line.Quantity,
+			Unit:         line.Unit,
+			Note:         line.Note,
+			Optional:     line.Optional,
+		}
+		if line.Ingredient != nil {
+			ingredients[i].Name = line.Ingredient.Name
+		}
+	}
+	return &models.RecipeRevision{
+		RecipeID:            recipe.ID,
+		Number:              number,
+		EditorID:            editorId,
+		Title:               recipe.Title,
+		Description:         recipe.Description,
+		Difficulty:          recipe.Difficulty,
+		Servings:            recipe.Servings,
+		OvenTemperature:     recipe.OvenTemperature,
+		OvenTemperatureUnit: recipe.OvenTemperatureUnit,
+		Ingredients:         ingredients,
+	}
+}
@@ -0,0 +1,178 @@
+// package repositories defines interfaces for managing recipe recommendation data in the database
+package repositories
+
+import (
+	"github.com/clementb49/welsh_academy/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// RecommendationRepository is an interface that defines functions for computing and reading the recipes recommended to the users
+type RecommendationRepository interface {
+	RefreshRecommendations(maxPerUser int) error
+	GetRecommendedRecipes(userId uint, pageSize, pageNumber int) ([]*RecommendedRecipe, int64, error)
+	GetColdStartRecipes(userId uint, pageSize, pageNumber int) ([]*RecommendedRecipe, int64, error)
+}
+
+// The sources of the recommended recipes
+const (
+	RecommendationSourceFavorites   = "favorites"   // the recipe is favored by the users who favored the same recipes
+	RecommendationSourceIngredients = "ingredients" // the recipe uses the ingredients the user likes
+	RecommendationSourcePopular     = "popular"     // the recipe is one of the best rated ones
+)
+
+// Queries used to compute the recommendations with an item-based collaborative filtering on the favorite recipes.
+// The similarity of two recipes is the cosine of their favorites: the number of users who favored both divided by the
+// square root of the product of their number of favorites. The score of a recipe for a user is the sum of its similarities
+// with the favorites of the user, the favorites and the recipes of the user aren't recommended.
+const (
+	deleteRecommendationsQuery  = "DELETE FROM wac_recipe_recommendations"
+	computeRecommendationsQuery = "INSERT INTO wac_recipe_recommendations (user_id, recipe_id, score, created_at) " +
+		"WITH favorite_counts AS (SELECT f.recipe_id, COUNT(*) AS total FROM wac_favorites_recipes f " +
+		"JOIN wac_recipes r ON r.id = f.recipe_id AND r.deleted_at IS NULL GROUP BY f.recipe_id), " +
+		"recipe_similarities AS (SELECT a.recipe_id, b.recipe_id AS similar_id, COUNT(*) / SQRT(ca.total * cb.total) AS similarity " +
+		"FROM wac_favorites_recipes a JOIN wac_favorites_recipes b ON b.user_id = a.user_id AND b.recipe_id <> a.recipe_id " +
+		"JOIN favorite_counts ca ON ca.recipe_id = a.recipe_id JOIN favorite_counts cb ON cb.recipe_id = b.recipe_id " +
+		"GROUP BY a.recipe_id, b.recipe_id, ca.total, cb.total), " +
+		"user_scores AS (SELECT f.user_id, s.similar_id AS recipe_id, SUM(s.similarity) AS score, " +
+		"ROW_NUMBER() OVER (PARTITION BY f.user_id ORDER BY SUM(s.similarity) DESC, s.similar_id) AS position " +
+		"FROM wac_favorites_recipes f JOIN recipe_similarities s ON s.recipe_id = f.recipe_id " +
+		"JOIN wac_recipes r ON r.id = s.similar_id AND r.author_id <> f.user_id " +
+		"WHERE s.similar_id NOT IN (SELECT recipe_id FROM wac_favorites_recipes WHERE user_id = f.user_id) " +
+		"GROUP BY f.user_id, s.similar_id) " +
+		"SELECT user_id, recipe_id, score, NOW() FROM user_scores WHERE position <= ?"
+	// the recipes favored since the last computation are left out
+	recommendedRecipesCondition = "wac_recipe_recommendations.user_id = ? AND wac_recipe_recommendations.recipe_id NOT IN (SELECT recipe_id FROM wac_favorites_recipes WHERE user_id = ?)"
+	recommendedRecipesJoinQuery = "JOIN wac_recipes ON wac_recipes.id = wac_recipe_recommendations.recipe_id AND wac_recipes.deleted_at IS NULL"
+)
+
+// Queries used to recommend recipes to a user without computed recommendations, the @user parameter is the user ID.
+// The ingredients the user likes are the ones of their favorites, of their recipes and of their pantry. The score of a
+// recipe is the part of its ingredients the user likes weighted by their rarity, the best rated recipes come first when
+// the scores are equal so a user without any liked ingredient gets the popular recipes.
+const (
+	coldStartRecipesQuery = "WITH ingredient_weights AS (" + ingredientWeightsQuery + "), " +
+		"liked_ingredients AS (SELECT ir.ingredient_id FROM wac_ingredients_recipes ir JOIN wac_favorites_recipes f ON f.recipe_id = ir.recipe_id AND f.user_id = @user " +
+		"UNION SELECT ir.ingredient_id FROM wac_ingredients_recipes ir JOIN wac_recipes r ON r.id = ir.recipe_id AND r.deleted_at IS NULL AND r.author_id = @user " +
+		"UNION SELECT ingredient_id FROM wac_pantries_ingredients WHERE user_id = @user), " +
+		"recipe_scores AS (SELECT r.id AS recipe_id, r.rating_average, r.rating_count, " +
+		"COALESCE(SUM(w.weight) FILTER (WHERE ir.ingredient_id IN (SELECT ingredient_id FROM liked_ingredients)) / SUM(w.weight), 0) AS score " +
+		"FROM wac_recipes r LEFT JOIN wac_ingredients_recipes ir ON ir.recipe_id = r.id LEFT JOIN ingredient_weights w ON w.ingredient_id = ir.ingredient_id " +
+		"WHERE r.deleted_at IS NULL AND r.author_id <> @user AND r.id NOT IN (SELECT recipe_id FROM wac_favorites_recipes WHERE user_id = @user) " +
+		"GROUP BY r.id) "
+	coldStartRecipesCount = "SELECT COUNT(*) FROM recipe_scores"
+	coldStartRecipesPage  = "SELECT recipe_id, score FROM recipe_scores " +
+		"ORDER BY score DESC, rating_average DESC, rating_count DESC, recipe_id LIMIT @limit OFFSET @offset"
+)
+
+// RecommendedRecipe is a recipe recommended to a user with its score and the source of the recommendation
+type RecommendedRecipe struct {
+	Recipe *models.Recipe // the recommended recipe
+	Score  float64        // the relevance of the recommendation, the higher the better
+	Source string         // the source of the recommendation: favorites, ingredients or popular
+}
+
+// NewRecommendationRepository returns a new instance of the RecommendationRepository interface
+func NewRecommendationRepository(db *gorm.DB) RecommendationRepository {
+	return &repository{
+		db:     db,
+		logger: zap.L(),
+	}
+}
+
+// RefreshRecommendations replaces the recommendations of all the users by the ones computed from the current favorites,
+// at most maxPerUser recipes are kept for every user. The readers see the former recommendations until the end of the computation.
+func (r *repository) RefreshRecommendations(maxPerUser int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(deleteRecommendationsQuery).Error
+		if err != nil {
+			return err
+		}
+		return tx.Exec(computeRecommendationsQuery, maxPerUser).Error
+	})
+}
+
+// GetRecommendedRecipes returns a page of the recipes recommended to the user by the last computation, the most relevant first
+func (r *repository) GetRecommendedRecipes(userId uint, pageSize, pageNumber int) ([]*RecommendedRecipe, int64, error) {
+	var totalRecipes int64
+	db := r.db.Model(&models.RecipeRecommendation{}).Joins(recommendedRecipesJoinQuery).
+		Where(recommendedRecipesCondition, userId, userId).Session(&gorm.Session{})
+	err := db.Count(&totalRecipes).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	var rows []*recommendationRow
+	err = db.Select("wac_recipe_recommendations.recipe_id, wac_recipe_recommendations.score").
+		Order("wac_recipe_recommendations.score DESC").Order("wac_recipe_recommendations.recipe_id").
+		Offset(pageNumber * pageSize).Limit(pageSize).Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	recipes, err := r.loadRecommendedRecipes(rows, RecommendationSourceFavorites)
+	if err != nil {
+		return nil, 0, err
+	}
+	return recipes, totalRecipes, nil
+}
+
+// GetColdStartRecipes returns a page of the recipes using the ingredients the user likes, the most relevant first,
+// it's used when no recommendation was computed for the user
+func (r *repository) GetColdStartRecipes(userId uint, pageSize, pageNumber int) ([]*RecommendedRecipe, int64, error) {
+	args := map[string]interface{}{"user": userId}
+	var totalRecipes int64
+	err := r.db.Raw(coldStartRecipesQuery+coldStartRecipesCount, args).Scan(&totalRecipes).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	var rows []*recommendationRow
+	args["limit"] = pageSize
+	args["offset"] = pageNumber * pageSize
+	err = r.db.Raw(coldStartRecipesQuery+coldStartRecipesPage, args).Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	recipes, err := r.loadRecommendedRecipes(rows, RecommendationSourceIngredients)
+	if err != nil {
+		return nil, 0, err
+	}
+	// a recipe using none of the liked ingredients is only recommended for its rating
+	for _, recipe := range recipes {
+		if recipe.Score == 0 {
+			recipe.Source = RecommendationSourcePopular
+		}
+	}
+	return recipes, totalRecipes, nil
+}
+
+// recommendationRow is a recommended recipe ID with its score read from the database
+type recommendationRow struct {
+	RecipeID uint
+	Score    float64
+}
+
+// loadRecommendedRecipes loads the recipes of the rows with their ingredients and keeps the order of the rows
+func (r *repository) loadRecommendedRecipes(rows []*recommendationRow, source string) ([]*RecommendedRecipe, error) {
+	if len(rows) == 0 {
+		return []*RecommendedRecipe{}, nil
+	}
+	recipesId := make([]uint, len(rows))
+	for i, row := range rows {
+		recipesId[i] = row.RecipeID
+	}
+	var recipes []*models.Recipe
+	err := preloadRecipeDetails(r.db).Find(&recipes, recipesId).Error
+	if err != nil {
+		return nil, err
+	}
+	recipesById := make(map[uint]*models.Recipe, len(recipes))
+	for _, recipe := range recipes {
+		recipesById[recipe.ID] = recipe
+	}
+	results := make([]*RecommendedRecipe, 0, len(rows))
+	for _, row := range rows {
+		if recipe, ok := recipesById[row.RecipeID]; ok {
+			results = append(results, &RecommendedRecipe{Recipe: recipe, Score: row.Score, Source: source})
+		}
+	}
+	return results, nil
+}
@@ -12,3 +12,15 @@ type repository struct {
 	logger *zap.Logger
 }
 
+// uniqueValues returns the values without duplicates, keeping the order of first appearance
+func uniqueValues[T comparable](values []T) []T {
+	seen := make(map[T]bool, len(values))
+	res := make([]T, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			res = append(res, v)
+		}
+	}
+	return res
+}
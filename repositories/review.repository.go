@@ -0,0 +1,89 @@
+// package repositories defines interfaces for managing recipe review data in the database
+package repositories
+
+import (
+	"github.com/clementb49/welsh_academy/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReviewRepository is an interface that defines functions for managing the reviews of the recipes in the database.
+// The rating of the recipe is updated in the same transaction as its reviews.
+type ReviewRepository interface {
+	SaveReview(review *models.Review) (*models.Review, error)
+	GetAllReviews(recipeId uint, pageSize, pageNumber int) ([]*models.Review, int64, error)
+	DeleteReview(userId, recipeId uint) error
+}
+
+// Query computing the rating of a recipe from its reviews
+const updateRecipeRatingQuery = "UPDATE wac_recipes SET rating_count = r.count, rating_average = r.average FROM " +
+	"(SELECT COUNT(*) AS count, COALESCE(ROUND(AVG(rating), 2), 0) AS average FROM wac_reviews WHERE recipe_id = ? AND deleted_at IS NULL) r " +
+	"WHERE wac_recipes.id = ?"
+
+// NewReviewRepository returns a new instance of the ReviewRepository interface
+func NewReviewRepository(db *gorm.DB) ReviewRepository {
+	return &repository{
+		db:     db,
+		logger: zap.L(),
+	}
+}
+
+// SaveReview creates the review of the user or replaces the rating and the text of the existing one, the review is returned with its user
+func (r *repository) SaveReview(input *models.Review) (*models.Review, error) {
+	var review *models.Review
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Omit(clause.Associations).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "recipe_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"rating", "text", "updated_at"}),
+		}).Create(input)
+		if err := result.Error; err != nil {
+			return err
+		}
+		err := updateRecipeRating(tx, input.RecipeID)
+		if err != nil {
+			return err
+		}
+		result = tx.Preload("User").Where("user_id = ? AND recipe_id = ?", input.UserID, input.RecipeID).First(&review)
+		return result.Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// updateRecipeRating computes the rating average and the number of reviews of the recipe
+func updateRecipeRating(tx *gorm.DB, recipeId uint) error {
+	return tx.Exec(updateRecipeRatingQuery, recipeId, recipeId).Error
+}
+
+// GetAllReviews returns a page of the reviews of the recipe with their user, the most recent first
+func (r *repository) GetAllReviews(recipeId uint, pageSize, pageNumber int) ([]*models.Review, int64, error) {
+	var reviews []*models.Review
+	var totalReviews int64
+	db := r.db.Model(&models.Review{}).Where("recipe_id = ?", recipeId).Session(&gorm.Session{})
+	err := db.Count(&totalReviews).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	err = db.Preload("User").Order("updated_at DESC").Order("id").Offset(pageNumber * pageSize).Limit(pageSize).Find(&reviews).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return reviews, totalReviews, nil
+}
+
+// DeleteReview deletes permanently the review of the recipe written by the user
+func (r *repository) DeleteReview(userId, recipeId uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Where("user_id = ? AND recipe_id = ?", userId, recipeId).Delete(&models.Review{})
+		if err := result.Error; err != nil {
+			return err
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return updateRecipeRating(tx, recipeId)
+	})
+}
@@ -0,0 +1,110 @@
+// package repositories defines interfaces for managing shopping list data in the database
+package repositories
+
+import (
+	"github.com/clementb49/welsh_academy/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ShoppingListRepository is an interface that defines functions for managing the shopping lists of the users in the database.
+// The lists are always looked up with their owner so a user can't access the lists of another user.
+type ShoppingListRepository interface {
+	CreateShoppingList(list *models.ShoppingList) (*models.ShoppingList, error)
+	GetAllShoppingLists(userId uint, pageSize, pageNumber int) ([]*models.ShoppingList, int64, error)
+	GetShoppingListById(userId, listId uint) (*models.ShoppingList, error)
+	DeleteShoppingListById(userId, listId uint) error
+	CheckShoppingListItem(userId, listId, itemId uint, checked bool) (*models.ShoppingListItem, error)
+}
+
+// NewShoppingListRepository returns a new instance of the ShoppingListRepository interface
+func NewShoppingListRepository(db *gorm.DB) ShoppingListRepository {
+	return &repository{
+		db:     db,
+		logger: zap.L(),
+	}
+}
+
+// preloadShoppingListItems loads the items of the lists ordered by aisle with their ingredient, even when it's deleted
+func preloadShoppingListItems(db *gorm.DB) *gorm.DB {
+	return db.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("aisle").Order("id")
+	}).Preload("Items.Ingredient", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	})
+}
+
+// CreateShoppingList inserts the shopping list with its items, the ingredients aren't modified
+func (r *repository) CreateShoppingList(list *models.ShoppingList) (*models.ShoppingList, error) {
+	result := r.db.Omit("Items.Ingredient").Create(list)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetAllShoppingLists returns a page of the shopping lists of the user, the most recent first
+func (r *repository) GetAllShoppingLists(userId uint, pageSize, pageNumber int) ([]*models.ShoppingList, int64, error) {
+	var lists []*models.ShoppingList
+	var totalLists int64
+	db := r.db.Model(&models.ShoppingList{}).Where("user_id = ?", userId)
+	err := db.Count(&totalLists).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	err = preloadShoppingListItems(db).Order("created_at DESC").Offset(pageNumber * pageSize).Limit(pageSize).Find(&lists).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return lists, totalLists, nil
+}
+
+// GetShoppingListById returns a shopping list of the user by ID with its items
+func (r *repository) GetShoppingListById(userId, listId uint) (*models.ShoppingList, error) {
+	var list *models.ShoppingList
+	result := preloadShoppingListItems(r.db).Where("user_id = ?", userId).First(&list, listId)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// DeleteShoppingListById deletes a shopping list of the user by ID with its items
+func (r *repository) DeleteShoppingListById(userId, listId uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var list *models.ShoppingList
+		result := tx.Where("user_id = ?", userId).First(&list, listId)
+		if err := result.Error; err != nil {
+			return err
+		}
+		result = tx.Where("shopping_list_id = ?", list.ID).Delete(&models.ShoppingListItem{})
+		if err := result.Error; err != nil {
+			return err
+		}
+		return tx.Delete(list).Error
+	})
+}
+
+// CheckShoppingListItem ticks off or unticks an item of a shopping list of the user
+func (r *repository) CheckShoppingListItem(userId, listId, itemId uint, checked bool) (*models.ShoppingListItem, error) {
+	var item *models.ShoppingListItem
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var list *models.ShoppingList
+		result := tx.Where("user_id = ?", userId).First(&list, listId)
+		if err := result.Error; err != nil {
+			return err
+		}
+		result = tx.Preload("Ingredient", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped()
+		}).Where("shopping_list_id = ?", list.ID).First(&item, itemId)
+		if err := result.Error; err != nil {
+			return err
+		}
+		item.Checked = checked
+		return tx.Model(item).Update("checked", checked).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
@@ -0,0 +1,203 @@
+// package repositories defines interfaces for managing recipe step data in the database
+package repositories
+
+import (
+	"fmt"
+
+	"github.com/clementb49/welsh_academy/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StepRepository is an interface that defines functions for managing the preparation steps of the recipes in the database
+type StepRepository interface {
+	GetAllSteps(recipeId uint) ([]*models.RecipeStep, error)
+	CreateStep(step *models.RecipeStep, ingredientsId []uint) (*models.RecipeStep, error)
+	GetStepById(recipeId, stepId uint) (*models.RecipeStep, error)
+	UpdateStep(step *models.RecipeStep, ingredientsId []uint) (*models.RecipeStep, error)
+	DeleteStepById(recipeId, stepId uint) error
+	ReorderSteps(recipeId uint, stepsId []uint) ([]*models.RecipeStep, error)
+}
+
+// Query counting the ingredients used by a recipe among the given ones
+const countRecipeIngredientsQuery = "SELECT COUNT(*) FROM wac_ingredients_recipes WHERE recipe_id = ? AND ingredient_id IN ?"
+
+// Query removing the links of the steps of a recipe to the ingredients which are no longer used by its ingredient lines
+const deleteRemovedStepIngredientsQuery = "DELETE FROM wac_recipe_steps_ingredients WHERE recipe_step_id IN (SELECT id FROM wac_recipe_steps WHERE recipe_id = ?) " +
+	"AND ingredient_id NOT IN (SELECT ingredient_id FROM wac_ingredients_recipes WHERE recipe_id = ?)"
+
+// ErrStepIngredientNotAcceptable is returned when a step references an ingredient which isn't used by the recipe
+var ErrStepIngredientNotAcceptable = fmt.Errorf("the step uses an ingredient which isn't in the recipe, step not acceptable")
+
+// ErrStepsOrderNotAcceptable is returned when the new order doesn't contain exactly all the steps of the recipe
+var ErrStepsOrderNotAcceptable = fmt.Errorf("the new order must contain each step of the recipe once, order not acceptable")
+
+// NewStepRepository returns a new instance of the StepRepository interface
+func NewStepRepository(db *gorm.DB) StepRepository {
+	return &repository{
+		db:     db,
+		logger: zap.L(),
+	}
+}
+
+// preloadOrderedSteps loads the preparation steps of the recipe in their order with their ingredients
+func preloadOrderedSteps(db *gorm.DB) *gorm.DB {
+	return db.Preload("Steps", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position")
+	}).Preload("Steps.Ingredients", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	})
+}
+
+// GetAllSteps returns the steps of the recipe in their order
+func (r *repository) GetAllSteps(recipeId uint) ([]*models.RecipeStep, error) {
+	var steps []*models.RecipeStep
+	result := r.db.Preload("Ingredients").Where("recipe_id = ?", recipeId).Order("position").Find(&steps)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return steps, nil
+}
+
+// CreateStep inserts the step at its position, the following steps are shifted.
+// When the position is 0 or after the last step, the step is appended at the end of the recipe.
+func (r *repository) CreateStep(input *models.RecipeStep, ingredientsId []uint) (*models.RecipeStep, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var nbSteps int64
+		result := tx.Model(&models.RecipeStep{}).Where("recipe_id = ?", input.RecipeID).Count(&nbSteps)
+		if err := result.Error; err != nil {
+			return err
+		}
+		if input.Position == 0 || int64(input.Position) > nbSteps {
+			input.Position = uint(nbSteps) + 1
+		} else {
+			result = tx.Model(&models.RecipeStep{}).
+				Where("recipe_id = ? AND position >= ?", input.RecipeID, input.Position).
+				UpdateColumn("position", gorm.Expr("position + 1"))
+			if err := result.Error; err != nil {
+				return err
+			}
+		}
+		ingredients, err := findStepIngredients(tx, input.RecipeID, ingredientsId)
+		if err != nil {
+			return err
+		}
+		input.Ingredients = ingredients
+		return tx.Omit("Ingredients.*").Create(input).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return input, nil
+}
+
+// findStepIngredients returns the ingredients referenced by a step, they must be used by the recipe
+func findStepIngredients(tx *gorm.DB, recipeId uint, ingredientsId []uint) ([]*models.Ingredient, error) {
+	ingredientsId = uniqueValues(ingredientsId)
+	ingredients := make([]*models.Ingredient, 0, len(ingredientsId))
+	if len(ingredientsId) == 0 {
+		return ingredients, nil
+	}
+	var nbIngredients int64
+	result := tx.Raw(countRecipeIngredientsQuery, recipeId, ingredientsId).Scan(&nbIngredients)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	if nbIngredients != int64(len(ingredientsId)) {
+		return nil, ErrStepIngredientNotAcceptable
+	}
+	result = tx.Unscoped().Find(&ingredients, ingredientsId)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return ingredients, nil
+}
+
+// deleteRemovedStepIngredients removes the links of the steps to the ingredients removed from the recipe lines,
+// it's called in the transaction replacing the ingredient lines
+func deleteRemovedStepIngredients(tx *gorm.DB, recipeId uint) error {
+	return tx.Exec(deleteRemovedStepIngredientsQuery, recipeId, recipeId).Error
+}
+
+// GetStepById returns a step of the recipe by ID with its ingredients
+func (r *repository) GetStepById(recipeId, stepId uint) (*models.RecipeStep, error) {
+	var step *models.RecipeStep
+	result := r.db.Preload("Ingredients").Where("recipe_id = ?", recipeId).First(&step, stepId)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return step, nil
+}
+
+// UpdateStep saves the text and the duration of the step and replaces its ingredients
+func (r *repository) UpdateStep(input *models.RecipeStep, ingredientsId []uint) (*models.RecipeStep, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Omit(clause.Associations).Save(input)
+		if err := result.Error; err != nil {
+			return err
+		}
+		ingredients, err := findStepIngredients(tx, input.RecipeID, ingredientsId)
+		if err != nil {
+			return err
+		}
+		input.Ingredients = ingredients
+		return tx.Model(input).Omit("Ingredients.*").Association("Ingredients").Replace(ingredients)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return input, nil
+}
+
+// DeleteStepById deletes a step of the recipe by ID, the following steps are shifted to keep the numbering continuous
+func (r *repository) DeleteStepById(recipeId, stepId uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var step *models.RecipeStep
+		result := tx.Where("recipe_id = ?", recipeId).First(&step, stepId)
+		if err := result.Error; err != nil {
+			return err
+		}
+		result = tx.Delete(step)
+		if err := result.Error; err != nil {
+			return err
+		}
+		result = tx.Model(&models.RecipeStep{}).
+			Where("recipe_id = ? AND position > ?", recipeId, step.Position).
+			UpdateColumn("position", gorm.Expr("position - 1"))
+		return result.Error
+	})
+}
+
+// ReorderSteps sets the position of each step of the recipe to its index in stepsId starting at 1
+func (r *repository) ReorderSteps(recipeId uint, stepsId []uint) ([]*models.RecipeStep, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var steps []*models.RecipeStep
+		result := tx.Where("recipe_id = ?", recipeId).Find(&steps)
+		if err := result.Error; err != nil {
+			return err
+		}
+		if len(uniqueValues(stepsId)) != len(stepsId) || len(stepsId) != len(steps) {
+			return ErrStepsOrderNotAcceptable
+		}
+		positions := make(map[uint]uint, len(stepsId))
+		for i, id := range stepsId {
+			positions[id] = uint(i + 1)
+		}
+		for _, step := range steps {
+			position, ok := positions[step.ID]
+			if !ok {
+				return ErrStepsOrderNotAcceptable
+			}
+			result = tx.Model(step).UpdateColumn("position", position)
+			if err := result.Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetAllSteps(recipeId)
+}
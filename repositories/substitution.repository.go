@@ -0,0 +1,102 @@
+// package repositories defines interfaces for managing ingredient substitution data in the database
+package repositories
+
+import (
+	"github.com/clementb49/welsh_academy/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// SubstitutionRepository is an interface that defines functions for managing the substitutions between the ingredients in the database
+type SubstitutionRepository interface {
+	CreateSubstitution(substitution *models.IngredientSubstitution) (*models.IngredientSubstitution, error)
+	GetAllSubstitutions(ingredientId uint) ([]*models.IngredientSubstitution, error)
+	GetSubstitutionById(substitutionId uint) (*models.IngredientSubstitution, error)
+	CountSubstitutionsBetween(ingredientId, otherId uint) (int64, error)
+	UpdateSubstitution(substitution *models.IngredientSubstitution) (*models.IngredientSubstitution, error)
+	DeleteSubstitutionById(substitutionId uint) error
+}
+
+// Condition selecting the substitutions which can replace an ingredient, the ? placeholder is the ingredient ID
+const ingredientSubstitutionsCondition = "ingredient_id = ? OR (substitute_id = ? AND bidirectional)"
+
+// Condition selecting the substitutions between two ingredients whatever their direction
+const substitutionPairCondition = "(ingredient_id = ? AND substitute_id = ?) OR (ingredient_id = ? AND substitute_id = ?)"
+
+// Sub query selecting the ingredients which can be replaced by one of the given ingredients, the ? placeholders are the ingredients ID
+const replaceableIngredientsQuery = "SELECT ingredient_id FROM wac_ingredient_substitutions WHERE substitute_id IN ? " +
+	"UNION SELECT substitute_id FROM wac_ingredient_substitutions WHERE ingredient_id IN ? AND bidirectional"
+
+// NewSubstitutionRepository returns a new instance of the SubstitutionRepository interface
+func NewSubstitutionRepository(db *gorm.DB) SubstitutionRepository {
+	return &repository{
+		db:     db,
+		logger: zap.L(),
+	}
+}
+
+// preloadSubstitutionIngredients loads the replaced ingredient and the substitute of the substitutions
+func preloadSubstitutionIngredients(db *gorm.DB) *gorm.DB {
+	return db.Preload("Ingredient").Preload("Substitute")
+}
+
+// CreateSubstitution creates a new substitution, the substitution is returned with its ingredients
+func (r *repository) CreateSubstitution(input *models.IngredientSubstitution) (*models.IngredientSubstitution, error) {
+	result := r.db.Omit("Ingredient", "Substitute").Create(input)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return r.GetSubstitutionById(input.ID)
+}
+
+// GetAllSubstitutions returns the substitutions which can replace the ingredient, the ones where it's the substitute
+// are returned when they are bidirectional
+func (r *repository) GetAllSubstitutions(ingredientId uint) ([]*models.IngredientSubstitution, error) {
+	var substitutions []*models.IngredientSubstitution
+	result := preloadSubstitutionIngredients(r.db).Where(ingredientSubstitutionsCondition, ingredientId, ingredientId).Order("id").Find(&substitutions)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return substitutions, nil
+}
+
+// GetSubstitutionById returns a substitution by ID with its ingredients
+func (r *repository) GetSubstitutionById(substitutionId uint) (*models.IngredientSubstitution, error) {
+	var substitution *models.IngredientSubstitution
+	result := preloadSubstitutionIngredients(r.db).First(&substitution, substitutionId)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return substitution, nil
+}
+
+// CountSubstitutionsBetween counts the substitutions between the two ingredients, in one direction or the other
+func (r *repository) CountSubstitutionsBetween(ingredientId, otherId uint) (int64, error) {
+	var totalSubstitutions int64
+	result := r.db.Model(&models.IngredientSubstitution{}).Where(substitutionPairCondition, ingredientId, otherId, otherId, ingredientId).Count(&totalSubstitutions)
+	if err := result.Error; err != nil {
+		return 0, err
+	}
+	return totalSubstitutions, nil
+}
+
+// UpdateSubstitution saves the ratio, the note and the direction of the substitution
+func (r *repository) UpdateSubstitution(input *models.IngredientSubstitution) (*models.IngredientSubstitution, error) {
+	result := r.db.Model(input).Select("Ratio", "Note", "Bidirectional").Updates(input)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return input, nil
+}
+
+// DeleteSubstitutionById deletes a substitution by ID
+func (r *repository) DeleteSubstitutionById(substitutionId uint) error {
+	result := r.db.Delete(&models.IngredientSubstitution{}, substitutionId)
+	if err := result.Error; err != nil {
+		return err
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+// package repositories defines interfaces for managing tag data in the database
+package repositories
+
+import (
+	"github.com/clementb49/welsh_academy/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// TagRepository is an interface that defines functions for managing the tags and their link with the recipes in the database
+type TagRepository interface {
+	CreateTag(tag *models.Tag) (*models.Tag, error)
+	GetAllTags(category string, pageSize, pageNumber int) ([]*TagUsage, int64, error)
+	GetTagById(tagId uint) (*models.Tag, error)
+	UpdateTag(tag *models.Tag) (*models.Tag, error)
+	DeleteTagById(tagId uint) error
+	AddTagToRecipe(recipeId, tagId uint) error
+	DeleteTagFromRecipe(recipeId, tagId uint) error
+}
+
+// Queries used to count the recipes classified by a tag and to link the tags with the recipes
+const (
+	tagUsageCountSelect      = "(SELECT COUNT(*) FROM wac_tags_recipes tr JOIN wac_recipes r ON r.id = tr.recipe_id AND r.deleted_at IS NULL WHERE tr.tag_id = wac_tags.id) AS usage_count"
+	addTagToRecipeQuery      = "INSERT INTO wac_tags_recipes (recipe_id, tag_id) VALUES (?, ?) ON CONFLICT DO NOTHING"
+	deleteTagFromRecipeQuery = "DELETE FROM wac_tags_recipes WHERE recipe_id = ? AND tag_id = ?"
+	deleteTagRecipesQuery    = "DELETE FROM wac_tags_recipes WHERE tag_id = ?"
+)
+
+// TagUsage is a tag returned with the number of recipes it classifies
+type TagUsage struct {
+	Tag        *models.Tag // the tag
+	UsageCount int64       // the number of recipes which aren't deleted using the tag
+}
+
+// NewTagRepository returns a new instance of the TagRepository interface
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &repository{
+		db:     db,
+		logger: zap.L(),
+	}
+}
+
+// CreateTag inserts a new tag in the database
+func (r *repository) CreateTag(input *models.Tag) (*models.Tag, error) {
+	result := r.db.Create(input)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return input, nil
+}
+
+// GetAllTags returns a page of the tags of the category with the number of recipes using them, the most used first.
+// All the categories are returned when the category is empty.
+func (r *repository) GetAllTags(category string, pageSize, pageNumber int) ([]*TagUsage, int64, error) {
+	var totalTags int64
+	db := r.db.Model(&models.Tag{})
+	if category != "" {
+		db = db.Where("category = ?", category)
+	}
+	db = db.Session(&gorm.Session{})
+	err := db.Count(&totalTags).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	var rows []struct {
+		models.Tag
+		UsageCount int64
+	}
+	err = db.Select("wac_tags.*, " + tagUsageCountSelect).
+		Order("usage_count DESC").Order("category").Order("name").
+		Offset(pageNumber * pageSize).Limit(pageSize).Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	tags := make([]*TagUsage, len(rows))
+	for i := range rows {
+		tags[i] = &TagUsage{Tag: &rows[i].Tag, UsageCount: rows[i].UsageCount}
+	}
+	return tags, totalTags, nil
+}
+
+// GetTagById returns a tag by ID
+func (r *repository) GetTagById(tagId uint) (*models.Tag, error) {
+	var tag *models.Tag
+	result := r.db.First(&tag, tagId)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// UpdateTag saves the name and the category of the tag
+func (r *repository) UpdateTag(input *models.Tag) (*models.Tag, error) {
+	result := r.db.Model(input).Select("Name", "Category").Updates(input)
+	if err := result.Error; err != nil {
+		return nil, err
+	}
+	return input, nil
+}
+
+// DeleteTagById removes the tag from the recipes and deletes it permanently
+func (r *repository) DeleteTagById(tagId uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(deleteTagRecipesQuery, tagId)
+		if err := result.Error; err != nil {
+			return err
+		}
+		result = tx.Unscoped().Delete(&models.Tag{}, tagId)
+		if err := result.Error; err != nil {
+			return err
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// AddTagToRecipe classifies the recipe with the tag, nothing changes when the recipe already has the tag
+func (r *repository) AddTagToRecipe(recipeId, tagId uint) error {
+	return r.db.Exec(addTagToRecipeQuery, recipeId, tagId).Error
+}
+
+// DeleteTagFromRecipe removes the tag from the recipe, it returns gorm.ErrRecordNotFound when the recipe doesn't have the tag
+func (r *repository) DeleteTagFromRecipe(recipeId, tagId uint) error {
+	result := r.db.Exec(deleteTagFromRecipeQuery, recipeId, tagId)
+	if err := result.Error; err != nil {
+		return err
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// package repositories defines interfaces for managing trending recipe data in the database
+package repositories
+
+import (
+	"github.com/clementb49/welsh_academy/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// TrendingRepository is an interface that defines functions for computing and reading the trending recipes
+type TrendingRepository interface {
+	RefreshTrendingRecipes() error
+	GetTrendingRecipes(window string, pageSize, pageNumber int) ([]*TrendingRecipe, int64, error)
+}
+
+// Queries used to compute the trending scores of the recipes over the time windows 24h, 7d and 30d.
+// A favorite weights 5 views and the weight of every favorite or view is halved every quarter of the window,
+// so a recipe liked today ranks above a recipe liked as much at the beginning of the window.
+// The favorites added before their date was recorded are ignored.
+const (
+	deleteTrendingRecipesQuery  = "DELETE FROM wac_recipe_trends"
+	purgeRecipeViewsQuery       = "DELETE FROM wac_recipe_views WHERE created_at < NOW() - INTERVAL '30 days'"
+	computeTrendingRecipesQuery = "INSERT INTO wac_recipe_trends (period, recipe_id, score, favorite_count, view_count, created_at) " +
+		"WITH windows (period, duration) AS (VALUES ('24h', INTERVAL '24 hours'), ('7d', INTERVAL '7 days'), ('30d', INTERVAL '30 days')), " +
+		"events AS (SELECT recipe_id, created_at, 5 AS weight, 1 AS favorites, 0 AS views FROM wac_favorites_recipes WHERE created_at > NOW() - INTERVAL '30 days' " +
+		"UNION ALL SELECT recipe_id, created_at, 1, 0, 1 FROM wac_recipe_views WHERE created_at > NOW() - INTERVAL '30 days') " +
+		"SELECT w.period, e.recipe_id, SUM(e.weight * POWER(0.5, EXTRACT(EPOCH FROM NOW() - e.created_at) / EXTRACT(EPOCH FROM w.duration / 4))), " +
+		"SUM(e.favorites), SUM(e.views), NOW() FROM windows w JOIN events e ON e.created_at > NOW() - w.duration " +
+		"JOIN wac_recipes r ON r.id = e.recipe_id AND r.deleted_at IS NULL GROUP BY w.period, e.recipe_id"
+	trendingRecipesJoinQuery = "JOIN wac_recipes ON wac_recipes.id = wac_recipe_trends.recipe_id AND wac_recipes.deleted_at IS NULL"
+)
+
+// TrendingRecipe is a recipe returned with its trending score and the number of favorites and views during the window
+type TrendingRecipe struct {
+	Recipe        *models.Recipe // the trending recipe
+	Score         float64        // the time-decayed score, the higher the better
+	FavoriteCount uint           // the number of favorites added during the window
+	ViewCount     uint           // the number of views during the window
+}
+
+// NewTrendingRepository returns a new instance of the TrendingRepository interface
+func NewTrendingRepository(db *gorm.DB) TrendingRepository {
+	return &repository{
+		db:     db,
+		logger: zap.L(),
+	}
+}
+
+// RefreshTrendingRecipes replaces the trending scores of all the windows by the ones computed from the current favorites and views,
+// the views older than the longest window are purged. The readers see the former scores until the end of the computation.
+func (r *repository) RefreshTrendingRecipes() error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(purgeRecipeViewsQuery).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Exec(deleteTrendingRecipesQuery).Error
+		if err != nil {
+			return err
+		}
+		return tx.Exec(computeTrendingRecipesQuery).Error
+	})
+}
+
+// GetTrendingRecipes returns a page of the trending recipes over the window computed by the last computation, the hottest first
+func (r *repository) GetTrendingRecipes(window string, pageSize, pageNumber int) ([]*TrendingRecipe, int64, error) {
+	var totalRecipes int64
+	db := r.db.Model(&models.RecipeTrend{}).Joins(trendingRecipesJoinQuery).
+		Where("wac_recipe_trends.period = ?", window).Session(&gorm.Session{})
+	err := db.Count(&totalRecipes).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	var trends []*models.RecipeTrend
+	err = db.Order("wac_recipe_trends.score DESC").Order("wac_recipe_trends.recipe_id").
+		Offset(pageNumber * pageSize).Limit(pageSize).Find(&trends).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(trends) == 0 {
+		return []*TrendingRecipe{}, totalRecipes, nil
+	}
+	recipesId := make([]uint, len(trends))
+	for i, trend := range trends {
+		recipesId[i] = trend.RecipeID
+	}
+	var recipes []*models.Recipe
+	err = preloadRecipeDetails(r.db).Find(&recipes, recipesId).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	recipesById := make(map[uint]*models.Recipe, len(recipes))
+	for _, recipe := range recipes {
+		recipesById[recipe.ID] = recipe
+	}
+	results := make([]*TrendingRecipe, 0, len(trends))
+	for _, trend := range trends {
+		if recipe, ok := recipesById[trend.RecipeID]; ok {
+			results = append(results, &TrendingRecipe{
+				Recipe:        recipe,
+				Score:         trend.Score,
+				FavoriteCount: trend.FavoriteCount,
+				ViewCount:     trend.ViewCount,
+			})
+		}
+	}
+	return results, totalRecipes, nil
+}
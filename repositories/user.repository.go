@@ -7,11 +7,13 @@ import (
 	"gorm.io/gorm"
 )
 
-// Define UserRepository interface with three methods
+// Define UserRepository interface with the methods to manage the users
 type UserRepository interface {
 	CreateUser(input *models.User) (*models.User, error)
 	GetUserById(userId uint) (*models.User, error)
 	GetUserByEmail(email string) (*models.User, error)
+	GetUserByCalendarToken(token string) (*models.User, error)
+	SetCalendarToken(userId uint, token string) error
 }
 
 // Define a function to create a new UserRepository
@@ -54,3 +56,26 @@ func (r *repository) GetUserByEmail(email string) (*models.User, error) {
 	}
 	return &user, nil // Return a pointer to the retrieved user model with no errors
 }
+
+// This function retrieves a user from the database by taking the secret token of their meal plan calendar as input
+// and returns a pointer to the retrieved user model along with any error encountered
+func (r *repository) GetUserByCalendarToken(token string) (*models.User, error) {
+	var user models.User                                     // Create a variable to hold the retrieved user model
+	result := r.db.First(&user, "calendar_token = ?", token) // Retrieve the user owning the calendar token
+	if err := result.Error; err != nil {                     // Check for any errors in the result of the retrieve operation
+		return nil, err
+	}
+	return &user, nil // Return a pointer to the retrieved user model with no errors
+}
+
+// This function replaces the secret token of the meal plan calendar of the user, the previous token stops working
+func (r *repository) SetCalendarToken(userId uint, token string) error {
+	result := r.db.Model(&models.User{}).Where("id = ?", userId).Update("calendar_token", token) // Save the new token of the user
+	if err := result.Error; err != nil {                                                         // Check for any errors in the result of the update operation
+		return err
+	}
+	if result.RowsAffected == 0 { // The user doesn't exist
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
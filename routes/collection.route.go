@@ -0,0 +1,43 @@
+// Package routes provides the routing configuration for the application.
+package routes
+
+import (
+	"github.com/clementb49/welsh_academy/handlers"
+	"github.com/clementb49/welsh_academy/repositories"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// InitCollectionRoute initializes the routes for the recipe collection HTTP requests
+func InitCollectionRoute(db *gorm.DB, unauthRouter, authRouter *gin.RouterGroup) {
+	logger := zap.S()
+	logger.Debug("Initializing collection routes ...")
+
+	// Create the collection and recipe repositories using the provided database instance
+	collectionRepository := repositories.NewCollectionRepository(db)
+	recipeRepository := repositories.NewRecipeRepository(db)
+	// Create a new collection service using the repositories
+	collectionService := services.NewCollectionService(collectionRepository, recipeRepository)
+	// Create a new collection handler using the collection service
+	collectionHandler := handlers.NewCollectionHandler(collectionService)
+
+	// Define the HTTP routes for authenticated users, the collections belong to the current user
+	authRouter.POST("/users/my/collections", collectionHandler.CreateCollectionHandler)
+	authRouter.GET("/users/my/collections", collectionHandler.GetAllCollectionsHandler)
+	authRouter.GET("/users/my/collections/:id", collectionHandler.GetCollectionByIdHandler)
+	authRouter.PUT("/users/my/collections/:id", collectionHandler.UpdateCollectionHandler)
+	authRouter.DELETE("/users/my/collections/:id", collectionHandler.DeleteCollectionByIdHandler)
+	authRouter.PUT("/users/my/collections/:id/recipes/order", collectionHandler.ReorderCollectionRecipesHandler)
+	authRouter.PUT("/users/my/collections/:id/recipes/:recipeId", collectionHandler.AddRecipeToCollectionHandler)
+	authRouter.DELETE("/users/my/collections/:id/recipes/:recipeId", collectionHandler.DeleteRecipeFromCollectionHandler)
+	authRouter.GET("/users/my/collections/:id/share", collectionHandler.GetCollectionShareHandler)
+	authRouter.POST("/users/my/collections/:id/share", collectionHandler.RegenerateCollectionShareHandler)
+	authRouter.DELETE("/users/my/collections/:id/share", collectionHandler.DeleteCollectionShareHandler)
+
+	// Define the HTTP routes for unauthenticated users, the private collections are only readable with their share link
+	unauthRouter.GET("/collections", collectionHandler.GetAllPublicCollectionsHandler)
+	unauthRouter.GET("/collections/:id", collectionHandler.GetPublicCollectionByIdHandler)
+	unauthRouter.GET("/collections/shared/:token", collectionHandler.GetSharedCollectionHandler)
+}
@@ -0,0 +1,36 @@
+// Package routes provides the routing configuration for the application.
+package routes
+
+import (
+	"github.com/clementb49/welsh_academy/handlers"
+	"github.com/clementb49/welsh_academy/repositories"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// InitCommentRoute initializes the routes for recipe comment-related HTTP requests
+func InitCommentRoute(db *gorm.DB, unauthRouter, authRouter *gin.RouterGroup) {
+	logger := zap.S()
+	logger.Debug("Initializing recipe comment routes ...")
+
+	// Create the comment, recipe and user repositories using the provided database instance
+	commentRepository := repositories.NewCommentRepository(db)
+	recipeRepository := repositories.NewRecipeRepository(db)
+	userRepository := repositories.NewUserRepository(db)
+	// Create a new comment service using the repositories
+	commentService := services.NewCommentService(commentRepository, recipeRepository, userRepository)
+	// Create a new comment handler using the comment service
+	commentHandler := handlers.NewCommentHandler(commentService)
+
+	// Define the HTTP routes for authenticated users
+	authRouter.POST("/recipes/:id/comments", commentHandler.CreateCommentHandler)
+	authRouter.PUT("/recipes/:id/comments/:commentId", commentHandler.UpdateCommentHandler)
+	authRouter.DELETE("/recipes/:id/comments/:commentId", commentHandler.DeleteCommentHandler)
+	authRouter.PUT("/recipes/:id/comments/:commentId/pin", commentHandler.PinCommentHandler)
+	authRouter.DELETE("/recipes/:id/comments/:commentId/pin", commentHandler.UnpinCommentHandler)
+
+	// Define the HTTP routes for unauthenticated users
+	unauthRouter.GET("/recipes/:id/comments", commentHandler.GetAllCommentsHandler)
+}
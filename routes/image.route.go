@@ -0,0 +1,47 @@
+// Package routes provides the routing configuration for the application.
+package routes
+
+import (
+	"github.com/clementb49/welsh_academy/config"
+	"github.com/clementb49/welsh_academy/handlers"
+	"github.com/clementb49/welsh_academy/media"
+	"github.com/clementb49/welsh_academy/repositories"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// InitImageRoute initializes the routes for the recipe photo and ingredient image HTTP requests
+func InitImageRoute(db *gorm.DB, unauthRouter, authRouter *gin.RouterGroup) {
+	logger := zap.S()
+	logger.Debug("Initializing image routes ...")
+
+	// Create the image, recipe, ingredient and user repositories using the provided database instance
+	imageRepository := repositories.NewImageRepository(db)
+	recipeRepository := repositories.NewRecipeRepository(db)
+	ingredientRepository := repositories.NewIngredientRepository(db)
+	userRepository := repositories.NewUserRepository(db)
+	// Create a new image service using the repositories, the files are written in the media storage
+	imageService := services.NewImageService(imageRepository, recipeRepository, ingredientRepository, userRepository,
+		newMediaStorage(), config.GetWaConfig().MaxImageSize)
+	// Create a new image handler using the image service
+	imageHandler := handlers.NewImageHandler(imageService)
+
+	// Define the HTTP routes for authenticated users
+	authRouter.POST("/recipes/:id/images", imageHandler.UploadRecipeImageHandler)
+	authRouter.PUT("/recipes/:id/images/:imageId/cover", imageHandler.SetRecipeCoverImageHandler)
+	authRouter.DELETE("/recipes/:id/images/:imageId", imageHandler.DeleteRecipeImageHandler)
+	authRouter.PUT("/ingredients/:id/image", imageHandler.UploadIngredientImageHandler)
+	authRouter.DELETE("/ingredients/:id/image", imageHandler.DeleteIngredientImageHandler)
+
+	// Define the HTTP routes for unauthenticated users
+	unauthRouter.GET("/recipes/:id/images", imageHandler.GetAllRecipeImagesHandler)
+}
+
+// newMediaStorage returns the storage of the uploaded images configured for the application,
+// the services use it to build the URLs of the images
+func newMediaStorage() media.Storage {
+	waCfg := config.GetWaConfig()
+	return media.NewLocalStorage(waCfg.MediaDir, waCfg.MediaUrl)
+}
@@ -0,0 +1,29 @@
+// Package routes provides the routing configuration for the application.
+package routes
+
+import (
+	"github.com/clementb49/welsh_academy/handlers"
+	"github.com/clementb49/welsh_academy/repositories"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// InitImportRoute initializes the routes for the recipe import HTTP requests
+func InitImportRoute(db *gorm.DB, unauthRouter, authRouter *gin.RouterGroup) {
+	logger := zap.S()
+	logger.Debug("Initializing import routes ...")
+
+	// Create the import and recipe repositories using the provided database instance
+	importRepository := repositories.NewImportRepository(db)
+	recipeRepository := repositories.NewRecipeRepository(db)
+	// Create a new import service using the repositories
+	importService := services.NewImportService(importRepository, recipeRepository, newMediaStorage())
+	// Create a new import handler using the import service
+	importHandler := handlers.NewImportHandler(importService)
+
+	// Define the HTTP routes for authenticated users
+	authRouter.POST("/recipes/import", importHandler.ImportRecipeHandler)
+	authRouter.POST("/recipes/import/commit", importHandler.CommitRecipeImportHandler)
+}
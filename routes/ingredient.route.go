@@ -14,15 +14,17 @@ import (
 func InitIngredientRoute(db *gorm.DB, unAuthRouter, authRouter *gin.RouterGroup) {
 	logger := zap.S()
 	logger.Debug("Initializing ingredient routes ...")
-	// Create a new ingredient repository using the provided database instance
+	// Create the ingredient and user repositories using the provided database instance
 	ingredientRepository := repositories.NewIngredientRepository(db)
-	// Create a new ingredient service using the ingredient repository
-	ingredientService := services.NewIngredientService(ingredientRepository)
+	userRepository := repositories.NewUserRepository(db)
+	// Create a new ingredient service using the repositories
+	ingredientService := services.NewIngredientService(ingredientRepository, userRepository, newMediaStorage())
 	// Create a new ingredient handler using the ingredient service
 	ingredientHandler := handlers.NewIngredientHandlers(ingredientService)
 
 	// Define the HTTP routes for authenticated users
 	authRouter.POST("/ingredients", ingredientHandler.CreateIngredientHandler)
+	authRouter.PUT("/ingredients/:id", ingredientHandler.UpdateIngredientHandler)
 	authRouter.DELETE("ingredients/:id", ingredientHandler.DeleteIngredientByIdHandler)
 
 	// Define the HTTP routes for unauthenticated users
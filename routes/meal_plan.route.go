@@ -0,0 +1,39 @@
+// Package routes provides the routing configuration for the application.
+package routes
+
+import (
+	"github.com/clementb49/welsh_academy/config"
+	"github.com/clementb49/welsh_academy/handlers"
+	"github.com/clementb49/welsh_academy/repositories"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// InitMealPlanRoute initializes the routes for meal plan-related HTTP requests
+func InitMealPlanRoute(db *gorm.DB, unauthRouter, authRouter *gin.RouterGroup) {
+	logger := zap.S()
+	logger.Debug("Initializing meal plan routes ...")
+
+	// Create the meal plan, recipe and user repositories using the provided database instance
+	mealPlanRepository := repositories.NewMealPlanRepository(db)
+	recipeRepository := repositories.NewRecipeRepository(db)
+	userRepository := repositories.NewUserRepository(db)
+	// Create a new meal plan service using the repositories, the dates are days of the database time zone
+	mealPlanService := services.NewMealPlanService(mealPlanRepository, recipeRepository, userRepository, config.GetWaConfig().DbCfg.Location())
+	// Create a new meal plan handler using the meal plan service
+	mealPlanHandler := handlers.NewMealPlanHandler(mealPlanService)
+
+	// Define the HTTP routes for authenticated users
+	authRouter.POST("/users/my/mealplans", mealPlanHandler.CreateMealPlanHandler)
+	authRouter.GET("/users/my/mealplans", mealPlanHandler.GetAllMealPlansHandler)
+	authRouter.GET("/users/my/mealplans/calendar", mealPlanHandler.GetCalendarHandler)
+	authRouter.POST("/users/my/mealplans/calendar", mealPlanHandler.RegenerateCalendarHandler)
+	authRouter.GET("/users/my/mealplans/:id", mealPlanHandler.GetMealPlanByIdHandler)
+	authRouter.PUT("/users/my/mealplans/:id", mealPlanHandler.UpdateMealPlanHandler)
+	authRouter.DELETE("/users/my/mealplans/:id", mealPlanHandler.DeleteMealPlanByIdHandler)
+
+	// Define the HTTP routes for unauthenticated users, the calendar applications can't log in
+	unauthRouter.GET("/mealplans/:token/calendar.ics", mealPlanHandler.ExportCalendarHandler)
+}
@@ -0,0 +1,30 @@
+// Package routes provides the routing configuration for the application.
+package routes
+
+import (
+	"github.com/clementb49/welsh_academy/handlers"
+	"github.com/clementb49/welsh_academy/repositories"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// InitNutritionRoute initializes the routes for the ingredient nutrition HTTP requests
+func InitNutritionRoute(db *gorm.DB, unauthRouter, authRouter *gin.RouterGroup) {
+	logger := zap.S()
+	logger.Debug("Initializing nutrition routes ...")
+
+	// Create the nutrition, ingredient and user repositories using the provided database instance
+	nutritionRepository := repositories.NewNutritionRepository(db)
+	ingredientRepository := repositories.NewIngredientRepository(db)
+	userRepository := repositories.NewUserRepository(db)
+	// Create a new nutrition service using the repositories
+	nutritionService := services.NewNutritionService(nutritionRepository, ingredientRepository, userRepository, newMediaStorage())
+	// Create a new nutrition handler using the nutrition service
+	nutritionHandler := handlers.NewNutritionHandler(nutritionService)
+
+	// Define the HTTP routes for authenticated users, the nutrition facts are read with the ingredient
+	authRouter.PUT("/ingredients/:id/nutrition", nutritionHandler.SaveIngredientNutritionHandler)
+	authRouter.DELETE("/ingredients/:id/nutrition", nutritionHandler.DeleteIngredientNutritionHandler)
+}
@@ -0,0 +1,32 @@
+// Package routes provides the routing configuration for the application.
+package routes
+
+import (
+	"github.com/clementb49/welsh_academy/handlers"
+	"github.com/clementb49/welsh_academy/repositories"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// InitPantryRoute initializes the routes for the pantry HTTP requests
+func InitPantryRoute(db *gorm.DB, unauthRouter, authRouter *gin.RouterGroup) {
+	logger := zap.S()
+	logger.Debug("Initializing pantry routes ...")
+
+	// Create the pantry and ingredient repositories using the provided database instance
+	pantryRepository := repositories.NewPantryRepository(db)
+	ingredientRepository := repositories.NewIngredientRepository(db)
+	// Create a new pantry service using the repositories
+	pantryService := services.NewPantryService(pantryRepository, ingredientRepository, newMediaStorage())
+	// Create a new pantry handler using the pantry service
+	pantryHandler := handlers.NewPantryHandler(pantryService)
+
+	// Define the HTTP routes for authenticated users, the pantry belongs to the current user
+	authRouter.GET("/users/my/pantry", pantryHandler.GetAllPantryIngredientsHandler)
+	authRouter.PUT("/users/my/pantry", pantryHandler.ReplacePantryHandler)
+	authRouter.PUT("/users/my/pantry/:id", pantryHandler.AddIngredientToPantryHandler)
+	authRouter.DELETE("/users/my/pantry/:id", pantryHandler.DeleteIngredientFromPantryHandler)
+	authRouter.GET("/recipes/cookable", pantryHandler.GetCookableRecipesHandler)
+}
@@ -0,0 +1,36 @@
+// Package routes provides the routing configuration for the application.
+package routes
+
+import (
+	"github.com/clementb49/welsh_academy/config"
+	"github.com/clementb49/welsh_academy/handlers"
+	"github.com/clementb49/welsh_academy/repositories"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// InitPriceRoute initializes the routes for the ingredient price and recipe cost HTTP requests
+func InitPriceRoute(db *gorm.DB, unauthRouter, authRouter *gin.RouterGroup) {
+	logger := zap.S()
+	logger.Debug("Initializing price routes ...")
+
+	// Create the price, ingredient, recipe and user repositories using the provided database instance
+	priceRepository := repositories.NewPriceRepository(db)
+	ingredientRepository := repositories.NewIngredientRepository(db)
+	recipeRepository := repositories.NewRecipeRepository(db)
+	userRepository := repositories.NewUserRepository(db)
+	// Create a new price service using the repositories, the prices are recorded on the days of the database time zone
+	priceService := services.NewPriceService(priceRepository, ingredientRepository, recipeRepository, userRepository, config.GetWaConfig().DbCfg.Location())
+	// Create a new price handler using the price service
+	priceHandler := handlers.NewPriceHandler(priceService)
+
+	// Define the HTTP routes for authenticated users
+	authRouter.POST("/ingredients/:id/prices", priceHandler.CreateIngredientPriceHandler)
+	authRouter.DELETE("/ingredients/:id/prices/:priceId", priceHandler.DeleteIngredientPriceHandler)
+
+	// Define the HTTP routes for unauthenticated users
+	unauthRouter.GET("/ingredients/:id/prices", priceHandler.GetAllIngredientPricesHandler)
+	unauthRouter.GET("/recipes/:id/cost-history", priceHandler.GetRecipeCostHistoryHandler)
+}
@@ -17,19 +17,26 @@ func InitRecipeRoute(db *gorm.DB, unauthRouter, authRouter *gin.RouterGroup) {
 
 	// Create a new recipe repository using the provided database instance
 	recipeRepository := repositories.NewRecipeRepository(db)
-	// Create a new recipe service using the recipe repository
-	recipeService := services.NewRecipeService(recipeRepository)
+	// Create a new user repository used to check the user permissions
+	userRepository := repositories.NewUserRepository(db)
+	// Create a new recipe service using the recipe and the user repositories
+	recipeService := services.NewRecipeService(recipeRepository, userRepository, newMediaStorage())
 	// Create a new recipe handler using the recipe service
 	recipeHandler := handlers.NewRecipeHandler(recipeService)
 
 	// Define the HTTP routes for authenticated users
 	authRouter.POST("/recipes", recipeHandler.CreateRecipeHandler)
+	authRouter.PUT("/recipes/:id", recipeHandler.UpdateRecipeHandler)
+	authRouter.PATCH("/recipes/:id", recipeHandler.PatchRecipeHandler)
 	authRouter.DELETE("/recipes/:id", recipeHandler.DeleteRecipeById)
+	authRouter.POST("/recipes/:id/fork", recipeHandler.ForkRecipeHandler)
 	authRouter.PATCH("/recipes/:id/favorite", recipeHandler.AddToFavRecipeHandler)
 	authRouter.DELETE("/recipes/:id/favorite", recipeHandler.DeleteFavRecipeHandler)
 	authRouter.GET("recipes/favorites", recipeHandler.GetAllFavRecipeHandler)
 
 	// Define the HTTP routes for unauthenticated users
 	unauthRouter.GET("/recipes", recipeHandler.GetAllRecipeHandler)
+	unauthRouter.GET("/recipes/search", recipeHandler.SearchRecipeHandler)
 	unauthRouter.GET("/recipes/:id", recipeHandler.GetRecipeByIdHandler)
+	unauthRouter.GET("/recipes/:id/similar", recipeHandler.GetSimilarRecipesHandler)
 }
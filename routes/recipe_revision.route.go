@@ -0,0 +1,34 @@
+// Package routes provides the routing configuration for the application.
+package routes
+
+import (
+	"github.com/clementb49/welsh_academy/handlers"
+	"github.com/clementb49/welsh_academy/repositories"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// InitRecipeRevisionRoute initializes the routes for recipe revision-related HTTP requests
+func InitRecipeRevisionRoute(db *gorm.DB, unauthRouter, authRouter *gin.RouterGroup) {
+	logger := zap.S()
+	logger.Debug("Initializing recipe revision routes ...")
+
+	// Create the revision, recipe and user repositories using the provided database instance
+	revisionRepository := repositories.NewRecipeRevisionRepository(db)
+	recipeRepository := repositories.NewRecipeRepository(db)
+	userRepository := repositories.NewUserRepository(db)
+	// Create a new revision service using the repositories
+	revisionService := services.NewRecipeRevisionService(revisionRepository, recipeRepository, userRepository, newMediaStorage())
+	// Create a new revision handler using the revision service
+	revisionHandler := handlers.NewRecipeRevisionHandler(revisionService)
+
+	// Define the HTTP routes for authenticated users
+	authRouter.POST("/recipes/:id/revisions/:number/rollback", revisionHandler.RollbackRecipeHandler)
+
+	// Define the HTTP routes for unauthenticated users
+	unauthRouter.GET("/recipes/:id/revisions", revisionHandler.GetAllRecipeRevisionsHandler)
+	unauthRouter.GET("/recipes/:id/revisions/diff", revisionHandler.DiffRecipeRevisionsHandler)
+	unauthRouter.GET("/recipes/:id/revisions/:number", revisionHandler.GetRecipeRevisionHandler)
+}
@@ -0,0 +1,27 @@
+// Package routes provides the routing configuration for the application.
+package routes
+
+import (
+	"github.com/clementb49/welsh_academy/handlers"
+	"github.com/clementb49/welsh_academy/repositories"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// InitRecommendationRoute initializes the routes for the recommendation HTTP requests
+func InitRecommendationRoute(db *gorm.DB, unauthRouter, authRouter *gin.RouterGroup) {
+	logger := zap.S()
+	logger.Debug("Initializing recommendation routes ...")
+
+	// Create a new recommendation repository using the provided database instance
+	recommendationRepository := repositories.NewRecommendationRepository(db)
+	// Create a new recommendation service using the repository
+	recommendationService := services.NewRecommendationService(recommendationRepository, newMediaStorage())
+	// Create a new recommendation handler using the recommendation service
+	recommendationHandler := handlers.NewRecommendationHandler(recommendationService)
+
+	// Define the HTTP routes for authenticated users, the recommendations are made for the current user
+	authRouter.GET("/users/my/recommendations", recommendationHandler.GetUserRecommendationsHandler)
+}
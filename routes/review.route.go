@@ -0,0 +1,32 @@
+// Package routes provides the routing configuration for the application.
+package routes
+
+import (
+	"github.com/clementb49/welsh_academy/handlers"
+	"github.com/clementb49/welsh_academy/repositories"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// InitReviewRoute initializes the routes for recipe review-related HTTP requests
+func InitReviewRoute(db *gorm.DB, unauthRouter, authRouter *gin.RouterGroup) {
+	logger := zap.S()
+	logger.Debug("Initializing recipe review routes ...")
+
+	// Create the review and recipe repositories using the provided database instance
+	reviewRepository := repositories.NewReviewRepository(db)
+	recipeRepository := repositories.NewRecipeRepository(db)
+	// Create a new review service using the repositories
+	reviewService := services.NewReviewService(reviewRepository, recipeRepository)
+	// Create a new review handler using the review service
+	reviewHandler := handlers.NewReviewHandler(reviewService)
+
+	// Define the HTTP routes for authenticated users, a user has a single review by recipe
+	authRouter.PUT("/recipes/:id/reviews/my", reviewHandler.SaveReviewHandler)
+	authRouter.DELETE("/recipes/:id/reviews/my", reviewHandler.DeleteReviewHandler)
+
+	// Define the HTTP routes for unauthenticated users
+	unauthRouter.GET("/recipes/:id/reviews", reviewHandler.GetAllReviewsHandler)
+}
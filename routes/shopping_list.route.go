@@ -0,0 +1,35 @@
+// Package routes provides the routing configuration for the application.
+package routes
+
+import (
+	"github.com/clementb49/welsh_academy/handlers"
+	"github.com/clementb49/welsh_academy/repositories"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// InitShoppingListRoute initializes the routes for shopping list-related HTTP requests
+func InitShoppingListRoute(db *gorm.DB, unauthRouter, authRouter *gin.RouterGroup) {
+	logger := zap.S()
+	logger.Debug("Initializing shopping list routes ...")
+
+	// Create the shopping list and recipe repositories using the provided database instance
+	shoppingListRepository := repositories.NewShoppingListRepository(db)
+	recipeRepository := repositories.NewRecipeRepository(db)
+	// Create a new shopping list service using the repositories
+	shoppingListService := services.NewShoppingListService(shoppingListRepository, recipeRepository)
+	// Create a new shopping list handler using the shopping list service
+	shoppingListHandler := handlers.NewShoppingListHandler(shoppingListService)
+
+	// Define the HTTP routes for authenticated users
+	authRouter.POST("/users/my/shopping-lists", shoppingListHandler.CreateShoppingListHandler)
+	authRouter.GET("/users/my/shopping-lists", shoppingListHandler.GetAllShoppingListsHandler)
+	authRouter.GET("/users/my/shopping-lists/:id", shoppingListHandler.GetShoppingListByIdHandler)
+	authRouter.DELETE("/users/my/shopping-lists/:id", shoppingListHandler.DeleteShoppingListByIdHandler)
+	authRouter.PATCH("/users/my/shopping-lists/:id/items/:itemId", shoppingListHandler.CheckShoppingListItemHandler)
+
+	// Define the HTTP routes for unauthenticated users
+	unauthRouter.POST("/shopping-lists/generate", shoppingListHandler.GenerateShoppingListHandler)
+}
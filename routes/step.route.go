@@ -0,0 +1,35 @@
+// Package routes provides the routing configuration for the application.
+package routes
+
+import (
+	"github.com/clementb49/welsh_academy/handlers"
+	"github.com/clementb49/welsh_academy/repositories"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// InitStepRoute initializes the routes for recipe step-related HTTP requests
+func InitStepRoute(db *gorm.DB, unauthRouter, authRouter *gin.RouterGroup) {
+	logger := zap.S()
+	logger.Debug("Initializing recipe step routes ...")
+
+	// Create the step, recipe and user repositories using the provided database instance
+	stepRepository := repositories.NewStepRepository(db)
+	recipeRepository := repositories.NewRecipeRepository(db)
+	userRepository := repositories.NewUserRepository(db)
+	// Create a new step service using the repositories
+	stepService := services.NewStepService(stepRepository, recipeRepository, userRepository)
+	// Create a new step handler using the step service
+	stepHandler := handlers.NewStepHandler(stepService)
+
+	// Define the HTTP routes for authenticated users
+	authRouter.POST("/recipes/:id/steps", stepHandler.CreateStepHandler)
+	authRouter.PUT("/recipes/:id/steps/order", stepHandler.ReorderStepsHandler)
+	authRouter.PUT("/recipes/:id/steps/:stepId", stepHandler.UpdateStepHandler)
+	authRouter.DELETE("/recipes/:id/steps/:stepId", stepHandler.DeleteStepByIdHandler)
+
+	// Define the HTTP routes for unauthenticated users
+	unauthRouter.GET("/recipes/:id/steps", stepHandler.GetAllStepsHandler)
+}
@@ -0,0 +1,34 @@
+// Package routes provides the routing configuration for the application.
+package routes
+
+import (
+	"github.com/clementb49/welsh_academy/handlers"
+	"github.com/clementb49/welsh_academy/repositories"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// InitSubstitutionRoute initializes the routes for the ingredient substitution HTTP requests
+func InitSubstitutionRoute(db *gorm.DB, unauthRouter, authRouter *gin.RouterGroup) {
+	logger := zap.S()
+	logger.Debug("Initializing substitution routes ...")
+
+	// Create the substitution, ingredient and user repositories using the provided database instance
+	substitutionRepository := repositories.NewSubstitutionRepository(db)
+	ingredientRepository := repositories.NewIngredientRepository(db)
+	userRepository := repositories.NewUserRepository(db)
+	// Create a new substitution service using the repositories
+	substitutionService := services.NewSubstitutionService(substitutionRepository, ingredientRepository, userRepository)
+	// Create a new substitution handler using the substitution service
+	substitutionHandler := handlers.NewSubstitutionHandler(substitutionService)
+
+	// Define the HTTP routes for authenticated users
+	authRouter.POST("/ingredients/:id/substitutions", substitutionHandler.CreateSubstitutionHandler)
+	authRouter.PUT("/ingredients/:id/substitutions/:substitutionId", substitutionHandler.UpdateSubstitutionHandler)
+	authRouter.DELETE("/ingredients/:id/substitutions/:substitutionId", substitutionHandler.DeleteSubstitutionHandler)
+
+	// Define the HTTP routes for unauthenticated users
+	unauthRouter.GET("/ingredients/:id/substitutions", substitutionHandler.GetAllSubstitutionsHandler)
+}
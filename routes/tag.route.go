@@ -0,0 +1,37 @@
+// Package routes provides the routing configuration for the application.
+package routes
+
+import (
+	"github.com/clementb49/welsh_academy/handlers"
+	"github.com/clementb49/welsh_academy/repositories"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// InitTagRoute initializes the routes for tag-related HTTP requests
+func InitTagRoute(db *gorm.DB, unauthRouter, authRouter *gin.RouterGroup) {
+	logger := zap.S()
+	logger.Debug("Initializing tag routes ...")
+
+	// Create the tag, recipe and user repositories using the provided database instance
+	tagRepository := repositories.NewTagRepository(db)
+	recipeRepository := repositories.NewRecipeRepository(db)
+	userRepository := repositories.NewUserRepository(db)
+	// Create a new tag service using the repositories
+	tagService := services.NewTagService(tagRepository, recipeRepository, userRepository, newMediaStorage())
+	// Create a new tag handler using the tag service
+	tagHandler := handlers.NewTagHandler(tagService)
+
+	// Define the HTTP routes for authenticated users
+	authRouter.POST("/tags", tagHandler.CreateTagHandler)
+	authRouter.PUT("/tags/:id", tagHandler.UpdateTagHandler)
+	authRouter.DELETE("/tags/:id", tagHandler.DeleteTagByIdHandler)
+	authRouter.PUT("/recipes/:id/tags/:tagId", tagHandler.AddTagToRecipeHandler)
+	authRouter.DELETE("/recipes/:id/tags/:tagId", tagHandler.DeleteTagFromRecipeHandler)
+
+	// Define the HTTP routes for unauthenticated users
+	unauthRouter.GET("/tags", tagHandler.GetAllTagsHandler)
+	unauthRouter.GET("/tags/:id", tagHandler.GetTagByIdHandler)
+}
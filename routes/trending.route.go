@@ -0,0 +1,27 @@
+// Package routes provides the routing configuration for the application.
+package routes
+
+import (
+	"github.com/clementb49/welsh_academy/handlers"
+	"github.com/clementb49/welsh_academy/repositories"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// InitTrendingRoute initializes the routes for the trending recipe HTTP requests
+func InitTrendingRoute(db *gorm.DB, unauthRouter, authRouter *gin.RouterGroup) {
+	logger := zap.S()
+	logger.Debug("Initializing trending routes ...")
+
+	// Create a new trending repository using the provided database instance
+	trendingRepository := repositories.NewTrendingRepository(db)
+	// Create a new trending service using the repository
+	trendingService := services.NewTrendingService(trendingRepository, newMediaStorage())
+	// Create a new trending handler using the trending service
+	trendingHandler := handlers.NewTrendingHandler(trendingService)
+
+	// Define the HTTP routes for unauthenticated users
+	unauthRouter.GET("/recipes/trending", trendingHandler.GetTrendingRecipesHandler)
+}
@@ -0,0 +1,93 @@
+// The package 'services' contains the business logic for handling route
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/clementb49/welsh_academy/models"
+)
+
+// mealTime is the time of the day when a meal starts
+type mealTime struct {
+	hour   int
+	minute int
+}
+
+// The start time of the meal slots in the time zone of the application
+var mealSlotTimes = map[string]mealTime{
+	models.MealSlotBreakfast: {hour: 8},
+	models.MealSlotLunch:     {hour: 12, minute: 30},
+	models.MealSlotDinner:    {hour: 19},
+}
+
+// The duration of the meal events
+const mealDuration = time.Hour
+
+// The format of the UTC date-times in the iCalendar files
+const icsTimeFormat = "20060102T150405Z"
+
+// The maximal length in octets of a line of an iCalendar file, the longer lines are folded
+const icsLineLength = 75
+
+// icsTextEscaper escapes the special characters of the iCalendar texts
+var icsTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// exportCalendar formats the meal plans as an iCalendar file (RFC 5545). Each plan is an event starting at the time of its slot
+// in the location, the times are written in UTC so the calendar applications don't need the time zone definition.
+func exportCalendar(plans []*models.MealPlan, location *time.Location) string {
+	var builder strings.Builder
+	writeIcsLine(&builder, "BEGIN:VCALENDAR")
+	writeIcsLine(&builder, "VERSION:2.0")
+	writeIcsLine(&builder, "PRODID:-//Welsh Academy//Meal planner//EN")
+	writeIcsLine(&builder, "CALSCALE:GREGORIAN")
+	writeIcsLine(&builder, "METHOD:PUBLISH")
+	writeIcsLine(&builder, "X-WR-CALNAME:Welsh Academy meal plan")
+	for _, plan := range plans {
+		slotTime := mealSlotTimes[plan.Slot]
+		start := time.Date(plan.Date.Year(), plan.Date.Month(), plan.Date.Day(), slotTime.hour, slotTime.minute, 0, 0, location)
+		title := fmt.Sprintf("recipe %d", plan.RecipeID)
+		servings := plan.Servings
+		if plan.Recipe != nil {
+			title = plan.Recipe.Title
+			if servings == 0 {
+				servings = plan.Recipe.Servings
+			}
+		}
+		description := fmt.Sprintf("%d servings", servings)
+		if plan.Note != "" {
+			description = description + "\n" + plan.Note
+		}
+		writeIcsLine(&builder, "BEGIN:VEVENT")
+		writeIcsLine(&builder, fmt.Sprintf("UID:mealplan-%d@welsh-academy", plan.ID))
+		writeIcsLine(&builder, "DTSTAMP:"+plan.UpdatedAt.UTC().Format(icsTimeFormat))
+		writeIcsLine(&builder, "DTSTART:"+start.UTC().Format(icsTimeFormat))
+		writeIcsLine(&builder, "DTEND:"+start.Add(mealDuration).UTC().Format(icsTimeFormat))
+		writeIcsLine(&builder, "SUMMARY:"+icsTextEscaper.Replace(strings.ToUpper(plan.Slot[:1])+plan.Slot[1:]+": "+title))
+		writeIcsLine(&builder, "DESCRIPTION:"+icsTextEscaper.Replace(description))
+		writeIcsLine(&builder, "END:VEVENT")
+	}
+	writeIcsLine(&builder, "END:VCALENDAR")
+	return builder.String()
+}
+
+// writeIcsLine writes a content line ended by CRLF, the lines longer than 75 octets are folded
+// without cutting a UTF-8 character and the continuation lines start with a space
+func writeIcsLine(builder *strings.Builder, line string) {
+	limit := icsLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		builder.WriteString(line[:cut])
+		builder.WriteString("\r\n ")
+		line = line[cut:]
+		// the leading space of the continuation line counts in its length
+		limit = icsLineLength - 1
+	}
+	builder.WriteString(line)
+	builder.WriteString("\r\n")
+}
@@ -0,0 +1,193 @@
+// The package 'services' contains the business logic for handling route
+package services
+
+import (
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/repositories"
+	"go.uber.org/zap"
+)
+
+// CollectionService is an interface for defining the methods to manage the recipe collections of the users and to browse the public ones
+type CollectionService interface {
+	CreateCollection(body *dto.CollectionReqBody, userId uint) (*dto.CollectionResBody, error)
+	GetAllCollections(query *dto.CommonQueryPage, userId uint) (*dto.CommonPageRespBody, error)
+	GetCollectionById(input *dto.CommonIdPathUri, userId uint) (*dto.CollectionResBody, error)
+	UpdateCollection(input *dto.CommonIdPathUri, body *dto.CollectionReqBody, userId uint) (*dto.CollectionResBody, error)
+	DeleteCollectionById(input *dto.CommonIdPathUri, userId uint) error
+	AddRecipeToCollection(input *dto.CollectionRecipePathUri, userId uint) (*dto.CollectionResBody, error)
+	DeleteRecipeFromCollection(input *dto.CollectionRecipePathUri, userId uint) (*dto.CollectionResBody, error)
+	ReorderCollectionRecipes(input *dto.CommonIdPathUri, body *dto.CollectionRecipesOrderReqBody, userId uint) (*dto.CollectionResBody, error)
+	GetCollectionShareToken(input *dto.CommonIdPathUri, userId uint) (*dto.CollectionShareResBody, error)
+	RegenerateCollectionShareToken(input *dto.CommonIdPathUri, userId uint) (*dto.CollectionShareResBody, error)
+	DeleteCollectionShareToken(input *dto.CommonIdPathUri, userId uint) error
+	GetAllPublicCollections(query *dto.PublicCollectionQuery) (*dto.CommonPageRespBody, error)
+	GetPublicCollectionById(input *dto.CommonIdPathUri) (*dto.CollectionResBody, error)
+	GetSharedCollection(input *dto.CollectionTokenPathUri) (*dto.CollectionResBody, error)
+}
+
+// collectionService is an implementation of the CollectionService interface
+type collectionService struct {
+	repo       repositories.CollectionRepository
+	recipeRepo repositories.RecipeRepository
+	logger     *zap.Logger
+}
+
+// NewCollectionService creates a new CollectionService instance, the recipe repository is used to check the added recipes exist
+func NewCollectionService(repo repositories.CollectionRepository, recipeRepo repositories.RecipeRepository) CollectionService {
+	return &collectionService{
+		repo:       repo,
+		recipeRepo: recipeRepo,
+		logger:     zap.L(),
+	}
+}
+
+// CreateCollection creates an empty collection for the user
+func (s *collectionService) CreateCollection(body *dto.CollectionReqBody, userId uint) (*dto.CollectionResBody, error) {
+	collection, err := s.repo.CreateCollection(body.ConvertToModel(userId))
+	if err != nil {
+		return nil, err
+	}
+	return convertCollection(collection), nil
+}
+
+// GetAllCollections returns a page of the collections of the user, the private ones included
+func (s *collectionService) GetAllCollections(query *dto.CommonQueryPage, userId uint) (*dto.CommonPageRespBody, error) {
+	collections, totalCollections, err := s.repo.GetAllCollections(userId, query.PageSize, query.PageNumber)
+	if err != nil {
+		return nil, err
+	}
+	return newCollectionsPage(query, totalCollections, collections), nil
+}
+
+// GetCollectionById returns a collection of the user by ID
+func (s *collectionService) GetCollectionById(input *dto.CommonIdPathUri, userId uint) (*dto.CollectionResBody, error) {
+	collection, err := s.repo.GetCollectionById(userId, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	return convertCollection(collection), nil
+}
+
+// UpdateCollection replaces the title, the description and the visibility of a collection of the user, its recipes are kept
+func (s *collectionService) UpdateCollection(input *dto.CommonIdPathUri, body *dto.CollectionReqBody, userId uint) (*dto.CollectionResBody, error) {
+	collection := body.ConvertToModel(userId)
+	collection.ID = input.ID
+	collection, err := s.repo.UpdateCollection(collection)
+	if err != nil {
+		return nil, err
+	}
+	return convertCollection(collection), nil
+}
+
+// DeleteCollectionById deletes a collection of the user by ID, its recipes aren't deleted
+func (s *collectionService) DeleteCollectionById(input *dto.CommonIdPathUri, userId uint) error {
+	return s.repo.DeleteCollectionById(userId, input.ID)
+}
+
+// AddRecipeToCollection adds the recipe at the end of a collection of the user, the recipe isn't moved when it's already there
+func (s *collectionService) AddRecipeToCollection(input *dto.CollectionRecipePathUri, userId uint) (*dto.CollectionResBody, error) {
+	_, err := s.recipeRepo.GetRecipeById(input.RecipeID)
+	if err != nil {
+		return nil, err
+	}
+	collection, err := s.repo.AddRecipeToCollection(userId, input.ID, input.RecipeID)
+	if err != nil {
+		return nil, err
+	}
+	return convertCollection(collection), nil
+}
+
+// DeleteRecipeFromCollection removes the recipe from a collection of the user
+func (s *collectionService) DeleteRecipeFromCollection(input *dto.CollectionRecipePathUri, userId uint) (*dto.CollectionResBody, error) {
+	collection, err := s.repo.DeleteRecipeFromCollection(userId, input.ID, input.RecipeID)
+	if err != nil {
+		return nil, err
+	}
+	return convertCollection(collection), nil
+}
+
+// ReorderCollectionRecipes changes the order of the recipes of a collection of the user, all its recipes must be given once
+func (s *collectionService) ReorderCollectionRecipes(input *dto.CommonIdPathUri, body *dto.CollectionRecipesOrderReqBody, userId uint) (*dto.CollectionResBody, error) {
+	collection, err := s.repo.ReorderCollectionRecipes(userId, input.ID, body.RecipesId)
+	if err != nil {
+		return nil, err
+	}
+	return convertCollection(collection), nil
+}
+
+// GetCollectionShareToken returns the secret token of the share link of a collection of the user, it's generated on the first call
+func (s *collectionService) GetCollectionShareToken(input *dto.CommonIdPathUri, userId uint) (*dto.CollectionShareResBody, error) {
+	collection, err := s.repo.GetCollectionById(userId, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	if collection.ShareToken == nil {
+		return s.RegenerateCollectionShareToken(input, userId)
+	}
+	return &dto.CollectionShareResBody{Token: *collection.ShareToken}, nil
+}
+
+// RegenerateCollectionShareToken replaces the secret token of the share link of a collection of the user, the previous link stops working
+func (s *collectionService) RegenerateCollectionShareToken(input *dto.CommonIdPathUri, userId uint) (*dto.CollectionShareResBody, error) {
+	token, err := newSecretToken()
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.repo.SetCollectionShareToken(userId, input.ID, &token)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.CollectionShareResBody{Token: token}, nil
+}
+
+// DeleteCollectionShareToken stops sharing a collection of the user, its share link stops working
+func (s *collectionService) DeleteCollectionShareToken(input *dto.CommonIdPathUri, userId uint) error {
+	_, err := s.repo.SetCollectionShareToken(userId, input.ID, nil)
+	return err
+}
+
+// GetAllPublicCollections returns a page of the public collections of every user or of the user of the query
+func (s *collectionService) GetAllPublicCollections(query *dto.PublicCollectionQuery) (*dto.CommonPageRespBody, error) {
+	collections, totalCollections, err := s.repo.GetAllPublicCollections(query.UserId, query.PageSize, query.PageNumber)
+	if err != nil {
+		return nil, err
+	}
+	return newCollectionsPage(&query.CommonQueryPage, totalCollections, collections), nil
+}
+
+// GetPublicCollectionById returns a public collection by ID, the private collections aren't found
+func (s *collectionService) GetPublicCollectionById(input *dto.CommonIdPathUri) (*dto.CollectionResBody, error) {
+	collection, err := s.repo.GetPublicCollectionById(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	return convertCollection(collection), nil
+}
+
+// GetSharedCollection returns the collection shared with the token of the share link, even when it's private
+func (s *collectionService) GetSharedCollection(input *dto.CollectionTokenPathUri) (*dto.CollectionResBody, error) {
+	collection, err := s.repo.GetCollectionByShareToken(input.Token)
+	if err != nil {
+		return nil, err
+	}
+	return convertCollection(collection), nil
+}
+
+// convertCollection converts a collection model to its response body
+func convertCollection(collection *models.Collection) *dto.CollectionResBody {
+	collectionRes := &dto.CollectionResBody{}
+	collectionRes.ConvertFromModel(collection)
+	return collectionRes
+}
+
+// newCollectionsPage returns the page of the collections converted to their response body
+func newCollectionsPage(query *dto.CommonQueryPage, totalCollections int64, collections []*models.Collection) *dto.CommonPageRespBody {
+	collectionsRes := make([]interface{}, len(collections))
+	for i, v := range collections {
+		res := dto.CollectionResBody{}
+		res.ConvertFromModel(v)
+		collectionsRes[i] = res
+	}
+	return newPageRespBody(query, totalCollections, collectionsRes)
+}
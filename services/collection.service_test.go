@@ -0,0 +1,226 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/repositories"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type mockCollectionRepository struct {
+	collection *models.Collection
+}
+
+func newMockCollectionRepository() *mockCollectionRepository {
+	return &mockCollectionRepository{collection: &models.Collection{
+		Model:  gorm.Model{ID: 1},
+		Title:  "sunday lunch",
+		UserID: 1,
+		User:   &models.User{Model: gorm.Model{ID: 1}, FirstName: "john"},
+	}}
+}
+
+func (m *mockCollectionRepository) CreateCollection(collection *models.Collection) (*models.Collection, error) {
+	collection.ID = 2
+	return collection, nil
+}
+
+func (m *mockCollectionRepository) GetAllCollections(userId uint, pageSize, pageNumber int) ([]*models.Collection, int64, error) {
+	if userId != m.collection.UserID {
+		return []*models.Collection{}, 0, nil
+	}
+	return []*models.Collection{m.collection}, 1, nil
+}
+
+func (m *mockCollectionRepository) GetCollectionById(userId, collectionId uint) (*models.Collection, error) {
+	if userId != m.collection.UserID || collectionId != m.collection.ID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return m.collection, nil
+}
+
+func (m *mockCollectionRepository) UpdateCollection(collection *models.Collection) (*models.Collection, error) {
+	if _, err := m.GetCollectionById(collection.UserID, collection.ID); err != nil {
+		return nil, err
+	}
+	m.collection.Title = collection.Title
+	m.collection.Description = collection.Description
+	m.collection.Public = collection.Public
+	return m.collection, nil
+}
+
+func (m *mockCollectionRepository) DeleteCollectionById(userId, collectionId uint) error {
+	_, err := m.GetCollectionById(userId, collectionId)
+	return err
+}
+
+func (m *mockCollectionRepository) AddRecipeToCollection(userId, collectionId, recipeId uint) (*models.Collection, error) {
+	if _, err := m.GetCollectionById(userId, collectionId); err != nil {
+		return nil, err
+	}
+	recipe, _ := (&mockRecipeRepository{}).GetRecipeById(recipeId)
+	m.collection.Recipes = append(m.collection.Recipes, &models.CollectionRecipe{
+		CollectionID: collectionId,
+		RecipeID:     recipeId,
+		Recipe:       recipe,
+		Position:     uint(len(m.collection.Recipes) + 1),
+	})
+	return m.collection, nil
+}
+
+func (m *mockCollectionRepository) DeleteRecipeFromCollection(userId, collectionId, recipeId uint) (*models.Collection, error) {
+	if _, err := m.GetCollectionById(userId, collectionId); err != nil {
+		return nil, err
+	}
+	for i, v := range m.collection.Recipes {
+		if v.RecipeID == recipeId {
+			m.collection.Recipes = append(m.collection.Recipes[:i], m.collection.Recipes[i+1:]...)
+			return m.collection, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockCollectionRepository) ReorderCollectionRecipes(userId, collectionId uint, recipesId []uint) (*models.Collection, error) {
+	if _, err := m.GetCollectionById(userId, collectionId); err != nil {
+		return nil, err
+	}
+	if len(recipesId) != len(m.collection.Recipes) {
+		return nil, repositories.ErrCollectionOrderNotAcceptable
+	}
+	return m.collection, nil
+}
+
+func (m *mockCollectionRepository) SetCollectionShareToken(userId, collectionId uint, token *string) (*models.Collection, error) {
+	if _, err := m.GetCollectionById(userId, collectionId); err != nil {
+		return nil, err
+	}
+	m.collection.ShareToken = token
+	return m.collection, nil
+}
+
+func (m *mockCollectionRepository) GetAllPublicCollections(ownerId uint, pageSize, pageNumber int) ([]*models.Collection, int64, error) {
+	if !m.collection.Public || (ownerId != 0 && ownerId != m.collection.UserID) {
+		return []*models.Collection{}, 0, nil
+	}
+	return []*models.Collection{m.collection}, 1, nil
+}
+
+func (m *mockCollectionRepository) GetPublicCollectionById(collectionId uint) (*models.Collection, error) {
+	if !m.collection.Public || collectionId != m.collection.ID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return m.collection, nil
+}
+
+func (m *mockCollectionRepository) GetCollectionByShareToken(token string) (*models.Collection, error) {
+	if m.collection.ShareToken == nil || *m.collection.ShareToken != token {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return m.collection, nil
+}
+
+func TestCreateCollection(t *testing.T) {
+	collectionService := services.NewCollectionService(newMockCollectionRepository(), &mockRecipeRepository{})
+	body := &dto.CollectionReqBody{Title: "welsh classics", Description: "the recipes of my grandmother", Public: true}
+	collectionRes, err := collectionService.CreateCollection(body, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), collectionRes.ID)
+	assert.Equal(t, "welsh classics", collectionRes.Title)
+	assert.True(t, collectionRes.Public)
+	assert.False(t, collectionRes.Shared)
+	assert.Equal(t, uint(1), collectionRes.AuthorId)
+	assert.Empty(t, collectionRes.Recipes)
+}
+
+func TestUpdateCollection(t *testing.T) {
+	collectionService := services.NewCollectionService(newMockCollectionRepository(), &mockRecipeRepository{})
+	body := &dto.CollectionReqBody{Title: "sunday dinner", Public: true}
+	// test happy path
+	collectionRes, err := collectionService.UpdateCollection(&dto.CommonIdPathUri{ID: 1}, body, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "sunday dinner", collectionRes.Title)
+	assert.True(t, collectionRes.Public)
+	assert.Equal(t, "john", collectionRes.AuthorName)
+	// test error: the collection belongs to another user
+	collectionRes, err = collectionService.UpdateCollection(&dto.CommonIdPathUri{ID: 1}, body, 2)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, collectionRes)
+}
+
+func TestCollectionRecipes(t *testing.T) {
+	collectionService := services.NewCollectionService(newMockCollectionRepository(), &mockRecipeRepository{})
+	// test happy path
+	collectionRes, err := collectionService.AddRecipeToCollection(&dto.CollectionRecipePathUri{ID: 1, RecipeID: 1}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, collectionRes.RecipeCount)
+	assert.Equal(t, "welsh rarebit", collectionRes.Recipes[0].Title)
+	assert.Equal(t, uint(1), collectionRes.Recipes[0].Position)
+	// test error: the recipe doesn't exist
+	collectionRes, err = collectionService.AddRecipeToCollection(&dto.CollectionRecipePathUri{ID: 1, RecipeID: 2}, 1)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, collectionRes)
+	// test error: the order doesn't contain every recipe
+	collectionRes, err = collectionService.ReorderCollectionRecipes(&dto.CommonIdPathUri{ID: 1}, &dto.CollectionRecipesOrderReqBody{RecipesId: []uint{1, 2}}, 1)
+	assert.ErrorIs(t, err, repositories.ErrCollectionOrderNotAcceptable)
+	assert.Nil(t, collectionRes)
+	// test happy path
+	collectionRes, err = collectionService.DeleteRecipeFromCollection(&dto.CollectionRecipePathUri{ID: 1, RecipeID: 1}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, collectionRes.RecipeCount)
+}
+
+func TestCollectionShareToken(t *testing.T) {
+	collectionService := services.NewCollectionService(newMockCollectionRepository(), &mockRecipeRepository{})
+	// test happy path: the token is generated on the first call and kept on the next ones
+	shareRes, err := collectionService.GetCollectionShareToken(&dto.CommonIdPathUri{ID: 1}, 1)
+	assert.NoError(t, err)
+	assert.Len(t, shareRes.Token, 64)
+	sameShareRes, err := collectionService.GetCollectionShareToken(&dto.CommonIdPathUri{ID: 1}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, shareRes.Token, sameShareRes.Token)
+	// test happy path: the private collection is read with its token
+	collectionRes, err := collectionService.GetSharedCollection(&dto.CollectionTokenPathUri{Token: shareRes.Token})
+	assert.NoError(t, err)
+	assert.True(t, collectionRes.Shared)
+	// test happy path: the previous token stops working once regenerated
+	newShareRes, err := collectionService.RegenerateCollectionShareToken(&dto.CommonIdPathUri{ID: 1}, 1)
+	assert.NoError(t, err)
+	assert.NotEqual(t, shareRes.Token, newShareRes.Token)
+	_, err = collectionService.GetSharedCollection(&dto.CollectionTokenPathUri{Token: shareRes.Token})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	// test happy path: the collection isn't shared anymore
+	err = collectionService.DeleteCollectionShareToken(&dto.CommonIdPathUri{ID: 1}, 1)
+	assert.NoError(t, err)
+	_, err = collectionService.GetSharedCollection(&dto.CollectionTokenPathUri{Token: newShareRes.Token})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	// test error: the collection belongs to another user
+	shareRes, err = collectionService.GetCollectionShareToken(&dto.CommonIdPathUri{ID: 1}, 2)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, shareRes)
+}
+
+func TestGetPublicCollections(t *testing.T) {
+	repo := newMockCollectionRepository()
+	collectionService := services.NewCollectionService(repo, &mockRecipeRepository{})
+	query := &dto.PublicCollectionQuery{CommonQueryPage: dto.CommonQueryPage{PageSize: 10}}
+	// test happy path: the private collections aren't listed
+	pageRes, err := collectionService.GetAllPublicCollections(query)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, pageRes.TotalNbResult)
+	_, err = collectionService.GetPublicCollectionById(&dto.CommonIdPathUri{ID: 1})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	// test happy path
+	repo.collection.Public = true
+	pageRes, err = collectionService.GetAllPublicCollections(query)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, pageRes.TotalNbResult)
+	assert.Equal(t, "sunday lunch", pageRes.Items[0].(dto.CollectionResBody).Title)
+	collectionRes, err := collectionService.GetPublicCollectionById(&dto.CommonIdPathUri{ID: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), collectionRes.ID)
+}
@@ -0,0 +1,126 @@
+// The package 'services' contains the business logic for handling route
+package services
+
+import (
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/repositories"
+	"go.uber.org/zap"
+)
+
+// CommentService is an interface for defining the methods to manage the comment threads of the recipes
+type CommentService interface {
+	GetAllComments(input *dto.CommonIdPathUri, query *dto.CommonQueryPage) (*dto.CommonPageRespBody, error)
+	CreateComment(input *dto.CommonIdPathUri, body *dto.CommentReqBody, userId uint) (*dto.CommentResBody, error)
+	UpdateComment(input *dto.CommentPathUri, body *dto.CommentReqBody, userId uint) (*dto.CommentResBody, error)
+	DeleteComment(input *dto.CommentPathUri, userId uint) error
+	PinComment(input *dto.CommentPathUri, pinned bool, userId uint) (*dto.CommentResBody, error)
+}
+
+// commentService is an implementation of the CommentService interface
+type commentService struct {
+	repo       repositories.CommentRepository
+	recipeRepo repositories.RecipeRepository
+	userRepo   repositories.UserRepository
+	logger     *zap.Logger
+}
+
+// NewCommentService creates a new CommentService instance, the recipe and the user repositories are used to check the user permissions
+func NewCommentService(repo repositories.CommentRepository, recipeRepo repositories.RecipeRepository, userRepo repositories.UserRepository) CommentService {
+	return &commentService{
+		repo:       repo,
+		recipeRepo: recipeRepo,
+		userRepo:   userRepo,
+		logger:     zap.L(),
+	}
+}
+
+// GetAllComments returns a page of the comment threads of the recipe
+func (s *commentService) GetAllComments(input *dto.CommonIdPathUri, query *dto.CommonQueryPage) (*dto.CommonPageRespBody, error) {
+	_, err := s.recipeRepo.GetRecipeById(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	threads, totalThreads, err := s.repo.GetAllComments(input.ID, query.PageSize, query.PageNumber)
+	if err != nil {
+		return nil, err
+	}
+	threadsRes := make([]interface{}, len(threads))
+	for i, v := range threads {
+		res := dto.CommentResBody{}
+		res.ConvertFromModel(v)
+		threadsRes[i] = res
+	}
+	return newPageRespBody(query, totalThreads, threadsRes), nil
+}
+
+// CreateComment adds a comment of the user on the recipe, or a reply when the body references a parent comment
+func (s *commentService) CreateComment(input *dto.CommonIdPathUri, body *dto.CommentReqBody, userId uint) (*dto.CommentResBody, error) {
+	_, err := s.recipeRepo.GetRecipeById(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := s.repo.CreateComment(body.ConvertToModel(userId, input.ID))
+	if err != nil {
+		return nil, err
+	}
+	commentRes := &dto.CommentResBody{}
+	commentRes.ConvertFromModel(comment)
+	return commentRes, nil
+}
+
+// UpdateComment replaces the text of the comment, only the author of the comment or an administrator can edit it
+func (s *commentService) UpdateComment(input *dto.CommentPathUri, body *dto.CommentReqBody, userId uint) (*dto.CommentResBody, error) {
+	comment, err := s.repo.GetCommentById(input.ID, input.CommentID)
+	if err != nil {
+		return nil, err
+	}
+	err = checkAuthorOrAdmin(s.userRepo, comment.UserID, userId)
+	if err != nil {
+		return nil, err
+	}
+	comment.Text = body.Text
+	comment, err = s.repo.UpdateComment(comment)
+	if err != nil {
+		return nil, err
+	}
+	commentRes := &dto.CommentResBody{}
+	commentRes.ConvertFromModel(comment)
+	return commentRes, nil
+}
+
+// DeleteComment deletes the comment, only the author of the comment or an administrator can delete it
+func (s *commentService) DeleteComment(input *dto.CommentPathUri, userId uint) error {
+	comment, err := s.repo.GetCommentById(input.ID, input.CommentID)
+	if err != nil {
+		return err
+	}
+	err = checkAuthorOrAdmin(s.userRepo, comment.UserID, userId)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteComment(comment)
+}
+
+// PinComment pins or unpins the comment at the top of the list, only the author of the recipe or an administrator can pin it
+func (s *commentService) PinComment(input *dto.CommentPathUri, pinned bool, userId uint) (*dto.CommentResBody, error) {
+	recipe, err := s.recipeRepo.GetRecipeById(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	err = checkAuthorOrAdmin(s.userRepo, uint(recipe.AuthorID), userId)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := s.repo.GetCommentById(input.ID, input.CommentID)
+	if err != nil {
+		return nil, err
+	}
+	comment.Pinned = pinned
+	comment, err = s.repo.UpdateComment(comment)
+	if err != nil {
+		return nil, err
+	}
+	commentRes := &dto.CommentResBody{}
+	commentRes.ConvertFromModel(comment)
+	return commentRes, nil
+}
@@ -0,0 +1,129 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type mockCommentRepository struct{}
+
+func (m *mockCommentRepository) CreateComment(comment *models.Comment) (*models.Comment, error) {
+	comment.ID = 3
+	comment.User = &models.User{Model: gorm.Model{ID: comment.UserID}, FirstName: "Gwen"}
+	return comment, nil
+}
+
+func (m *mockCommentRepository) GetAllComments(recipeId uint, pageSize, pageNumber int) ([]*models.Comment, int64, error) {
+	parentId := uint(1)
+	comments := []*models.Comment{
+		{
+			Model:    gorm.Model{ID: 1, DeletedAt: gorm.DeletedAt{Valid: true}},
+			RecipeID: recipeId,
+			UserID:   2,
+			Text:     "removed",
+			Replies: []*models.Comment{
+				{Model: gorm.Model{ID: 2}, RecipeID: recipeId, UserID: 3, ParentID: &parentId, Text: "agreed", Replies: []*models.Comment{}},
+			},
+		},
+	}
+	return comments, 7, nil
+}
+
+func (m *mockCommentRepository) GetCommentById(recipeId, commentId uint) (*models.Comment, error) {
+	if recipeId == 1 && commentId == 1 {
+		return &models.Comment{Model: gorm.Model{ID: 1}, RecipeID: 1, UserID: 1, Text: "first"}, nil
+	}
+	if recipeId == 1 && commentId == 2 {
+		return &models.Comment{Model: gorm.Model{ID: 2}, RecipeID: 1, UserID: 2, Text: "second"}, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockCommentRepository) UpdateComment(comment *models.Comment) (*models.Comment, error) {
+	return comment, nil
+}
+
+func (m *mockCommentRepository) DeleteComment(comment *models.Comment) error {
+	return nil
+}
+
+func TestGetAllComments(t *testing.T) {
+	commentService := services.NewCommentService(&mockCommentRepository{}, &mockRecipeRepository{}, &mockUserRepository{})
+	query := &dto.CommonQueryPage{PageSize: 5, PageNumber: 0}
+	pageRes, err := commentService.GetAllComments(&dto.CommonIdPathUri{ID: 1}, query)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, pageRes.TotalNbResult)
+	assert.Equal(t, 2, pageRes.TotablNbPage)
+	assert.Len(t, pageRes.Items, 1)
+	thread := pageRes.Items[0].(dto.CommentResBody)
+	// the deleted comment is kept to show its replies without its content
+	assert.True(t, thread.Deleted)
+	assert.Empty(t, thread.Text)
+	assert.Zero(t, thread.AuthorId)
+	assert.Len(t, thread.Replies, 1)
+	assert.Equal(t, "agreed", thread.Replies[0].Text)
+	// test error: recipe not found
+	_, err = commentService.GetAllComments(&dto.CommonIdPathUri{ID: 2}, query)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestCreateComment(t *testing.T) {
+	commentService := services.NewCommentService(&mockCommentRepository{}, &mockRecipeRepository{}, &mockUserRepository{})
+	parentId := uint(1)
+	body := &dto.CommentReqBody{Text: "which cheddar?", ParentId: &parentId}
+	commentRes, err := commentService.CreateComment(&dto.CommonIdPathUri{ID: 1}, body, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), commentRes.ID)
+	assert.Equal(t, &parentId, commentRes.ParentId)
+	assert.Equal(t, uint(3), commentRes.AuthorId)
+	assert.Equal(t, "Gwen", commentRes.AuthorName)
+	// test error: recipe not found
+	commentRes, err = commentService.CreateComment(&dto.CommonIdPathUri{ID: 2}, body, 3)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, commentRes)
+}
+
+func TestUpdateComment(t *testing.T) {
+	commentService := services.NewCommentService(&mockCommentRepository{}, &mockRecipeRepository{}, &mockUserRepository{})
+	body := &dto.CommentReqBody{Text: "edited"}
+	commentRes, err := commentService.UpdateComment(&dto.CommentPathUri{ID: 1, CommentID: 1}, body, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "edited", commentRes.Text)
+	// an administrator can edit the comments of the other users
+	_, err = commentService.UpdateComment(&dto.CommentPathUri{ID: 1, CommentID: 2}, body, 3)
+	assert.NoError(t, err)
+	// test error: the user isn't the author of the comment
+	_, err = commentService.UpdateComment(&dto.CommentPathUri{ID: 1, CommentID: 2}, body, 1)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	// test error: comment not found
+	_, err = commentService.UpdateComment(&dto.CommentPathUri{ID: 1, CommentID: 4}, body, 1)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestDeleteComment(t *testing.T) {
+	commentService := services.NewCommentService(&mockCommentRepository{}, &mockRecipeRepository{}, &mockUserRepository{})
+	assert.NoError(t, commentService.DeleteComment(&dto.CommentPathUri{ID: 1, CommentID: 1}, 1))
+	// test error: the user isn't the author of the comment
+	assert.ErrorIs(t, commentService.DeleteComment(&dto.CommentPathUri{ID: 1, CommentID: 2}, 1), services.ErrForbidden)
+	// test error: comment not found
+	assert.ErrorIs(t, commentService.DeleteComment(&dto.CommentPathUri{ID: 2, CommentID: 1}, 1), gorm.ErrRecordNotFound)
+}
+
+func TestPinComment(t *testing.T) {
+	commentService := services.NewCommentService(&mockCommentRepository{}, &mockRecipeRepository{}, &mockUserRepository{})
+	// the author of the recipe can pin the comments of the other users
+	commentRes, err := commentService.PinComment(&dto.CommentPathUri{ID: 1, CommentID: 2}, true, 1)
+	assert.NoError(t, err)
+	assert.True(t, commentRes.Pinned)
+	commentRes, err = commentService.PinComment(&dto.CommentPathUri{ID: 1, CommentID: 2}, false, 1)
+	assert.NoError(t, err)
+	assert.False(t, commentRes.Pinned)
+	// test error: the user isn't the author of the recipe
+	_, err = commentService.PinComment(&dto.CommentPathUri{ID: 1, CommentID: 2}, true, 2)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+}
@@ -0,0 +1,242 @@
+// The package 'services' contains the business logic for handling route
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/media"
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/repositories"
+	"go.uber.org/zap"
+)
+
+// maxRecipeImages is the maximum number of photos of a recipe
+const maxRecipeImages = 20
+
+// ErrRecipeImagesNotAcceptable is returned when a photo is added to a recipe which already has the maximum number of photos
+var ErrRecipeImagesNotAcceptable = fmt.Errorf("a recipe has at most %d photos, image not acceptable", maxRecipeImages)
+
+// ImageService is an interface for defining the methods to upload the photos of the recipes and the images of the ingredients
+type ImageService interface {
+	UploadRecipeImage(input *dto.CommonIdPathUri, body *dto.RecipeImageReqBody, userId uint) (*dto.RecipeImageResBody, error)
+	GetAllRecipeImages(input *dto.CommonIdPathUri) ([]*dto.RecipeImageResBody, error)
+	SetRecipeCoverImage(input *dto.RecipeImagePathUri, userId uint) (*dto.RecipeImageResBody, error)
+	DeleteRecipeImage(input *dto.RecipeImagePathUri, userId uint) error
+	UploadIngredientImage(input *dto.CommonIdPathUri, body *dto.ImageReqBody, userId uint) (*dto.IngredientResBody, error)
+	DeleteIngredientImage(input *dto.CommonIdPathUri, userId uint) error
+}
+
+// imageService is an implementation of the ImageService interface
+type imageService struct {
+	repo           repositories.ImageRepository
+	recipeRepo     repositories.RecipeRepository
+	ingredientRepo repositories.IngredientRepository
+	userRepo       repositories.UserRepository
+	storage        media.Storage
+	maxImageSize   int64
+	logger         *zap.Logger
+}
+
+// NewImageService creates a new ImageService instance, the files are written in the storage and the uploads
+// bigger than maxImageSize bytes are refused. The user repository is used to check the user is the author of the recipe or an administrator,
+// only an administrator can change the images of the ingredients.
+func NewImageService(repo repositories.ImageRepository, recipeRepo repositories.RecipeRepository, ingredientRepo repositories.IngredientRepository,
+	userRepo repositories.UserRepository, storage media.Storage, maxImageSize int64) ImageService {
+	return &imageService{
+		repo:           repo,
+		recipeRepo:     recipeRepo,
+		ingredientRepo: ingredientRepo,
+		userRepo:       userRepo,
+		storage:        storage,
+		maxImageSize:   maxImageSize,
+		logger:         zap.L(),
+	}
+}
+
+// UploadRecipeImage adds a photo to the recipe, only its author or an administrator can add it.
+// The first photo of the recipe becomes its cover.
+func (s *imageService) UploadRecipeImage(input *dto.CommonIdPathUri, body *dto.RecipeImageReqBody, userId uint) (*dto.RecipeImageResBody, error) {
+	recipe, err := s.recipeRepo.GetRecipeById(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	err = checkAuthorOrAdmin(s.userRepo, uint(recipe.AuthorID), userId)
+	if err != nil {
+		return nil, err
+	}
+	totalImages, err := s.repo.CountRecipeImages(recipe.ID)
+	if err != nil {
+		return nil, err
+	}
+	if totalImages >= maxRecipeImages {
+		return nil, ErrRecipeImagesNotAcceptable
+	}
+	stored, err := s.storeImage(fmt.Sprintf("recipes/%d", recipe.ID), body.Image)
+	if err != nil {
+		return nil, err
+	}
+	image, err := s.repo.CreateRecipeImage(&models.RecipeImage{RecipeID: recipe.ID, Image: *stored, Cover: body.Cover})
+	if err != nil {
+		deleteImageFiles(s.storage, s.logger, stored)
+		return nil, err
+	}
+	imageRes := &dto.RecipeImageResBody{}
+	imageRes.ConvertFromModel(image)
+	imageRes.SetUrls(s.storage)
+	return imageRes, nil
+}
+
+// GetAllRecipeImages returns the photos of the recipe, the cover first
+func (s *imageService) GetAllRecipeImages(input *dto.CommonIdPathUri) ([]*dto.RecipeImageResBody, error) {
+	recipe, err := s.recipeRepo.GetRecipeById(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	images, err := s.repo.GetAllRecipeImages(recipe.ID)
+	if err != nil {
+		return nil, err
+	}
+	imagesRes := dto.ConvertRecipeImagesFromModel(images)
+	dto.SetRecipeImagesUrls(imagesRes, s.storage)
+	return imagesRes, nil
+}
+
+// SetRecipeCoverImage makes the photo the cover of the recipe, only its author or an administrator can change it
+func (s *imageService) SetRecipeCoverImage(input *dto.RecipeImagePathUri, userId uint) (*dto.RecipeImageResBody, error) {
+	recipe, err := s.recipeRepo.GetRecipeById(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	err = checkAuthorOrAdmin(s.userRepo, uint(recipe.AuthorID), userId)
+	if err != nil {
+		return nil, err
+	}
+	image, err := s.repo.SetRecipeCoverImage(recipe.ID, input.ImageID)
+	if err != nil {
+		return nil, err
+	}
+	imageRes := &dto.RecipeImageResBody{}
+	imageRes.ConvertFromModel(image)
+	imageRes.SetUrls(s.storage)
+	return imageRes, nil
+}
+
+// DeleteRecipeImage deletes the photo of the recipe and its files, only its author or an administrator can delete it
+func (s *imageService) DeleteRecipeImage(input *dto.RecipeImagePathUri, userId uint) error {
+	recipe, err := s.recipeRepo.GetRecipeById(input.ID)
+	if err != nil {
+		return err
+	}
+	err = checkAuthorOrAdmin(s.userRepo, uint(recipe.AuthorID), userId)
+	if err != nil {
+		return err
+	}
+	image, err := s.repo.DeleteRecipeImage(recipe.ID, input.ImageID)
+	if err != nil {
+		return err
+	}
+	deleteImageFiles(s.storage, s.logger, &image.Image)
+	return nil
+}
+
+// UploadIngredientImage sets the image of the ingredient, only an administrator can change it and the files of the former image are deleted.
+// The ingredient is returned with its new image.
+func (s *imageService) UploadIngredientImage(input *dto.CommonIdPathUri, body *dto.ImageReqBody, userId uint) (*dto.IngredientResBody, error) {
+	err := checkAdmin(s.userRepo, userId)
+	if err != nil {
+		return nil, err
+	}
+	ingredient, err := s.ingredientRepo.GetIngredientById(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := s.storeImage(fmt.Sprintf("ingredients/%d", ingredient.ID), body.Image)
+	if err != nil {
+		return nil, err
+	}
+	image, err := s.repo.SaveIngredientImage(&models.IngredientImage{IngredientID: ingredient.ID, Image: *stored})
+	if err != nil {
+		deleteImageFiles(s.storage, s.logger, stored)
+		return nil, err
+	}
+	if ingredient.Image != nil {
+		deleteImageFiles(s.storage, s.logger, &ingredient.Image.Image)
+	}
+	ingredient.Image = image
+	ingredientRes := &dto.IngredientResBody{}
+	ingredientRes.ConvertFromModel(ingredient)
+	ingredientRes.SetImageUrls(s.storage)
+	return ingredientRes, nil
+}
+
+// DeleteIngredientImage deletes the image of the ingredient and its files, only an administrator can delete it
+func (s *imageService) DeleteIngredientImage(input *dto.CommonIdPathUri, userId uint) error {
+	err := checkAdmin(s.userRepo, userId)
+	if err != nil {
+		return err
+	}
+	ingredient, err := s.ingredientRepo.GetIngredientById(input.ID)
+	if err != nil {
+		return err
+	}
+	err = s.repo.DeleteIngredientImage(ingredient.ID)
+	if err != nil {
+		return err
+	}
+	if ingredient.Image != nil {
+		deleteImageFiles(s.storage, s.logger, &ingredient.Image.Image)
+	}
+	return nil
+}
+
+// storeImage validates the uploaded file and writes it with its thumbnail in the directory of the storage,
+// the files get a random name so the URL of a replaced image is never reused
+func (s *imageService) storeImage(dir string, upload *multipart.FileHeader) (*models.Image, error) {
+	if upload.Size > s.maxImageSize {
+		return nil, media.ErrImageTooLarge
+	}
+	file, err := upload.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	img, err := media.ReadImage(file, s.maxImageSize)
+	if err != nil {
+		return nil, err
+	}
+	name, err := newSecretToken()
+	if err != nil {
+		return nil, err
+	}
+	stored := &models.Image{
+		Path:          fmt.Sprintf("%s/%s%s", dir, name, img.Extension),
+		ThumbnailPath: fmt.Sprintf("%s/%s_thumb%s", dir, name, img.ThumbnailExtension),
+		ContentType:   img.ContentType,
+		Width:         img.Width,
+		Height:        img.Height,
+		Size:          int64(len(img.Content)),
+	}
+	err = s.storage.Save(stored.Path, bytes.NewReader(img.Content))
+	if err != nil {
+		return nil, err
+	}
+	err = s.storage.Save(stored.ThumbnailPath, bytes.NewReader(img.Thumbnail))
+	if err != nil {
+		deleteImageFiles(s.storage, s.logger, stored)
+		return nil, err
+	}
+	return stored, nil
+}
+
+// deleteImageFiles removes the files of the image from the storage, a failure is only logged because the image is already forgotten
+func deleteImageFiles(storage media.Storage, logger *zap.Logger, image *models.Image) {
+	for _, path := range []string{image.Path, image.ThumbnailPath} {
+		err := storage.Delete(path)
+		if err != nil {
+			logger.Warn("Unable to delete the image file", zap.String("path", path), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package services_test
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"strings"
+	"testing"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/media"
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type mockStorage struct {
+	files map[string][]byte
+}
+
+func (m *mockStorage) Save(path string, content io.Reader) error {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+	m.files[path] = data
+	return nil
+}
+
+func (m *mockStorage) Delete(path string) error {
+	delete(m.files, path)
+	return nil
+}
+
+func (m *mockStorage) Url(path string) string {
+	if path == "" {
+		return ""
+	}
+	return "https://cdn.example.com/" + path
+}
+
+type mockImageRepository struct {
+	recipeImages    []*models.RecipeImage
+	ingredientImage *models.IngredientImage
+}
+
+func (m *mockImageRepository) CreateRecipeImage(image *models.RecipeImage) (*models.RecipeImage, error) {
+	image.ID = uint(len(m.recipeImages) + 1)
+	image.Cover = image.Cover || len(m.recipeImages) == 0
+	m.recipeImages = append(m.recipeImages, image)
+	return image, nil
+}
+
+func (m *mockImageRepository) CountRecipeImages(recipeId uint) (int64, error) {
+	return int64(len(m.recipeImages)), nil
+}
+
+func (m *mockImageRepository) GetAllRecipeImages(recipeId uint) ([]*models.RecipeImage, error) {
+	return m.recipeImages, nil
+}
+
+func (m *mockImageRepository) SetRecipeCoverImage(recipeId, imageId uint) (*models.RecipeImage, error) {
+	for _, v := range m.recipeImages {
+		if v.ID == imageId {
+			v.Cover = true
+			return v, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockImageRepository) DeleteRecipeImage(recipeId, imageId uint) (*models.RecipeImage, error) {
+	for i, v := range m.recipeImages {
+		if v.ID == imageId {
+			m.recipeImages = append(m.recipeImages[:i], m.recipeImages[i+1:]...)
+			return v, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockImageRepository) SaveIngredientImage(image *models.IngredientImage) (*models.IngredientImage, error) {
+	m.ingredientImage = image
+	return image, nil
+}
+
+func (m *mockImageRepository) DeleteIngredientImage(ingredientId uint) error {
+	if m.ingredientImage == nil {
+		return gorm.ErrRecordNotFound
+	}
+	m.ingredientImage = nil
+	return nil
+}
+
+// newImageUpload returns the file of a multipart form uploading the content
+func newImageUpload(t *testing.T, content []byte) *multipart.FileHeader {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("image", "photo.png")
+	assert.NoError(t, err)
+	_, err = part.Write(content)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(1 << 20)
+	assert.NoError(t, err)
+	return form.File["image"][0]
+}
+
+// newPngImage returns a PNG image of the size
+func newPngImage(t *testing.T, width, height int) []byte {
+	var content bytes.Buffer
+	assert.NoError(t, png.Encode(&content, image.NewRGBA(image.Rect(0, 0, width, height))))
+	return content.Bytes()
+}
+
+func TestUploadRecipeImage(t *testing.T) {
+	repo := &mockImageRepository{}
+	storage := &mockStorage{files: map[string][]byte{}}
+	imageService := services.NewImageService(repo, &mockRecipeRepository{}, &mockIngredientRepository{}, &mockUserRepository{}, storage, 1<<20)
+	// test happy path: the first photo becomes the cover
+	body := &dto.RecipeImageReqBody{Image: newImageUpload(t, newPngImage(t, 640, 480))}
+	imageRes, err := imageService.UploadRecipeImage(&dto.CommonIdPathUri{ID: 1}, body, 1)
+	assert.NoError(t, err)
+	assert.True(t, imageRes.Cover)
+	assert.Equal(t, "image/png", imageRes.ContentType)
+	assert.Equal(t, 640, imageRes.Width)
+	assert.True(t, strings.HasPrefix(imageRes.Url, "https://cdn.example.com/recipes/1/"))
+	assert.Len(t, storage.files, 2)
+	assert.Contains(t, storage.files, repo.recipeImages[0].Image.ThumbnailPath)
+	// test happy path: an administrator can add a photo
+	imageRes, err = imageService.UploadRecipeImage(&dto.CommonIdPathUri{ID: 1}, body, 3)
+	assert.NoError(t, err)
+	assert.False(t, imageRes.Cover)
+	// test error: the user isn't the author of the recipe
+	imageRes, err = imageService.UploadRecipeImage(&dto.CommonIdPathUri{ID: 1}, body, 2)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	assert.Nil(t, imageRes)
+	// test error: the file isn't an image
+	body = &dto.RecipeImageReqBody{Image: newImageUpload(t, []byte("welsh rarebit"))}
+	imageRes, err = imageService.UploadRecipeImage(&dto.CommonIdPathUri{ID: 1}, body, 1)
+	assert.ErrorIs(t, err, media.ErrImageNotAcceptable)
+	assert.Nil(t, imageRes)
+	// test error: the image is too large
+	body = &dto.RecipeImageReqBody{Image: newImageUpload(t, make([]byte, 2<<20))}
+	imageRes, err = imageService.UploadRecipeImage(&dto.CommonIdPathUri{ID: 1}, body, 1)
+	assert.ErrorIs(t, err, media.ErrImageTooLarge)
+	assert.Nil(t, imageRes)
+	assert.Len(t, storage.files, 4)
+}
+
+func TestDeleteRecipeImage(t *testing.T) {
+	repo := &mockImageRepository{}
+	storage := &mockStorage{files: map[string][]byte{}}
+	imageService := services.NewImageService(repo, &mockRecipeRepository{}, &mockIngredientRepository{}, &mockUserRepository{}, storage, 1<<20)
+	body := &dto.RecipeImageReqBody{Image: newImageUpload(t, newPngImage(t, 10, 10))}
+	_, err := imageService.UploadRecipeImage(&dto.CommonIdPathUri{ID: 1}, body, 1)
+	assert.NoError(t, err)
+	// test error: the user isn't the author of the recipe
+	err = imageService.DeleteRecipeImage(&dto.RecipeImagePathUri{ID: 1, ImageID: 1}, 2)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	// test happy path: the files are removed from the storage
+	err = imageService.DeleteRecipeImage(&dto.RecipeImagePathUri{ID: 1, ImageID: 1}, 1)
+	assert.NoError(t, err)
+	assert.Empty(t, storage.files)
+	// test error: the photo doesn't exist
+	err = imageService.DeleteRecipeImage(&dto.RecipeImagePathUri{ID: 1, ImageID: 1}, 1)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestUploadIngredientImage(t *testing.T) {
+	repo := &mockImageRepository{}
+	storage := &mockStorage{files: map[string][]byte{}}
+	imageService := services.NewImageService(repo, &mockRecipeRepository{}, &mockIngredientRepository{}, &mockUserRepository{}, storage, 1<<20)
+	// test happy path: an administrator sets the image
+	body := &dto.ImageReqBody{Image: newImageUpload(t, newPngImage(t, 20, 10))}
+	ingredientRes, err := imageService.UploadIngredientImage(&dto.CommonIdPathUri{ID: 1}, body, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), ingredientRes.ID)
+	assert.Equal(t, 20, ingredientRes.Image.Width)
+	assert.True(t, strings.HasPrefix(ingredientRes.Image.ThumbnailUrl, "https://cdn.example.com/ingredients/1/"))
+	assert.Len(t, storage.files, 2)
+	// test error: the user isn't an administrator
+	ingredientRes, err = imageService.UploadIngredientImage(&dto.CommonIdPathUri{ID: 1}, body, 1)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	assert.Nil(t, ingredientRes)
+	// test error: the ingredient doesn't exist
+	ingredientRes, err = imageService.UploadIngredientImage(&dto.CommonIdPathUri{ID: 2}, body, 3)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, ingredientRes)
+	// test error: the user isn't an administrator
+	err = imageService.DeleteIngredientImage(&dto.CommonIdPathUri{ID: 1}, 1)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	// test happy path
+	err = imageService.DeleteIngredientImage(&dto.CommonIdPathUri{ID: 1}, 3)
+	assert.NoError(t, err)
+	// test error: the ingredient has no image anymore
+	err = imageService.DeleteIngredientImage(&dto.CommonIdPathUri{ID: 1}, 3)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
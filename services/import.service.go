@@ -0,0 +1,211 @@
+// The package 'services' contains the business logic for handling route
+package services
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/importer"
+	"github.com/clementb49/welsh_academy/media"
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/repositories"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// MaxImportSize is the maximum size in bytes of an imported document, the HTML pages of the food blogs are big
+const MaxImportSize = 2 << 20
+
+// importedIngredientType is the type of the ingredients created by the imports, an administrator can classify them later
+const importedIngredientType = "imported"
+
+// defaultImportDifficulty is the difficulty of the imported recipes when the import doesn't give it
+const defaultImportDifficulty = 3
+
+// ErrImportTitleConflict is returned when the committed import has the title of another recipe
+var ErrImportTitleConflict = fmt.Errorf("a recipe already has this title, %w", gorm.ErrDuplicatedKey)
+
+// ImportService is an interface for defining the methods to import the recipes published with the schema.org vocabulary
+type ImportService interface {
+	ImportRecipe(content io.Reader, query *dto.RecipeImportQuery) (*dto.RecipeImportResBody, error)
+	CommitRecipeImport(body *dto.RecipeImportCommitReqBody, userId uint) (*dto.RecipeResBody, error)
+}
+
+// importService is an implementation of the ImportService interface
+type importService struct {
+	repo       repositories.ImportRepository
+	recipeRepo repositories.RecipeRepository
+	storage    media.Storage
+	logger     *zap.Logger
+}
+
+// NewImportService creates a new ImportService instance, the recipe repository is used to return the committed recipe
+func NewImportService(repo repositories.ImportRepository, recipeRepo repositories.RecipeRepository, storage media.Storage) ImportService {
+	return &importService{
+		repo:       repo,
+		recipeRepo: recipeRepo,
+		storage:    storage,
+		logger:     zap.L(),
+	}
+}
+
+// ImportRecipe reads the schema.org Recipe of the document and matches its ingredients with the known ones,
+// the recipe is only returned as a preview to be corrected and committed. The title conflicts with another recipe are reported.
+func (s *importService) ImportRecipe(content io.Reader, query *dto.RecipeImportQuery) (*dto.RecipeImportResBody, error) {
+	imported, err := importer.ReadRecipe(content, MaxImportSize)
+	if err != nil {
+		return nil, err
+	}
+	recipe, err := s.convertImportedRecipe(imported, query)
+	if err != nil {
+		return nil, err
+	}
+	nbRecipes, err := s.repo.CountRecipesByTitle(recipe.Title)
+	if err != nil {
+		return nil, err
+	}
+	if nbRecipes > 0 {
+		imported.Warnings = append(imported.Warnings, fmt.Sprintf("a recipe is already titled %q, the title must be changed", recipe.Title))
+	}
+	importRes := &dto.RecipeImportResBody{}
+	importRes.ConvertFromModel(recipe, imported)
+	importRes.TitleConflict = nbRecipes > 0
+	return importRes, nil
+}
+
+// CommitRecipeImport creates the reviewed import for the user, the lines without ingredient ID use the oldest known ingredient
+// with the same name or a new ingredient created with the recipe
+func (s *importService) CommitRecipeImport(body *dto.RecipeImportCommitReqBody, userId uint) (*dto.RecipeResBody, error) {
+	nbRecipes, err := s.repo.CountRecipesByTitle(body.Title)
+	if err != nil {
+		return nil, err
+	}
+	if nbRecipes > 0 {
+		return nil, ErrImportTitleConflict
+	}
+	var names []string
+	for _, line := range body.Ingredients {
+		if line.IngredientId == 0 {
+			names = append(names, importer.NameKeys(line.Name)...)
+		}
+	}
+	ingredientsByKey, err := s.getKnownIngredients(names)
+	if err != nil {
+		return nil, err
+	}
+	recipe := body.ConvertToModel(userId)
+	newIngredients := make(map[*models.Ingredient]bool)
+	for i, line := range recipe.Ingredients {
+		if line.IngredientID != 0 {
+			continue
+		}
+		ingredient := matchImportedIngredient(ingredientsByKey, body.Ingredients[i].Name)
+		// the repository refuses the known ingredients used twice but a new ingredient used twice would be created twice
+		if ingredient.ID == 0 && newIngredients[ingredient] {
+			return nil, repositories.ErrRecipeDuplicatedIngredient
+		}
+		newIngredients[ingredient] = true
+		line.Ingredient = ingredient
+		line.IngredientID = ingredient.ID
+	}
+	recipe, err = s.repo.ImportRecipe(recipe)
+	if err != nil {
+		return nil, err
+	}
+	recipe, err = s.recipeRepo.GetRecipeById(recipe.ID)
+	if err != nil {
+		return nil, err
+	}
+	recipeRes := &dto.RecipeResBody{}
+	recipeRes.ConvertFromModel(recipe)
+	completeRecipeRes(recipeRes, recipe, s.storage)
+	return recipeRes, nil
+}
+
+// convertImportedRecipe builds the recipe model of the imported recipe, each line uses the oldest known ingredient with
+// the same name or a new ingredient. The lines using the same ingredient as a previous line are removed with a warning.
+func (s *importService) convertImportedRecipe(imported *importer.Recipe, query *dto.RecipeImportQuery) (*models.Recipe, error) {
+	var names []string
+	for _, line := range imported.Ingredients {
+		names = append(names, importer.NameKeys(line.Name)...)
+	}
+	ingredientsByKey, err := s.getKnownIngredients(names)
+	if err != nil {
+		return nil, err
+	}
+	recipe := &models.Recipe{
+		Title:       imported.Name,
+		Description: imported.Description,
+		Difficulty:  query.Difficulty,
+		Servings:    imported.Servings,
+	}
+	if recipe.Difficulty == 0 {
+		recipe.Difficulty = defaultImportDifficulty
+	}
+	usedIngredients := make(map[*models.Ingredient]bool, len(imported.Ingredients))
+	var keptLines []*importer.IngredientLine
+	for _, line := range imported.Ingredients {
+		ingredient := matchImportedIngredient(ingredientsByKey, line.Name)
+		if usedIngredients[ingredient] {
+			imported.Warnings = append(imported.Warnings, fmt.Sprintf("the ingredient line %q uses the same ingredient as a previous line, it's ignored", line.Text))
+			continue
+		}
+		usedIngredients[ingredient] = true
+		recipe.Ingredients = append(recipe.Ingredients, &models.RecipeIngredient{
+			IngredientID: ingredient.ID,
+			Ingredient:   ingredient,
+			Position:     uint(len(recipe.Ingredients)),
+			Quantity:     line.Quantity,
+			Unit:         line.Unit,
+			Note:         line.Note,
+			Optional:     line.Optional,
+		})
+		keptLines = append(keptLines, line)
+	}
+	imported.Ingredients = keptLines
+	recipe.Steps = make([]*models.RecipeStep, len(imported.Steps))
+	for i, text := range imported.Steps {
+		recipe.Steps[i] = &models.RecipeStep{Position: uint(i + 1), Text: text}
+	}
+	return recipe, nil
+}
+
+// getKnownIngredients returns the known ingredients having one of the names by the key of their name, the oldest one is kept
+// when several ingredients have the same key
+func (s *importService) getKnownIngredients(names []string) (map[string]*models.Ingredient, error) {
+	known, err := s.repo.GetIngredientsByName(names)
+	if err != nil {
+		return nil, err
+	}
+	ingredientsByKey := make(map[string]*models.Ingredient, len(known))
+	for _, ingredient := range known {
+		key := importer.NameKeys(ingredient.Name)[0]
+		if _, ok := ingredientsByKey[key]; !ok {
+			ingredientsByKey[key] = ingredient
+		}
+	}
+	return ingredientsByKey, nil
+}
+
+// matchImportedIngredient returns the ingredient matching the name, an unknown ingredient is added to the map
+// as a new ingredient so the next lines with the same name use it
+func matchImportedIngredient(ingredientsByKey map[string]*models.Ingredient, name string) *models.Ingredient {
+	ingredient := findImportedIngredient(ingredientsByKey, name)
+	if ingredient == nil {
+		ingredient = &models.Ingredient{Name: strings.TrimSpace(name), Type: importedIngredientType}
+		ingredientsByKey[importer.NameKeys(name)[0]] = ingredient
+	}
+	return ingredient
+}
+
+// findImportedIngredient returns the ingredient matching one of the forms of the name, nil when it's unknown
+func findImportedIngredient(ingredientsByKey map[string]*models.Ingredient, name string) *models.Ingredient {
+	for _, key := range importer.NameKeys(name) {
+		if ingredient, ok := ingredientsByKey[key]; ok {
+			return ingredient
+		}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/importer"
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/repositories"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type mockImportRepository struct {
+	imported *models.Recipe
+}
+
+func (m *mockImportRepository) GetIngredientsByName(names []string) ([]*models.Ingredient, error) {
+	known := []*models.Ingredient{
+		{Model: gorm.Model{ID: 5}, Name: "Cheddar", Type: "cheese"},
+		{Model: gorm.Model{ID: 6}, Name: "egg", Type: "egg"},
+	}
+	var ingredients []*models.Ingredient
+	for _, ingredient := range known {
+		for _, name := range names {
+			if strings.ToLower(ingredient.Name) == name {
+				ingredients = append(ingredients, ingredient)
+				break
+			}
+		}
+	}
+	return ingredients, nil
+}
+
+func (m *mockImportRepository) CountRecipesByTitle(title string) (int64, error) {
+	if title == "welsh rarebit" {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func (m *mockImportRepository) ImportRecipe(recipe *models.Recipe) (*models.Recipe, error) {
+	for i, line := range recipe.Ingredients {
+		if line.IngredientID == 0 {
+			line.Ingredient.ID = uint(10 + i)
+			line.IngredientID = line.Ingredient.ID
+		}
+	}
+	recipe.ID = 1
+	m.imported = recipe
+	return recipe, nil
+}
+
+const importedRecipeJsonLd = `{
+	"@context": "https://schema.org",
+	"@type": "Recipe",
+	"name": "Welsh rarebit",
+	"description": "A cheesy toast",
+	"recipeYield": "2",
+	"recipeIngredient": ["200g cheddar, grated", "2 eggs", "1 tbsp brown ale", "100 g Cheddar"],
+	"recipeInstructions": ["Melt the cheddar in the ale.", "Grill the toasts."]
+}`
+
+func TestImportRecipe(t *testing.T) {
+	repo := &mockImportRepository{}
+	service := services.NewImportService(repo, &mockRecipeRepository{}, &mockStorage{})
+	imported, err := service.ImportRecipe(strings.NewReader(importedRecipeJsonLd), &dto.RecipeImportQuery{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Welsh rarebit", imported.Title)
+	assert.False(t, imported.TitleConflict)
+	assert.Equal(t, uint8(3), imported.Difficulty)
+	assert.Equal(t, uint(2), imported.Servings)
+	assert.Equal(t, []string{"Melt the cheddar in the ale.", "Grill the toasts."}, imported.Steps)
+	assert.Nil(t, repo.imported)
+	// the known ingredients are matched with their singular form and the duplicated line is ignored
+	assert.Len(t, imported.Ingredients, 3)
+	assert.Equal(t, uint(5), imported.Ingredients[0].IngredientId)
+	assert.Equal(t, "Cheddar", imported.Ingredients[0].Name)
+	assert.Equal(t, "grated", imported.Ingredients[0].Note)
+	assert.Equal(t, uint(6), imported.Ingredients[1].IngredientId)
+	assert.False(t, imported.Ingredients[1].New)
+	assert.Equal(t, uint(0), imported.Ingredients[2].IngredientId)
+	assert.True(t, imported.Ingredients[2].New)
+	assert.Equal(t, "brown ale", imported.Ingredients[2].Name)
+	assert.Len(t, imported.Warnings, 1)
+	// test happy path: the difficulty is given with the import
+	imported, err = service.ImportRecipe(strings.NewReader(importedRecipeJsonLd), &dto.RecipeImportQuery{Difficulty: 5})
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(5), imported.Difficulty)
+	// test happy path: the title of another recipe is reported
+	document := strings.Replace(importedRecipeJsonLd, "Welsh rarebit", "welsh rarebit", 1)
+	imported, err = service.ImportRecipe(strings.NewReader(document), &dto.RecipeImportQuery{})
+	assert.NoError(t, err)
+	assert.True(t, imported.TitleConflict)
+	assert.Len(t, imported.Warnings, 2)
+	// test error: the document doesn't contain a recipe
+	_, err = service.ImportRecipe(strings.NewReader(`{"@type": "WebPage"}`), &dto.RecipeImportQuery{})
+	assert.ErrorIs(t, err, importer.ErrRecipeNotFound)
+}
+
+func TestCommitRecipeImport(t *testing.T) {
+	repo := &mockImportRepository{}
+	service := services.NewImportService(repo, &mockRecipeRepository{}, &mockStorage{})
+	body := &dto.RecipeImportCommitReqBody{
+		Title:      "Welsh rarebit with ale",
+		Difficulty: 2,
+		Servings:   2,
+		Ingredients: []dto.ImportedIngredientReqBody{
+			{IngredientId: 5, Name: "Cheddar", Quantity: 200, Unit: "g"},
+			{Name: "eggs", Quantity: 2},
+			{Name: "stout", Quantity: 1, Unit: "tbsp"},
+		},
+		Steps: []string{"Melt the cheddar in the stout."},
+	}
+	// test happy path: the corrected preview is created for the user, the new line uses a known ingredient or a new one
+	recipeRes, err := service.CommitRecipeImport(body, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), recipeRes.ID)
+	assert.Equal(t, uint64(1), repo.imported.AuthorID)
+	assert.Equal(t, "Welsh rarebit with ale", repo.imported.Title)
+	assert.Equal(t, uint8(2), repo.imported.Difficulty)
+	assert.Equal(t, uint(5), repo.imported.Ingredients[0].IngredientID)
+	assert.Equal(t, uint(6), repo.imported.Ingredients[1].IngredientID)
+	assert.Equal(t, uint(12), repo.imported.Ingredients[2].IngredientID)
+	assert.Equal(t, "stout", repo.imported.Ingredients[2].Ingredient.Name)
+	assert.Equal(t, "imported", repo.imported.Ingredients[2].Ingredient.Type)
+	assert.Equal(t, "Melt the cheddar in the stout.", repo.imported.Steps[0].Text)
+	// test error: the title is used by another recipe
+	repo.imported = nil
+	body.Title = "welsh rarebit"
+	_, err = service.CommitRecipeImport(body, 1)
+	assert.ErrorIs(t, err, services.ErrImportTitleConflict)
+	assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+	assert.Nil(t, repo.imported)
+	// test error: the same new ingredient is used on two lines
+	body.Title = "Welsh rarebit with ale"
+	body.Ingredients[1] = dto.ImportedIngredientReqBody{Name: "Stout", Quantity: 2}
+	_, err = service.CommitRecipeImport(body, 1)
+	assert.ErrorIs(t, err, repositories.ErrRecipeDuplicatedIngredient)
+	assert.Nil(t, repo.imported)
+}
@@ -5,6 +5,7 @@ import (
 	"math"
 
 	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/media"
 	"github.com/clementb49/welsh_academy/repositories"
 	"go.uber.org/zap"
 )
@@ -14,20 +15,26 @@ type IngredientService interface {
 	CreateIngredient(input *dto.IngredientReqBody) (*dto.IngredientResBody, error)
 	GetAllIngredients(input *dto.CommonQueryPage) (*dto.CommonPageRespBody, error)
 	GetIngredientById(id uint) (*dto.IngredientResBody, error)
+	UpdateIngredient(id uint, input *dto.IngredientReqBody, userId uint) (*dto.IngredientResBody, error)
 	DeleteIngredientById(id uint) error
 }
 
 // ingredientService is a struct that implements the IngredientService interface
 type ingredientService struct {
-	repo   repositories.IngredientRepository
-	logger *zap.Logger
+	repo     repositories.IngredientRepository
+	userRepo repositories.UserRepository
+	storage  media.Storage
+	logger   *zap.Logger
 }
 
-// NewIngredientService is a function that returns a new instance of IngredientService
-func NewIngredientService(repo repositories.IngredientRepository) IngredientService {
+// NewIngredientService is a function that returns a new instance of IngredientService,
+// the user repository is used to check the user is an administrator
+func NewIngredientService(repo repositories.IngredientRepository, userRepo repositories.UserRepository, storage media.Storage) IngredientService {
 	return &ingredientService{
-		repo:   repo,
-		logger: zap.L(),
+		repo:     repo,
+		userRepo: userRepo,
+		storage:  storage,
+		logger:   zap.L(),
 	}
 }
 
@@ -40,6 +47,7 @@ func (s *ingredientService) CreateIngredient(input *dto.IngredientReqBody) (*dto
 	}
 	ingredientRes := &dto.IngredientResBody{}
 	ingredientRes.ConvertFromModel(ingredientModel)
+	ingredientRes.SetImageUrls(s.storage)
 	return ingredientRes, nil
 }
 
@@ -53,6 +61,7 @@ func (s *ingredientService) GetAllIngredients(input *dto.CommonQueryPage) (*dto.
 	for i, ing := range ingredients {
 		res := dto.IngredientResBody{}
 		res.ConvertFromModel(ing)
+		res.SetImageUrls(s.storage)
 		ingredientsRes[i] = res
 	}
 	totalNbPage := int(math.Ceil(float64(totalIngredient) / float64(input.PageSize)))
@@ -73,14 +82,45 @@ func (s *ingredientService) GetIngredientById(id uint) (*dto.IngredientResBody,
 	}
 	ingredientRes := &dto.IngredientResBody{}
 	ingredientRes.ConvertFromModel(ingredient)
+	ingredientRes.SetImageUrls(s.storage)
 	return ingredientRes, nil
 }
 
-// DeleteIngredientById is a function that delete an ingredients specified by ID from the database
+// UpdateIngredient is a function that replaces the fields of an ingredient specified by ID, only an administrator can
+// update them because the recipes using it show its new allergens, the allergens of a recipe are derived from its ingredients when it's read
+func (s *ingredientService) UpdateIngredient(id uint, input *dto.IngredientReqBody, userId uint) (*dto.IngredientResBody, error) {
+	err := checkAdmin(s.userRepo, userId)
+	if err != nil {
+		return nil, err
+	}
+	ingredient, err := s.repo.GetIngredientById(id)
+	if err != nil {
+		return nil, err
+	}
+	input.ApplyToModel(ingredient)
+	ingredient, err = s.repo.UpdateIngredient(ingredient)
+	if err != nil {
+		return nil, err
+	}
+	ingredientRes := &dto.IngredientResBody{}
+	ingredientRes.ConvertFromModel(ingredient)
+	ingredientRes.SetImageUrls(s.storage)
+	return ingredientRes, nil
+}
+
+// DeleteIngredientById is a function that delete an ingredients specified by ID from the database,
+// the files of its image are removed from the storage
 func (s *ingredientService) DeleteIngredientById(id uint) error {
-	err := s.repo.DeleteIngredientById(id)
+	ingredient, err := s.repo.GetIngredientById(id)
 	if err != nil {
 		return err
 	}
+	err = s.repo.DeleteIngredientById(id)
+	if err != nil {
+		return err
+	}
+	if ingredient.Image != nil {
+		deleteImageFiles(s.storage, s.logger, &ingredient.Image.Image)
+	}
 	return nil
 }
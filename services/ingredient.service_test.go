@@ -51,6 +51,8 @@ func (m *mockIngredientRepository) GetIngredientById(ingredientId uint) (*models
 			},
 			Name: "test",
 			Type: "test_type",
+			Image: &models.IngredientImage{IngredientID: 1,
+				Image: models.Image{Path: "ingredients/1/test.png", ThumbnailPath: "ingredients/1/test_thumb.png"}},
 		}, nil
 	}
 	return nil, gorm.ErrRecordNotFound
@@ -62,9 +64,14 @@ func (m *mockIngredientRepository) DeleteIngredientById(ingredientId uint) error
 	}
 	return gorm.ErrRecordNotFound
 }
+
+func (m *mockIngredientRepository) UpdateIngredient(input *models.Ingredient) (*models.Ingredient, error) {
+	return input, nil
+}
+
 func TestCreateIngredient(t *testing.T) {
 	repo := &mockIngredientRepository{}
-	ingredientService := services.NewIngredientService(repo)
+	ingredientService := services.NewIngredientService(repo, &mockUserRepository{}, &mockStorage{})
 	// test happy path
 	input := dto.IngredientReqBody{
 		Name: "not_exist_ingredient",
@@ -85,7 +92,7 @@ func TestCreateIngredient(t *testing.T) {
 
 func TestGetIngredientById(t *testing.T) {
 	repo := &mockIngredientRepository{}
-	ingredientService := services.NewIngredientService(repo)
+	ingredientService := services.NewIngredientService(repo, &mockUserRepository{}, &mockStorage{})
 
 	// test happy path
 	ingredienRes, err := ingredientService.GetIngredientById(1)
@@ -99,12 +106,38 @@ func TestGetIngredientById(t *testing.T) {
 	assert.Nil(t, ingredienRes)
 }
 
+func TestUpdateIngredient(t *testing.T) {
+	repo := &mockIngredientRepository{}
+	ingredientService := services.NewIngredientService(repo, &mockUserRepository{}, &mockStorage{})
+	// test happy path: an administrator updates the ingredient
+	input := dto.IngredientReqBody{
+		Name:      "caerphilly",
+		Type:      "cheese",
+		Allergens: []string{"milk", "gluten"},
+	}
+	ingredientRes, err := ingredientService.UpdateIngredient(1, &input, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), ingredientRes.ID)
+	assert.Equal(t, "caerphilly", ingredientRes.Name)
+	assert.Equal(t, []string{"gluten", "milk"}, ingredientRes.Allergens)
+	// test error: the user isn't an administrator
+	ingredientRes, err = ingredientService.UpdateIngredient(1, &input, 1)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	assert.Nil(t, ingredientRes)
+	// test: record not found error
+	ingredientRes, err = ingredientService.UpdateIngredient(2, &input, 3)
+	assert.ErrorAs(t, err, &gorm.ErrRecordNotFound)
+	assert.Nil(t, ingredientRes)
+}
+
 func TestDeleteIngrdientById(t *testing.T) {
 	repo := &mockIngredientRepository{}
-	ingredientService := services.NewIngredientService(repo)
-	// test happy path
+	storage := &mockStorage{files: map[string][]byte{"ingredients/1/test.png": {}, "ingredients/1/test_thumb.png": {}}}
+	ingredientService := services.NewIngredientService(repo, &mockUserRepository{}, storage)
+	// test happy path: the files of the image are removed
 	err := ingredientService.DeleteIngredientById(1)
 	assert.NoError(t, err)
+	assert.Empty(t, storage.files)
 	// test record not found
 	err = ingredientService.DeleteIngredientById(2)
 	assert.ErrorAs(t, err, &gorm.ErrRecordNotFound)
@@ -112,7 +145,7 @@ func TestDeleteIngrdientById(t *testing.T) {
 
 func TestGetAllIngredients(t *testing.T) {
 	repo := &mockIngredientRepository{}
-	ingredientService := services.NewIngredientService(repo)
+	ingredientService := services.NewIngredientService(repo, &mockUserRepository{}, &mockStorage{})
 	// test happy path
 	input := &dto.CommonQueryPage{
 		PageSize:   10,
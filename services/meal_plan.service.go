@@ -0,0 +1,231 @@
+// The package 'services' contains the business logic for handling route
+package services
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/repositories"
+	"go.uber.org/zap"
+)
+
+// ErrMealPlanPeriodNotAcceptable is returned when the end of the period is before its start or when the period is too long
+var ErrMealPlanPeriodNotAcceptable = errors.New("the period must end after its start and last at most a year, period not acceptable")
+
+// The number of days of the default period and of the longest period of meal plans
+const (
+	mealPlanDefaultDays = 7
+	mealPlanMaxDays     = 366
+)
+
+// The period of the meal plans exported in the calendar feed, relative to the current day
+const (
+	calendarPastDays   = 28
+	calendarFutureDays = 365
+)
+
+// MealPlanService is an interface for defining the methods to manage the meal plans of the users and their calendar feed
+type MealPlanService interface {
+	CreateMealPlan(body *dto.MealPlanReqBody, userId uint) (*dto.MealPlanResBody, error)
+	GetAllMealPlans(query *dto.MealPlanQuery, userId uint) ([]*dto.MealPlanResBody, error)
+	GetMealPlanById(input *dto.CommonIdPathUri, userId uint) (*dto.MealPlanResBody, error)
+	UpdateMealPlan(input *dto.CommonIdPathUri, body *dto.MealPlanReqBody, userId uint) (*dto.MealPlanResBody, error)
+	DeleteMealPlanById(input *dto.CommonIdPathUri, userId uint) error
+	GetCalendarToken(userId uint) (*dto.MealPlanCalendarResBody, error)
+	RegenerateCalendarToken(userId uint) (*dto.MealPlanCalendarResBody, error)
+	ExportCalendar(input *dto.CalendarTokenPathUri) (string, error)
+}
+
+// mealPlanService is an implementation of the MealPlanService interface
+type mealPlanService struct {
+	repo       repositories.MealPlanRepository
+	recipeRepo repositories.RecipeRepository
+	userRepo   repositories.UserRepository
+	location   *time.Location
+	logger     *zap.Logger
+}
+
+// NewMealPlanService creates a new MealPlanService instance, the dates of the meal plans are days of the location
+func NewMealPlanService(repo repositories.MealPlanRepository, recipeRepo repositories.RecipeRepository, userRepo repositories.UserRepository, location *time.Location) MealPlanService {
+	return &mealPlanService{
+		repo:       repo,
+		recipeRepo: recipeRepo,
+		userRepo:   userRepo,
+		location:   location,
+		logger:     zap.L(),
+	}
+}
+
+// CreateMealPlan plans a recipe for a meal of the user
+func (s *mealPlanService) CreateMealPlan(body *dto.MealPlanReqBody, userId uint) (*dto.MealPlanResBody, error) {
+	_, err := s.recipeRepo.GetRecipeById(body.RecipeId)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := body.ConvertToModel(userId, s.location)
+	if err != nil {
+		return nil, err
+	}
+	plan, err = s.repo.CreateMealPlan(plan)
+	if err != nil {
+		return nil, err
+	}
+	planRes := &dto.MealPlanResBody{}
+	planRes.ConvertFromModel(plan)
+	return planRes, nil
+}
+
+// GetAllMealPlans returns the meal plans of the user during the period ordered by date and meal slot
+func (s *mealPlanService) GetAllMealPlans(query *dto.MealPlanQuery, userId uint) ([]*dto.MealPlanResBody, error) {
+	from, to, err := s.mealPlanPeriod(query)
+	if err != nil {
+		return nil, err
+	}
+	plans, err := s.repo.GetAllMealPlans(userId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	sortMealPlans(plans)
+	plansRes := make([]*dto.MealPlanResBody, len(plans))
+	for i, v := range plans {
+		res := &dto.MealPlanResBody{}
+		res.ConvertFromModel(v)
+		plansRes[i] = res
+	}
+	return plansRes, nil
+}
+
+// GetMealPlanById returns a meal plan of the user by ID
+func (s *mealPlanService) GetMealPlanById(input *dto.CommonIdPathUri, userId uint) (*dto.MealPlanResBody, error) {
+	plan, err := s.repo.GetMealPlanById(userId, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	planRes := &dto.MealPlanResBody{}
+	planRes.ConvertFromModel(plan)
+	return planRes, nil
+}
+
+// UpdateMealPlan replaces the recipe, the date, the slot, the servings and the note of a meal plan of the user
+func (s *mealPlanService) UpdateMealPlan(input *dto.CommonIdPathUri, body *dto.MealPlanReqBody, userId uint) (*dto.MealPlanResBody, error) {
+	plan, err := s.repo.GetMealPlanById(userId, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	if body.RecipeId != plan.RecipeID {
+		_, err = s.recipeRepo.GetRecipeById(body.RecipeId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = body.ApplyToModel(plan, s.location)
+	if err != nil {
+		return nil, err
+	}
+	plan, err = s.repo.UpdateMealPlan(plan)
+	if err != nil {
+		return nil, err
+	}
+	planRes := &dto.MealPlanResBody{}
+	planRes.ConvertFromModel(plan)
+	return planRes, nil
+}
+
+// DeleteMealPlanById deletes a meal plan of the user by ID
+func (s *mealPlanService) DeleteMealPlanById(input *dto.CommonIdPathUri, userId uint) error {
+	return s.repo.DeleteMealPlanById(userId, input.ID)
+}
+
+// GetCalendarToken returns the secret token of the calendar feed of the user, it's generated on the first call
+func (s *mealPlanService) GetCalendarToken(userId uint) (*dto.MealPlanCalendarResBody, error) {
+	user, err := s.userRepo.GetUserById(userId)
+	if err != nil {
+		return nil, err
+	}
+	if user.CalendarToken == nil {
+		return s.RegenerateCalendarToken(userId)
+	}
+	return &dto.MealPlanCalendarResBody{Token: *user.CalendarToken}, nil
+}
+
+// RegenerateCalendarToken replaces the secret token of the calendar feed of the user, the previous feed URL stops working
+func (s *mealPlanService) RegenerateCalendarToken(userId uint) (*dto.MealPlanCalendarResBody, error) {
+	token, err := newSecretToken()
+	if err != nil {
+		return nil, err
+	}
+	err = s.userRepo.SetCalendarToken(userId, token)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.MealPlanCalendarResBody{Token: token}, nil
+}
+
+// ExportCalendar returns the recent and coming meal plans of the user owning the token as an iCalendar file
+func (s *mealPlanService) ExportCalendar(input *dto.CalendarTokenPathUri) (string, error) {
+	user, err := s.userRepo.GetUserByCalendarToken(input.Token)
+	if err != nil {
+		return "", err
+	}
+	today := s.today()
+	plans, err := s.repo.GetAllMealPlans(user.ID, today.AddDate(0, 0, -calendarPastDays), today.AddDate(0, 0, calendarFutureDays))
+	if err != nil {
+		return "", err
+	}
+	sortMealPlans(plans)
+	return exportCalendar(plans, s.location), nil
+}
+
+// today returns the current day in the location of the meal plans
+func (s *mealPlanService) today() time.Time {
+	now := time.Now().In(s.location)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, s.location)
+}
+
+// mealPlanPeriod returns the first and the last days of the period of the query, a week is used when a date is omitted
+func (s *mealPlanService) mealPlanPeriod(query *dto.MealPlanQuery) (time.Time, time.Time, error) {
+	var from, to time.Time
+	var err error
+	if query.From != "" {
+		from, err = time.ParseInLocation(models.DateFormat, query.From, s.location)
+		if err != nil {
+			return from, to, err
+		}
+	}
+	if query.To != "" {
+		to, err = time.ParseInLocation(models.DateFormat, query.To, s.location)
+		if err != nil {
+			return from, to, err
+		}
+	}
+	switch {
+	case from.IsZero() && to.IsZero():
+		from = s.today()
+		to = from.AddDate(0, 0, mealPlanDefaultDays-1)
+	case from.IsZero():
+		from = to.AddDate(0, 0, 1-mealPlanDefaultDays)
+	case to.IsZero():
+		to = from.AddDate(0, 0, mealPlanDefaultDays-1)
+	}
+	if to.Before(from) || to.After(from.AddDate(0, 0, mealPlanMaxDays-1)) {
+		return from, to, ErrMealPlanPeriodNotAcceptable
+	}
+	return from, to, nil
+}
+
+// sortMealPlans sorts the meal plans by date then by meal slot, the plans of the same meal keep their order
+func sortMealPlans(plans []*models.MealPlan) {
+	slotRanks := make(map[string]int, len(models.MealSlots))
+	for i, slot := range models.MealSlots {
+		slotRanks[slot] = i
+	}
+	sort.SliceStable(plans, func(i, j int) bool {
+		if !plans[i].Date.Equal(plans[j].Date) {
+			return plans[i].Date.Before(plans[j].Date)
+		}
+		return slotRanks[plans[i].Slot] < slotRanks[plans[j].Slot]
+	})
+}
@@ -0,0 +1,124 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+const calendarToken = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+type mockMealPlanRepository struct{}
+
+func (m *mockMealPlanRepository) CreateMealPlan(plan *models.MealPlan) (*models.MealPlan, error) {
+	plan.ID = 1
+	plan.Recipe = &models.Recipe{Model: gorm.Model{ID: plan.RecipeID}, Title: "welsh rarebit", Servings: 4}
+	return plan, nil
+}
+
+func (m *mockMealPlanRepository) GetAllMealPlans(userId uint, from, to time.Time) ([]*models.MealPlan, error) {
+	recipe := &models.Recipe{Model: gorm.Model{ID: 1}, Title: "welsh rarebit, with ale", Servings: 4}
+	date := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+	return []*models.MealPlan{
+		{Model: gorm.Model{ID: 2}, UserID: userId, RecipeID: 1, Recipe: recipe, Date: date, Slot: models.MealSlotDinner, Servings: 8, Note: "party"},
+		{Model: gorm.Model{ID: 3}, UserID: userId, RecipeID: 1, Recipe: recipe, Date: date.AddDate(0, 0, -1), Slot: models.MealSlotLunch},
+		{Model: gorm.Model{ID: 1}, UserID: userId, RecipeID: 1, Recipe: recipe, Date: date, Slot: models.MealSlotBreakfast},
+	}, nil
+}
+
+func (m *mockMealPlanRepository) GetMealPlanById(userId, planId uint) (*models.MealPlan, error) {
+	if userId == 1 && planId == 1 {
+		return &models.MealPlan{
+			Model:    gorm.Model{ID: 1},
+			UserID:   1,
+			RecipeID: 1,
+			Recipe:   &models.Recipe{Model: gorm.Model{ID: 1}, Title: "welsh rarebit", Servings: 4},
+			Date:     time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC),
+			Slot:     models.MealSlotDinner,
+		}, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockMealPlanRepository) UpdateMealPlan(plan *models.MealPlan) (*models.MealPlan, error) {
+	return plan, nil
+}
+
+func (m *mockMealPlanRepository) DeleteMealPlanById(userId, planId uint) error {
+	_, err := m.GetMealPlanById(userId, planId)
+	return err
+}
+
+func TestCreateMealPlan(t *testing.T) {
+	mealPlanService := services.NewMealPlanService(&mockMealPlanRepository{}, &mockRecipeRepository{}, &mockUserRepository{}, time.UTC)
+	body := &dto.MealPlanReqBody{RecipeId: 1, Date: "2023-07-01", Slot: models.MealSlotLunch}
+	planRes, err := mealPlanService.CreateMealPlan(body, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "2023-07-01", planRes.Date)
+	assert.Equal(t, "welsh rarebit", planRes.RecipeTitle)
+	// the servings of the recipe are used by default
+	assert.Equal(t, uint(4), planRes.Servings)
+	// test error: recipe not found
+	body.RecipeId = 2
+	planRes, err = mealPlanService.CreateMealPlan(body, 1)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, planRes)
+}
+
+func TestGetAllMealPlans(t *testing.T) {
+	mealPlanService := services.NewMealPlanService(&mockMealPlanRepository{}, &mockRecipeRepository{}, &mockUserRepository{}, time.UTC)
+	// test happy path: the plans are ordered by date and meal slot
+	plansRes, err := mealPlanService.GetAllMealPlans(&dto.MealPlanQuery{From: "2023-06-26"}, 1)
+	assert.NoError(t, err)
+	assert.Len(t, plansRes, 3)
+	assert.Equal(t, uint(3), plansRes[0].ID)
+	assert.Equal(t, uint(1), plansRes[1].ID)
+	assert.Equal(t, uint(2), plansRes[2].ID)
+	// test error: the period ends before its start
+	_, err = mealPlanService.GetAllMealPlans(&dto.MealPlanQuery{From: "2023-07-01", To: "2023-06-30"}, 1)
+	assert.ErrorIs(t, err, services.ErrMealPlanPeriodNotAcceptable)
+	// test error: the period is longer than a year
+	_, err = mealPlanService.GetAllMealPlans(&dto.MealPlanQuery{From: "2023-01-01", To: "2024-01-02"}, 1)
+	assert.ErrorIs(t, err, services.ErrMealPlanPeriodNotAcceptable)
+}
+
+func TestUpdateMealPlan(t *testing.T) {
+	mealPlanService := services.NewMealPlanService(&mockMealPlanRepository{}, &mockRecipeRepository{}, &mockUserRepository{}, time.UTC)
+	body := &dto.MealPlanReqBody{RecipeId: 1, Date: "2023-07-02", Slot: models.MealSlotLunch, Servings: 2}
+	planRes, err := mealPlanService.UpdateMealPlan(&dto.CommonIdPathUri{ID: 1}, body, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "2023-07-02", planRes.Date)
+	assert.Equal(t, uint(2), planRes.Servings)
+	// test error: the plan belongs to another user
+	_, err = mealPlanService.UpdateMealPlan(&dto.CommonIdPathUri{ID: 1}, body, 3)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestCalendar(t *testing.T) {
+	location, err := time.LoadLocation("Europe/London")
+	assert.NoError(t, err)
+	mealPlanService := services.NewMealPlanService(&mockMealPlanRepository{}, &mockRecipeRepository{}, &mockUserRepository{}, location)
+	// test happy path: a token is generated for the user
+	calendarRes, err := mealPlanService.GetCalendarToken(1)
+	assert.NoError(t, err)
+	assert.Len(t, calendarRes.Token, 64)
+	// test happy path: the meal plans are exported in UTC
+	calendar, err := mealPlanService.ExportCalendar(&dto.CalendarTokenPathUri{Token: calendarToken})
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(calendar, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(calendar, "END:VEVENT\r\nEND:VCALENDAR\r\n"))
+	assert.Equal(t, 3, strings.Count(calendar, "BEGIN:VEVENT"))
+	assert.Contains(t, calendar, "UID:mealplan-2@welsh-academy\r\n")
+	assert.Contains(t, calendar, "DTSTART:20230701T180000Z\r\nDTEND:20230701T190000Z\r\n")
+	assert.Contains(t, calendar, "SUMMARY:Dinner: welsh rarebit\\, with ale\r\n")
+	assert.Contains(t, calendar, "DESCRIPTION:8 servings\\nparty\r\n")
+	// test error: unknown token
+	_, err = mealPlanService.ExportCalendar(&dto.CalendarTokenPathUri{Token: strings.Repeat("f", 64)})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
@@ -0,0 +1,78 @@
+// The package 'services' contains the business logic for handling route
+package services
+
+import (
+	"math"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/units"
+)
+
+// recipeNutrition computes the nutrition facts of the recipe from its ingredient lines, the optional ingredients
+// and the quantities to taste are ignored. The quantities are weighed with the density of the ingredient for the volumes
+// and with its piece weight for the counted quantities.
+func recipeNutrition(model *models.Recipe) *dto.RecipeNutritionResBody {
+	total := &dto.NutritionResBody{}
+	nutrition := &dto.RecipeNutritionResBody{MissingIngredients: make([]*dto.IngredientLinkResBody, 0)}
+	for _, line := range model.Ingredients {
+		if line.Optional || line.Quantity == 0 || line.Ingredient == nil {
+			continue
+		}
+		weight, ok := ingredientWeight(line)
+		if !ok || line.Ingredient.Nutrition == nil {
+			nutrition.MissingIngredients = append(nutrition.MissingIngredients, &dto.IngredientLinkResBody{ID: line.IngredientID, Name: line.Ingredient.Name})
+			continue
+		}
+		addNutrition(total, line.Ingredient.Nutrition, weight)
+	}
+	nutrition.Complete = len(nutrition.MissingIngredients) == 0
+	nutrition.Total = scaleNutrition(total, 1)
+	servings := float64(model.Servings)
+	if servings == 0 {
+		servings = 1
+	}
+	nutrition.PerServing = scaleNutrition(total, 1/servings)
+	return nutrition
+}
+
+// addNutrition adds to the total the nutrition facts for 100 g multiplied by the weight in g
+func addNutrition(total *dto.NutritionResBody, model *models.IngredientNutrition, weight float64) {
+	factor := weight / 100
+	total.EnergyKcal += model.EnergyKcal * factor
+	total.Fat += model.Fat * factor
+	total.Saturates += model.Saturates * factor
+	total.Carbohydrate += model.Carbohydrate * factor
+	total.Sugars += model.Sugars * factor
+	total.Protein += model.Protein * factor
+	total.Salt += model.Salt * factor
+}
+
+// scaleNutrition returns the nutrition facts multiplied by the factor and rounded to one decimal
+func scaleNutrition(nutrition *dto.NutritionResBody, factor float64) *dto.NutritionResBody {
+	round := func(value float64) float64 {
+		return math.Round(value*factor*10) / 10
+	}
+	return &dto.NutritionResBody{
+		EnergyKcal:   round(nutrition.EnergyKcal),
+		Fat:          round(nutrition.Fat),
+		Saturates:    round(nutrition.Saturates),
+		Carbohydrate: round(nutrition.Carbohydrate),
+		Sugars:       round(nutrition.Sugars),
+		Protein:      round(nutrition.Protein),
+		Salt:         round(nutrition.Salt),
+	}
+}
+
+// ingredientWeight returns the weight in g of the quantity of the ingredient line, it returns false when it can't be weighed
+func ingredientWeight(line *models.RecipeIngredient) (float64, bool) {
+	unit, ok := units.Lookup(line.Unit)
+	if !ok {
+		return 0, false
+	}
+	if unit.Kind == units.Count {
+		return line.Quantity * line.Ingredient.PieceWeight, line.Ingredient.PieceWeight > 0
+	}
+	weight, err := units.Convert(line.Quantity, unit.Symbol, "g", line.Ingredient.Density)
+	return weight, err == nil
+}
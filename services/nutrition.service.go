@@ -0,0 +1,66 @@
+// The package 'services' contains the business logic for handling route
+package services
+
+import (
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/media"
+	"github.com/clementb49/welsh_academy/repositories"
+	"go.uber.org/zap"
+)
+
+// NutritionService is an interface for defining the methods to maintain the nutrition facts of the ingredients
+type NutritionService interface {
+	SaveIngredientNutrition(input *dto.CommonIdPathUri, body *dto.NutritionReqBody, userId uint) (*dto.IngredientResBody, error)
+	DeleteIngredientNutrition(input *dto.CommonIdPathUri, userId uint) error
+}
+
+// nutritionService is an implementation of the NutritionService interface
+type nutritionService struct {
+	repo           repositories.NutritionRepository
+	ingredientRepo repositories.IngredientRepository
+	userRepo       repositories.UserRepository
+	storage        media.Storage
+	logger         *zap.Logger
+}
+
+// NewNutritionService creates a new NutritionService instance, the user repository is used to check the user is an administrator
+func NewNutritionService(repo repositories.NutritionRepository, ingredientRepo repositories.IngredientRepository, userRepo repositories.UserRepository, storage media.Storage) NutritionService {
+	return &nutritionService{
+		repo:           repo,
+		ingredientRepo: ingredientRepo,
+		userRepo:       userRepo,
+		storage:        storage,
+		logger:         zap.L(),
+	}
+}
+
+// SaveIngredientNutrition sets the nutrition facts for 100 g of the ingredient, only an administrator can maintain them.
+// The ingredient is returned with its new nutrition facts.
+func (s *nutritionService) SaveIngredientNutrition(input *dto.CommonIdPathUri, body *dto.NutritionReqBody, userId uint) (*dto.IngredientResBody, error) {
+	err := checkAdmin(s.userRepo, userId)
+	if err != nil {
+		return nil, err
+	}
+	ingredient, err := s.ingredientRepo.GetIngredientById(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	nutrition, err := s.repo.SaveIngredientNutrition(body.ConvertToModel(ingredient.ID))
+	if err != nil {
+		return nil, err
+	}
+	ingredient.Nutrition = nutrition
+	ingredientRes := &dto.IngredientResBody{}
+	ingredientRes.ConvertFromModel(ingredient)
+	ingredientRes.SetImageUrls(s.storage)
+	return ingredientRes, nil
+}
+
+// DeleteIngredientNutrition removes the nutrition facts of the ingredient, only an administrator can maintain them
+func (s *nutritionService) DeleteIngredientNutrition(input *dto.CommonIdPathUri, userId uint) error {
+	err := checkAdmin(s.userRepo, userId)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteIngredientNutrition(input.ID)
+}
@@ -0,0 +1,56 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type mockNutritionRepository struct{}
+
+func (m *mockNutritionRepository) SaveIngredientNutrition(nutrition *models.IngredientNutrition) (*models.IngredientNutrition, error) {
+	return nutrition, nil
+}
+
+func (m *mockNutritionRepository) DeleteIngredientNutrition(ingredientId uint) error {
+	if ingredientId == 1 {
+		return nil
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func TestSaveIngredientNutrition(t *testing.T) {
+	nutritionService := services.NewNutritionService(&mockNutritionRepository{}, &mockIngredientRepository{}, &mockUserRepository{}, &mockStorage{})
+	body := &dto.NutritionReqBody{EnergyKcal: 416, Fat: 34.9, Saturates: 21.7, Protein: 25.4, Salt: 1.8}
+	// test happy path: an administrator sets the nutrition facts
+	ingredientRes, err := nutritionService.SaveIngredientNutrition(&dto.CommonIdPathUri{ID: 1}, body, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), ingredientRes.ID)
+	assert.Equal(t, 416.0, ingredientRes.Nutrition.EnergyKcal)
+	assert.Equal(t, 1.8, ingredientRes.Nutrition.Salt)
+	// test error: the user isn't an administrator
+	ingredientRes, err = nutritionService.SaveIngredientNutrition(&dto.CommonIdPathUri{ID: 1}, body, 1)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	assert.Nil(t, ingredientRes)
+	// test error: ingredient not found
+	ingredientRes, err = nutritionService.SaveIngredientNutrition(&dto.CommonIdPathUri{ID: 2}, body, 3)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, ingredientRes)
+}
+
+func TestDeleteIngredientNutrition(t *testing.T) {
+	nutritionService := services.NewNutritionService(&mockNutritionRepository{}, &mockIngredientRepository{}, &mockUserRepository{}, &mockStorage{})
+	// test happy path
+	err := nutritionService.DeleteIngredientNutrition(&dto.CommonIdPathUri{ID: 1}, 3)
+	assert.NoError(t, err)
+	// test error: the user isn't an administrator
+	err = nutritionService.DeleteIngredientNutrition(&dto.CommonIdPathUri{ID: 1}, 1)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	// test error: the ingredient has no nutrition facts
+	err = nutritionService.DeleteIngredientNutrition(&dto.CommonIdPathUri{ID: 2}, 3)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
@@ -0,0 +1,110 @@
+// The package 'services' contains the business logic for handling route
+package services
+
+import (
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/media"
+	"github.com/clementb49/welsh_academy/repositories"
+	"go.uber.org/zap"
+)
+
+// PantryService is an interface for defining the methods to manage the pantry of the users and to find what they can cook
+type PantryService interface {
+	GetAllPantryIngredients(query *dto.CommonQueryPage, userId uint) (*dto.CommonPageRespBody, error)
+	AddIngredientToPantry(input *dto.CommonIdPathUri, userId uint) (*dto.IngredientResBody, error)
+	DeleteIngredientFromPantry(input *dto.CommonIdPathUri, userId uint) error
+	ReplacePantry(body *dto.PantryReqBody, userId uint) error
+	GetCookableRecipes(query *dto.CookableQuery, userId uint) (*dto.CommonPageRespBody, error)
+}
+
+// pantryService is an implementation of the PantryService interface
+type pantryService struct {
+	repo           repositories.PantryRepository
+	ingredientRepo repositories.IngredientRepository
+	storage        media.Storage
+	logger         *zap.Logger
+}
+
+// NewPantryService creates a new PantryService instance, the ingredient repository is used to check the added ingredients exist
+func NewPantryService(repo repositories.PantryRepository, ingredientRepo repositories.IngredientRepository, storage media.Storage) PantryService {
+	return &pantryService{
+		repo:           repo,
+		ingredientRepo: ingredientRepo,
+		storage:        storage,
+		logger:         zap.L(),
+	}
+}
+
+// GetAllPantryIngredients returns a page of the ingredients in the pantry of the user
+func (s *pantryService) GetAllPantryIngredients(query *dto.CommonQueryPage, userId uint) (*dto.CommonPageRespBody, error) {
+	ingredients, totalIngredients, err := s.repo.GetAllPantryIngredients(userId, query.PageSize, query.PageNumber)
+	if err != nil {
+		return nil, err
+	}
+	ingredientsRes := make([]interface{}, len(ingredients))
+	for i, v := range ingredients {
+		res := dto.IngredientResBody{}
+		res.ConvertFromModel(v)
+		res.SetImageUrls(s.storage)
+		ingredientsRes[i] = res
+	}
+	return newPageRespBody(query, totalIngredients, ingredientsRes), nil
+}
+
+// AddIngredientToPantry adds the ingredient to the pantry of the user, the ingredient is returned
+func (s *pantryService) AddIngredientToPantry(input *dto.CommonIdPathUri, userId uint) (*dto.IngredientResBody, error) {
+	ingredient, err := s.ingredientRepo.GetIngredientById(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.AddIngredientToPantry(userId, ingredient.ID)
+	if err != nil {
+		return nil, err
+	}
+	ingredientRes := &dto.IngredientResBody{}
+	ingredientRes.ConvertFromModel(ingredient)
+	ingredientRes.SetImageUrls(s.storage)
+	return ingredientRes, nil
+}
+
+// DeleteIngredientFromPantry removes the ingredient from the pantry of the user
+func (s *pantryService) DeleteIngredientFromPantry(input *dto.CommonIdPathUri, userId uint) error {
+	return s.repo.DeleteIngredientFromPantry(userId, input.ID)
+}
+
+// ReplacePantry replaces the content of the pantry of the user, the ingredients listed twice are added once
+func (s *pantryService) ReplacePantry(body *dto.PantryReqBody, userId uint) error {
+	ingredientsId := make([]uint, 0, len(body.IngredientIds))
+	seen := make(map[uint]bool, len(body.IngredientIds))
+	for _, id := range body.IngredientIds {
+		if !seen[id] {
+			seen[id] = true
+			ingredientsId = append(ingredientsId, id)
+		}
+	}
+	return s.repo.ReplacePantry(userId, ingredientsId)
+}
+
+// GetCookableRecipes returns a page of the recipes the user can cook with their pantry, the recipes missing the fewest ingredients first
+func (s *pantryService) GetCookableRecipes(query *dto.CookableQuery, userId uint) (*dto.CommonPageRespBody, error) {
+	filter := &repositories.CookableFilter{MaxMissing: query.MaxMissing, AllowSubstitutes: query.AllowSubstitutes}
+	results, totalRecipes, err := s.repo.GetCookableRecipes(userId, filter, query.PageSize, query.PageNumber)
+	if err != nil {
+		return nil, err
+	}
+	recipesRes := make([]interface{}, len(results))
+	for i, v := range results {
+		res := dto.CookableRecipeResBody{
+			IngredientCount:    v.IngredientCount,
+			AvailableCount:     v.AvailableCount,
+			MissingIngredients: make([]*dto.IngredientLinkResBody, len(v.MissingIngredients)),
+		}
+		res.ConvertFromModel(v.Recipe)
+		completeRecipeRes(&res.RecipeResBody, v.Recipe, s.storage)
+		for j, ingredient := range v.MissingIngredients {
+			res.MissingIngredients[j] = &dto.IngredientLinkResBody{ID: ingredient.ID, Name: ingredient.Name}
+		}
+		recipesRes[i] = res
+	}
+	return newPageRespBody(&query.CommonQueryPage, totalRecipes, recipesRes), nil
+}
@@ -0,0 +1,144 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/repositories"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type mockPantryRepository struct {
+	pantry []uint
+}
+
+func (m *mockPantryRepository) GetAllPantryIngredients(userId uint, pageSize, pageNumber int) ([]*models.Ingredient, int64, error) {
+	// the pantry is joined with the user so an unknown user has an empty pantry
+	if userId != 1 {
+		return []*models.Ingredient{}, 0, nil
+	}
+	return []*models.Ingredient{{
+		Model: gorm.Model{ID: 1},
+		Name:  "cheddar",
+		Type:  "cheese",
+		Image: &models.IngredientImage{IngredientID: 1,
+			Image: models.Image{Path: "ingredients/1/cheddar.png", ThumbnailPath: "ingredients/1/cheddar_thumb.png"}},
+	}}, 1, nil
+}
+
+func (m *mockPantryRepository) AddIngredientToPantry(userId, ingredientId uint) error {
+	m.pantry = append(m.pantry, ingredientId)
+	return nil
+}
+
+func (m *mockPantryRepository) DeleteIngredientFromPantry(userId, ingredientId uint) error {
+	if ingredientId == 1 {
+		return nil
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (m *mockPantryRepository) ReplacePantry(userId uint, ingredientsId []uint) error {
+	for _, id := range ingredientsId {
+		if id > 3 {
+			return repositories.ErrPantryIngredientNotAcceptable
+		}
+	}
+	m.pantry = ingredientsId
+	return nil
+}
+
+func (m *mockPantryRepository) GetCookableRecipes(userId uint, filter *repositories.CookableFilter, pageSize, pageNumber int) ([]*repositories.CookableRecipe, int64, error) {
+	recipe, _ := (&mockRecipeRepository{}).GetRecipeById(1)
+	if filter.MaxMissing != nil && *filter.MaxMissing < 1 {
+		return []*repositories.CookableRecipe{}, 0, nil
+	}
+	return []*repositories.CookableRecipe{{
+		Recipe:             recipe,
+		IngredientCount:    3,
+		AvailableCount:     2,
+		MissingIngredients: []*models.Ingredient{recipe.Ingredients[2].Ingredient},
+	}}, 1, nil
+}
+
+func TestGetAllPantryIngredients(t *testing.T) {
+	pantryService := services.NewPantryService(&mockPantryRepository{}, &mockIngredientRepository{}, &mockStorage{})
+	query := &dto.CommonQueryPage{PageSize: 10}
+	// test happy path: the ingredients of the pantry are listed with their image
+	pageRes, err := pantryService.GetAllPantryIngredients(query, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, pageRes.TotalNbResult)
+	ingredientRes := pageRes.Items[0].(dto.IngredientResBody)
+	assert.Equal(t, "cheddar", ingredientRes.Name)
+	assert.Equal(t, "https://cdn.example.com/ingredients/1/cheddar.png", ingredientRes.Image.Url)
+	// test user not found: the pantry is empty
+	pageRes, err = pantryService.GetAllPantryIngredients(query, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, pageRes.TotalNbResult)
+	assert.Empty(t, pageRes.Items)
+}
+
+func TestAddIngredientToPantry(t *testing.T) {
+	repo := &mockPantryRepository{}
+	pantryService := services.NewPantryService(repo, &mockIngredientRepository{}, &mockStorage{})
+	// test happy path
+	ingredientRes, err := pantryService.AddIngredientToPantry(&dto.CommonIdPathUri{ID: 1}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), ingredientRes.ID)
+	assert.Equal(t, []uint{1}, repo.pantry)
+	// test error: ingredient not found
+	ingredientRes, err = pantryService.AddIngredientToPantry(&dto.CommonIdPathUri{ID: 2}, 1)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, ingredientRes)
+}
+
+func TestDeleteIngredientFromPantry(t *testing.T) {
+	pantryService := services.NewPantryService(&mockPantryRepository{}, &mockIngredientRepository{}, &mockStorage{})
+	// test happy path
+	err := pantryService.DeleteIngredientFromPantry(&dto.CommonIdPathUri{ID: 1}, 1)
+	assert.NoError(t, err)
+	// test error: the ingredient isn't in the pantry
+	err = pantryService.DeleteIngredientFromPantry(&dto.CommonIdPathUri{ID: 2}, 1)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestReplacePantry(t *testing.T) {
+	repo := &mockPantryRepository{}
+	pantryService := services.NewPantryService(repo, &mockIngredientRepository{}, &mockStorage{})
+	// test happy path: the ingredients listed twice are added once
+	err := pantryService.ReplacePantry(&dto.PantryReqBody{IngredientIds: []uint{2, 1, 2}}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{2, 1}, repo.pantry)
+	// test happy path: an ingredient listed twice is the only one of the pantry
+	err = pantryService.ReplacePantry(&dto.PantryReqBody{IngredientIds: []uint{1, 1}}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{1}, repo.pantry)
+	// test happy path: the pantry is cleared
+	err = pantryService.ReplacePantry(&dto.PantryReqBody{}, 1)
+	assert.NoError(t, err)
+	assert.Empty(t, repo.pantry)
+	// test error: unknown ingredient
+	err = pantryService.ReplacePantry(&dto.PantryReqBody{IngredientIds: []uint{1, 4}}, 1)
+	assert.ErrorIs(t, err, repositories.ErrPantryIngredientNotAcceptable)
+}
+
+func TestGetCookableRecipes(t *testing.T) {
+	pantryService := services.NewPantryService(&mockPantryRepository{}, &mockIngredientRepository{}, &mockStorage{})
+	// test happy path: the missing ingredients are listed
+	pageRes, err := pantryService.GetCookableRecipes(&dto.CookableQuery{CommonQueryPage: dto.CommonQueryPage{PageSize: 10}}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, pageRes.TotalNbResult)
+	recipeRes := pageRes.Items[0].(dto.CookableRecipeResBody)
+	assert.Equal(t, "welsh rarebit", recipeRes.Title)
+	assert.Equal(t, 2, recipeRes.AvailableCount)
+	assert.Equal(t, []*dto.IngredientLinkResBody{{ID: 3, Name: "mustard"}}, recipeRes.MissingIngredients)
+	// test happy path: the recipes missing an ingredient are left out
+	maxMissing := uint(0)
+	pageRes, err = pantryService.GetCookableRecipes(&dto.CookableQuery{CommonQueryPage: dto.CommonQueryPage{PageSize: 10}, MaxMissing: &maxMissing}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, pageRes.TotalNbResult)
+	assert.Empty(t, pageRes.Items)
+}
@@ -0,0 +1,247 @@
+// The package 'services' contains the business logic for handling route
+package services
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/repositories"
+	"github.com/clementb49/welsh_academy/units"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ErrPriceUnitNotAcceptable is returned when an ingredient is priced with an unknown unit or a temperature
+var ErrPriceUnitNotAcceptable = errors.New("the price unit must be a known weight, volume or count unit, unit not acceptable")
+
+// PriceService is an interface for defining the methods to record the ingredient prices and to follow the recipe cost over time
+type PriceService interface {
+	CreateIngredientPrice(input *dto.CommonIdPathUri, body *dto.IngredientPriceReqBody, userId uint) (*dto.IngredientPriceResBody, error)
+	GetAllIngredientPrices(input *dto.CommonIdPathUri, query *dto.CommonQueryPage) (*dto.CommonPageRespBody, error)
+	DeleteIngredientPrice(input *dto.IngredientPricePathUri, userId uint) error
+	GetRecipeCostHistory(input *dto.CommonIdPathUri) (*dto.RecipeCostHistoryResBody, error)
+}
+
+// priceService is an implementation of the PriceService interface
+type priceService struct {
+	repo           repositories.PriceRepository
+	ingredientRepo repositories.IngredientRepository
+	recipeRepo     repositories.RecipeRepository
+	userRepo       repositories.UserRepository
+	location       *time.Location
+	logger         *zap.Logger
+}
+
+// NewPriceService creates a new PriceService instance, the user repository is used to check the user permissions
+// and the prices are recorded on the days of the location
+func NewPriceService(repo repositories.PriceRepository, ingredientRepo repositories.IngredientRepository, recipeRepo repositories.RecipeRepository,
+	userRepo repositories.UserRepository, location *time.Location) PriceService {
+	return &priceService{
+		repo:           repo,
+		ingredientRepo: ingredientRepo,
+		recipeRepo:     recipeRepo,
+		userRepo:       userRepo,
+		location:       location,
+		logger:         zap.L(),
+	}
+}
+
+// CreateIngredientPrice records a new reference price of the ingredient, the previous prices are kept in its history
+func (s *priceService) CreateIngredientPrice(input *dto.CommonIdPathUri, body *dto.IngredientPriceReqBody, userId uint) (*dto.IngredientPriceResBody, error) {
+	unit, ok := units.Lookup(body.Unit)
+	if !ok || unit.Kind == units.Temperature {
+		return nil, ErrPriceUnitNotAcceptable
+	}
+	ingredient, err := s.ingredientRepo.GetIngredientById(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	price, err := body.ConvertToModel(ingredient.ID, userId, s.location)
+	if err != nil {
+		return nil, err
+	}
+	price.Unit = unit.Symbol
+	price, err = s.repo.CreateIngredientPrice(price)
+	if err != nil {
+		return nil, err
+	}
+	priceRes := &dto.IngredientPriceResBody{}
+	priceRes.ConvertFromModel(price)
+	return priceRes, nil
+}
+
+// GetAllIngredientPrices returns a page of the price history of the ingredient, the latest first
+func (s *priceService) GetAllIngredientPrices(input *dto.CommonIdPathUri, query *dto.CommonQueryPage) (*dto.CommonPageRespBody, error) {
+	_, err := s.ingredientRepo.GetIngredientById(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	prices, totalPrices, err := s.repo.GetAllIngredientPrices(input.ID, query.PageSize, query.PageNumber)
+	if err != nil {
+		return nil, err
+	}
+	pricesRes := make([]interface{}, len(prices))
+	for i, v := range prices {
+		res := dto.IngredientPriceResBody{}
+		res.ConvertFromModel(v)
+		pricesRes[i] = res
+	}
+	return newPageRespBody(query, totalPrices, pricesRes), nil
+}
+
+// DeleteIngredientPrice removes a price from the history of the ingredient, only the user who recorded it or an administrator can remove it
+func (s *priceService) DeleteIngredientPrice(input *dto.IngredientPricePathUri, userId uint) error {
+	price, err := s.repo.GetIngredientPriceById(input.PriceID)
+	if err != nil {
+		return err
+	}
+	if price.IngredientID != input.ID {
+		return gorm.ErrRecordNotFound
+	}
+	err = checkAuthorOrAdmin(s.userRepo, price.CreatorID, userId)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteIngredientPriceById(price.ID)
+}
+
+// GetRecipeCostHistory estimates the cost of the current ingredients of the recipe at each date a price of one of them was recorded,
+// each estimate uses the latest price of the ingredients known at this date
+func (s *priceService) GetRecipeCostHistory(input *dto.CommonIdPathUri) (*dto.RecipeCostHistoryResBody, error) {
+	recipe, err := s.recipeRepo.GetRecipeById(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	prices, err := s.repo.GetRecipeIngredientPrices(recipe.ID)
+	if err != nil {
+		return nil, err
+	}
+	history := &dto.RecipeCostHistoryResBody{
+		RecipeId: recipe.ID,
+		Points:   make([]*dto.RecipeCostPointResBody, 0),
+	}
+	latestPrices := make(map[uint]*models.IngredientPrice)
+	for i, price := range prices {
+		latestPrices[price.IngredientID] = price
+		date := price.RecordedAt.Format(models.DateFormat)
+		// the prices are sorted by date so the estimate is done once all the prices of the day are known
+		if i+1 < len(prices) && prices[i+1].RecordedAt.Format(models.DateFormat) == date {
+			continue
+		}
+		history.Points = append(history.Points, &dto.RecipeCostPointResBody{Date: date, RecipeCostResBody: *recipeCost(recipe, latestPrices)})
+	}
+	return history, nil
+}
+
+// latestRecipePrices returns the latest prices of the ingredients of the recipe indexed by ingredient ID
+func latestRecipePrices(model *models.Recipe) map[uint]*models.IngredientPrice {
+	prices := make(map[uint]*models.IngredientPrice)
+	for _, line := range model.Ingredients {
+		if line.Ingredient != nil && line.Ingredient.Price != nil {
+			prices[line.IngredientID] = line.Ingredient.Price
+		}
+	}
+	return prices
+}
+
+// recipeCost estimates the cost of the recipe with the prices of the ingredients indexed by ingredient ID,
+// the optional ingredients and the quantities to taste are ignored
+func recipeCost(model *models.Recipe, prices map[uint]*models.IngredientPrice) *dto.RecipeCostResBody {
+	cost := &dto.RecipeCostResBody{
+		Currency:           recipeCostCurrency(model, prices),
+		MissingIngredients: make([]*dto.IngredientLinkResBody, 0),
+	}
+	for _, line := range model.Ingredients {
+		if line.Optional || line.Quantity == 0 || line.Ingredient == nil {
+			continue
+		}
+		price := prices[line.IngredientID]
+		lineCost, ok := ingredientLineCost(line, price)
+		if !ok || price.Currency != cost.Currency {
+			cost.MissingIngredients = append(cost.MissingIngredients, &dto.IngredientLinkResBody{ID: line.IngredientID, Name: line.Ingredient.Name})
+			continue
+		}
+		cost.Total += lineCost
+	}
+	cost.Complete = len(cost.MissingIngredients) == 0
+	servings := float64(model.Servings)
+	if servings == 0 {
+		servings = 1
+	}
+	cost.PerServing = roundPrice(cost.Total / servings)
+	cost.Total = roundPrice(cost.Total)
+	return cost
+}
+
+// recipeCostCurrency returns the currency used by most of the priced ingredient lines, the first one in alphabetical order on a tie
+func recipeCostCurrency(model *models.Recipe, prices map[uint]*models.IngredientPrice) string {
+	counts := make(map[string]int)
+	for _, line := range model.Ingredients {
+		if price, ok := prices[line.IngredientID]; ok && !line.Optional {
+			counts[price.Currency]++
+		}
+	}
+	currencies := make([]string, 0, len(counts))
+	for currency := range counts {
+		currencies = append(currencies, currency)
+	}
+	sort.Slice(currencies, func(i, j int) bool {
+		if counts[currencies[i]] != counts[currencies[j]] {
+			return counts[currencies[i]] > counts[currencies[j]]
+		}
+		return currencies[i] < currencies[j]
+	})
+	if len(currencies) == 0 {
+		return ""
+	}
+	return currencies[0]
+}
+
+// ingredientLineCost returns the cost of the quantity of the ingredient line, it returns false when the ingredient isn't priced
+// or its quantity can't be converted to the priced unit. The pieces are weighed with the piece weight of the ingredient
+// when the price is given for a weight or a volume and conversely.
+func ingredientLineCost(line *models.RecipeIngredient, price *models.IngredientPrice) (float64, bool) {
+	if price == nil || price.Quantity == 0 {
+		return 0, false
+	}
+	lineUnit, ok := units.Lookup(line.Unit)
+	if !ok {
+		return 0, false
+	}
+	priceUnit, ok := units.Lookup(price.Unit)
+	if !ok {
+		return 0, false
+	}
+	quantity, from := line.Quantity, lineUnit.Symbol
+	pieceWeight := line.Ingredient.PieceWeight
+	if lineUnit.Kind == units.Count && priceUnit.Kind != units.Count {
+		if pieceWeight == 0 {
+			return 0, false
+		}
+		quantity, from = quantity*pieceWeight, "g"
+	}
+	var converted float64
+	var err error
+	if priceUnit.Kind == units.Count && lineUnit.Kind != units.Count {
+		if pieceWeight == 0 {
+			return 0, false
+		}
+		converted, err = units.Convert(quantity, from, "g", line.Ingredient.Density)
+		converted = converted / pieceWeight
+	} else {
+		converted, err = units.Convert(quantity, from, priceUnit.Symbol, line.Ingredient.Density)
+	}
+	if err != nil {
+		return 0, false
+	}
+	return converted / price.Quantity * price.Price, true
+}
+
+// roundPrice rounds the price to the cent
+func roundPrice(price float64) float64 {
+	return math.Round(price*100) / 100
+}
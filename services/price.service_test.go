@@ -0,0 +1,123 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type mockPriceRepository struct{}
+
+func (m *mockPriceRepository) CreateIngredientPrice(price *models.IngredientPrice) (*models.IngredientPrice, error) {
+	price.ID = 4
+	return price, nil
+}
+
+func (m *mockPriceRepository) GetAllIngredientPrices(ingredientId uint, pageSize, pageNumber int) ([]*models.IngredientPrice, int64, error) {
+	prices, _ := m.GetRecipeIngredientPrices(1)
+	return []*models.IngredientPrice{prices[2], prices[0]}, 2, nil
+}
+
+func (m *mockPriceRepository) GetIngredientPriceById(priceId uint) (*models.IngredientPrice, error) {
+	prices, _ := m.GetRecipeIngredientPrices(1)
+	for _, price := range prices {
+		if price.ID == priceId {
+			return price, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockPriceRepository) DeleteIngredientPriceById(priceId uint) error {
+	return nil
+}
+
+func (m *mockPriceRepository) GetRecipeIngredientPrices(recipeId uint) ([]*models.IngredientPrice, error) {
+	return []*models.IngredientPrice{
+		{ID: 1, IngredientID: 1, Price: 7, Currency: "GBP", Quantity: 1, Unit: "kg", RecordedAt: time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC), CreatorID: 1},
+		{ID: 2, IngredientID: 2, Price: 3, Currency: "GBP", Quantity: 6, RecordedAt: time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC), CreatorID: 1},
+		{ID: 3, IngredientID: 1, Price: 8, Currency: "GBP", Quantity: 1, Unit: "kg", RecordedAt: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), CreatorID: 2},
+	}, nil
+}
+
+func TestCreateIngredientPrice(t *testing.T) {
+	location, err := time.LoadLocation("Pacific/Kiritimati")
+	assert.NoError(t, err)
+	priceService := services.NewPriceService(&mockPriceRepository{}, &mockIngredientRepository{}, &mockRecipeRepository{}, &mockUserRepository{}, location)
+	input := &dto.CommonIdPathUri{ID: 1}
+	// test happy path: the unit is normalized and the quantity defaults to 1
+	body := &dto.IngredientPriceReqBody{Price: 8.5, Currency: "GBP", Unit: "kilos", RecordedAt: "2026-03-01"}
+	priceRes, err := priceService.CreateIngredientPrice(input, body, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), priceRes.IngredientId)
+	assert.Equal(t, "kg", priceRes.Unit)
+	assert.Equal(t, 1.0, priceRes.Quantity)
+	assert.Equal(t, "2026-03-01", priceRes.RecordedAt)
+	assert.Equal(t, uint(1), priceRes.CreatorId)
+	// test happy path: the price is recorded today in the location of the service
+	priceRes, err = priceService.CreateIngredientPrice(input, &dto.IngredientPriceReqBody{Price: 8.5, Currency: "GBP", Unit: "kg"}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Now().In(location).Format(models.DateFormat), priceRes.RecordedAt)
+	// test error: the unit is a temperature
+	body = &dto.IngredientPriceReqBody{Price: 8.5, Currency: "GBP", Unit: "C"}
+	priceRes, err = priceService.CreateIngredientPrice(input, body, 1)
+	assert.ErrorIs(t, err, services.ErrPriceUnitNotAcceptable)
+	assert.Nil(t, priceRes)
+	// test error: ingredient not found
+	body = &dto.IngredientPriceReqBody{Price: 8.5, Currency: "GBP", Unit: "kg"}
+	priceRes, err = priceService.CreateIngredientPrice(&dto.CommonIdPathUri{ID: 2}, body, 1)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, priceRes)
+}
+
+func TestGetAllIngredientPrices(t *testing.T) {
+	priceService := services.NewPriceService(&mockPriceRepository{}, &mockIngredientRepository{}, &mockRecipeRepository{}, &mockUserRepository{}, time.UTC)
+	// test happy path
+	pageRes, err := priceService.GetAllIngredientPrices(&dto.CommonIdPathUri{ID: 1}, &dto.CommonQueryPage{PageSize: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, pageRes.TotalNbResult)
+	assert.Equal(t, "2026-02-01", pageRes.Items[0].(dto.IngredientPriceResBody).RecordedAt)
+	// test error: ingredient not found
+	pageRes, err = priceService.GetAllIngredientPrices(&dto.CommonIdPathUri{ID: 2}, &dto.CommonQueryPage{PageSize: 10})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, pageRes)
+}
+
+func TestDeleteIngredientPrice(t *testing.T) {
+	priceService := services.NewPriceService(&mockPriceRepository{}, &mockIngredientRepository{}, &mockRecipeRepository{}, &mockUserRepository{}, time.UTC)
+	// test happy path: the user recorded the price
+	err := priceService.DeleteIngredientPrice(&dto.IngredientPricePathUri{ID: 1, PriceID: 1}, 1)
+	assert.NoError(t, err)
+	// test happy path: an administrator removes the price
+	err = priceService.DeleteIngredientPrice(&dto.IngredientPricePathUri{ID: 1, PriceID: 3}, 3)
+	assert.NoError(t, err)
+	// test error: the user didn't record the price
+	err = priceService.DeleteIngredientPrice(&dto.IngredientPricePathUri{ID: 1, PriceID: 3}, 1)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	// test error: the price belongs to another ingredient
+	err = priceService.DeleteIngredientPrice(&dto.IngredientPricePathUri{ID: 1, PriceID: 2}, 1)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestGetRecipeCostHistory(t *testing.T) {
+	priceService := services.NewPriceService(&mockPriceRepository{}, &mockIngredientRepository{}, &mockRecipeRepository{}, &mockUserRepository{}, time.UTC)
+	// test happy path: there is a point each time a price is recorded
+	historyRes, err := priceService.GetRecipeCostHistory(&dto.CommonIdPathUri{ID: 1})
+	assert.NoError(t, err)
+	assert.Len(t, historyRes.Points, 3)
+	assert.Equal(t, "2026-01-10", historyRes.Points[0].Date)
+	assert.Equal(t, 1.4, historyRes.Points[0].Total)
+	assert.Len(t, historyRes.Points[0].MissingIngredients, 2)
+	assert.Equal(t, 2.4, historyRes.Points[1].Total)
+	assert.Equal(t, 2.6, historyRes.Points[2].Total)
+	assert.Equal(t, 0.65, historyRes.Points[2].PerServing)
+	// test error: recipe not found
+	historyRes, err = priceService.GetRecipeCostHistory(&dto.CommonIdPathUri{ID: 2})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, historyRes)
+}
@@ -2,7 +2,12 @@
 package services
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+
 	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/media"
+	"github.com/clementb49/welsh_academy/models"
 	"github.com/clementb49/welsh_academy/repositories"
 	"go.uber.org/zap"
 )
@@ -10,9 +15,14 @@ import (
 // RecipeService is an interface for defining the methods to manage recipes
 type RecipeService interface {
 	CreateRecipe(input *dto.RecipeReqBody, userId uint) (*dto.RecipeResBody, error)
-	GetAllRecipes(input *dto.CommonQueryPage) (*dto.CommonPageRespBody, error)
-	GetRecipeById(input *dto.CommonIdPathUri) (*dto.RecipeResBody, error)
-	DeleteRecipeById(input *dto.CommonIdPathUri) error
+	GetAllRecipes(input *dto.RecipeFilterQuery) (*dto.CommonPageRespBody, error)
+	SearchRecipes(input *dto.RecipeSearchQuery) (*dto.CommonPageRespBody, error)
+	GetSimilarRecipes(input *dto.CommonIdPathUri, query *dto.CommonQueryPage) (*dto.CommonPageRespBody, error)
+	GetRecipeById(input *dto.CommonIdPathUri, query *dto.RecipeQuery, viewer string) (*dto.RecipeResBody, error)
+	UpdateRecipe(input *dto.CommonIdPathUri, body *dto.RecipeReqBody, userId uint) (*dto.RecipeResBody, error)
+	PatchRecipe(input *dto.CommonIdPathUri, body *dto.RecipePatchReqBody, userId uint) (*dto.RecipeResBody, error)
+	DeleteRecipeById(input *dto.CommonIdPathUri, userId uint) error
+	ForkRecipe(input *dto.CommonIdPathUri, userId uint) (*dto.RecipeResBody, error)
 	AddToFavRecipe(userId uint, input *dto.CommonIdPathUri) (*dto.RecipeResBody, error)
 	DeleteFavRecipe(userId uint, input *dto.CommonIdPathUri) error
 	GetAllFavRecipes(input *dto.CommonQueryPage, userId uint) (*dto.CommonPageRespBody, error)
@@ -20,15 +30,19 @@ type RecipeService interface {
 
 // recipeService is an implementation of the RecipeService interface
 type recipeService struct {
-	repo   repositories.RecipeRepository
-	logger *zap.Logger
+	repo     repositories.RecipeRepository
+	userRepo repositories.UserRepository
+	storage  media.Storage
+	logger   *zap.Logger
 }
 
-// NewRecipeService creates a new RecipeService instance
-func NewRecipeService(repo repositories.RecipeRepository) RecipeService {
+// NewRecipeService creates a new RecipeService instance, the user repository is used to check the user permissions
+func NewRecipeService(repo repositories.RecipeRepository, userRepo repositories.UserRepository, storage media.Storage) RecipeService {
 	return &recipeService{
-		repo:   repo,
-		logger: zap.L(),
+		repo:     repo,
+		userRepo: userRepo,
+		storage:  storage,
+		logger:   zap.L(),
 	}
 }
 
@@ -36,18 +50,30 @@ func NewRecipeService(repo repositories.RecipeRepository) RecipeService {
 func (s *recipeService) CreateRecipe(input *dto.RecipeReqBody, userId uint) (*dto.RecipeResBody, error) {
 	recipeModel := input.ConvertToModdel()
 	recipeModel.AuthorID = uint64(userId)
-	recipeModel, err := s.repo.CreateRecipe(recipeModel, input.IngredientsId, userId)
+	recipeModel, err := s.repo.CreateRecipe(recipeModel)
 	if err != nil {
 		return nil, err
 	}
 	recipeRes := &dto.RecipeResBody{}
 	recipeRes.ConvertFromModel(recipeModel)
+	completeRecipeRes(recipeRes, recipeModel, s.storage)
 	return recipeRes, nil
 }
 
-// GetAllRecipes returns all the recipes using input to define pagination
-func (s *recipeService) GetAllRecipes(input *dto.CommonQueryPage) (*dto.CommonPageRespBody, error) {
-	recipes, totalRecipe, err := s.repo.GetAllRecipes(input.PageSize, input.PageNumber)
+// GetAllRecipes returns the recipes matching the filter using input to define pagination
+func (s *recipeService) GetAllRecipes(input *dto.RecipeFilterQuery) (*dto.CommonPageRespBody, error) {
+	filter := &repositories.RecipeFilter{
+		IncludeIngredients: input.IncludeIngredients,
+		ExcludeIngredients: input.ExcludeIngredients,
+		IncludeTypes:       input.IncludeTypes,
+		ExcludeTypes:       input.ExcludeTypes,
+		IncludeTags:        input.Tags,
+		ExcludeAllergens:   models.AllergensFromNames(input.ExcludeAllergens),
+		MatchAll:           input.Match != "any",
+		AllowSubstitutes:   input.AllowSubstitutes,
+		Sort:               input.Sort,
+	}
+	recipes, totalRecipe, err := s.repo.GetAllRecipes(filter, input.PageSize, input.PageNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -55,38 +81,166 @@ func (s *recipeService) GetAllRecipes(input *dto.CommonQueryPage) (*dto.CommonPa
 	for i, v := range recipes {
 		res := dto.RecipeResBody{}
 		res.ConvertFromModel(v)
+		completeRecipeRes(&res, v, s.storage)
 		recipesRes[i] = res
 	}
-	totalNbPage := int(totalRecipe / int64(input.PageSize))
-	pageRes := &dto.CommonPageRespBody{
-		CommonQueryPage: *input,
-		TotalNbResult:   int(totalRecipe),
-		TotablNbPage:    totalNbPage,
-		Items:           recipesRes,
+	return newPageRespBody(&input.CommonQueryPage, totalRecipe, recipesRes), nil
+}
+
+// SearchRecipes returns the recipes matching the full text search ordered by relevance using input to define pagination
+func (s *recipeService) SearchRecipes(input *dto.RecipeSearchQuery) (*dto.CommonPageRespBody, error) {
+	results, totalRecipe, err := s.repo.SearchRecipes(input.Query, input.PageSize, input.PageNumber)
+	if err != nil {
+		return nil, err
+	}
+	recipesRes := make([]interface{}, len(results))
+	for i, v := range results {
+		res := dto.RecipeSearchResBody{Rank: v.Rank, Snippet: v.Snippet}
+		res.ConvertFromModel(v.Recipe)
+		completeRecipeRes(&res.RecipeResBody, v.Recipe, s.storage)
+		recipesRes[i] = res
 	}
-	return pageRes, nil
+	return newPageRespBody(&input.CommonQueryPage, totalRecipe, recipesRes), nil
 }
 
-// GetRecipeById is a function that returns a recipe specified by ID from the database
-func (s *recipeService) GetRecipeById(input *dto.CommonIdPathUri) (*dto.RecipeResBody, error) {
+// GetSimilarRecipes returns a page of the recipes sharing ingredients or tags with the recipe, the most similar first,
+// with the ingredients they share with it
+func (s *recipeService) GetSimilarRecipes(input *dto.CommonIdPathUri, query *dto.CommonQueryPage) (*dto.CommonPageRespBody, error) {
 	recipe, err := s.repo.GetRecipeById(input.ID)
 	if err != nil {
 		return nil, err
 	}
+	results, totalRecipe, err := s.repo.GetSimilarRecipes(recipe.ID, query.PageSize, query.PageNumber)
+	if err != nil {
+		return nil, err
+	}
+	ingredientsId := make(map[uint]bool, len(recipe.Ingredients))
+	for _, line := range recipe.Ingredients {
+		ingredientsId[line.IngredientID] = true
+	}
+	recipesRes := make([]interface{}, len(results))
+	for i, v := range results {
+		res := dto.SimilarRecipeResBody{
+			Similarity:           v.Score,
+			IngredientSimilarity: v.IngredientSimilarity,
+			TagSimilarity:        v.TagSimilarity,
+			SharedIngredients:    make([]*dto.IngredientLinkResBody, 0),
+		}
+		res.ConvertFromModel(v.Recipe)
+		completeRecipeRes(&res.RecipeResBody, v.Recipe, s.storage)
+		for _, line := range v.Recipe.Ingredients {
+			if ingredientsId[line.IngredientID] && line.Ingredient != nil {
+				res.SharedIngredients = append(res.SharedIngredients, &dto.IngredientLinkResBody{ID: line.IngredientID, Name: line.Ingredient.Name})
+			}
+		}
+		recipesRes[i] = res
+	}
+	return newPageRespBody(query, totalRecipe, recipesRes), nil
+}
+
+// GetRecipeById is a function that returns a recipe specified by ID from the database and records its view by the viewer, the ingredient quantities
+// are scaled when the query asks for another number of servings and converted when it asks for a measurement system
+func (s *recipeService) GetRecipeById(input *dto.CommonIdPathUri, query *dto.RecipeQuery, viewer string) (*dto.RecipeResBody, error) {
+	recipe, err := s.repo.GetRecipeById(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.addRecipeView(recipe.ID, viewer)
+	recipeRes := &dto.RecipeResBody{}
+	recipeRes.ConvertFromModel(recipe)
+	completeRecipeRes(recipeRes, recipe, s.storage)
+	adaptRecipeQuantities(recipeRes, query)
+	return recipeRes, nil
+}
+
+// UpdateRecipe replaces all the fields and the ingredients of the recipe, only the author or an administrator can update it
+func (s *recipeService) UpdateRecipe(input *dto.CommonIdPathUri, body *dto.RecipeReqBody, userId uint) (*dto.RecipeResBody, error) {
+	recipe, err := s.getAuthorizedRecipe(input.ID, userId)
+	if err != nil {
+		return nil, err
+	}
+	body.ApplyToModel(recipe)
+	recipe, err = s.repo.UpdateRecipe(recipe, true, userId)
+	if err != nil {
+		return nil, err
+	}
 	recipeRes := &dto.RecipeResBody{}
 	recipeRes.ConvertFromModel(recipe)
+	completeRecipeRes(recipeRes, recipe, s.storage)
 	return recipeRes, nil
 }
 
-// DeleteRecipeById is a function that delete a recipe specified by ID from the database
-func (s *recipeService) DeleteRecipeById(input *dto.CommonIdPathUri) error {
-	err := s.repo.DeleteRecipeById(input.ID)
+// PatchRecipe updates only the provided fields of the recipe, only the author or an administrator can update it
+func (s *recipeService) PatchRecipe(input *dto.CommonIdPathUri, body *dto.RecipePatchReqBody, userId uint) (*dto.RecipeResBody, error) {
+	recipe, err := s.getAuthorizedRecipe(input.ID, userId)
+	if err != nil {
+		return nil, err
+	}
+	body.ApplyToModel(recipe)
+	recipe, err = s.repo.UpdateRecipe(recipe, body.Ingredients != nil, userId)
+	if err != nil {
+		return nil, err
+	}
+	recipeRes := &dto.RecipeResBody{}
+	recipeRes.ConvertFromModel(recipe)
+	completeRecipeRes(recipeRes, recipe, s.storage)
+	return recipeRes, nil
+}
+
+// DeleteRecipeById is a function that delete a recipe specified by ID from the database, only the author or an administrator can delete it.
+// The files of the photos of the recipe are removed from the storage.
+func (s *recipeService) DeleteRecipeById(input *dto.CommonIdPathUri, userId uint) error {
+	recipe, err := s.getAuthorizedRecipe(input.ID, userId)
+	if err != nil {
+		return err
+	}
+	err = s.repo.DeleteRecipeById(input.ID)
 	if err != nil {
 		return err
 	}
+	for _, v := range recipe.Images {
+		deleteImageFiles(s.storage, s.logger, &v.Image)
+	}
 	return nil
 }
 
+// ForkRecipe copies the recipe with its ingredients and its steps as a new recipe of the user, the original recipe is unchanged
+func (s *recipeService) ForkRecipe(input *dto.CommonIdPathUri, userId uint) (*dto.RecipeResBody, error) {
+	recipe, err := s.repo.ForkRecipe(input.ID, userId)
+	if err != nil {
+		return nil, err
+	}
+	recipeRes := &dto.RecipeResBody{}
+	recipeRes.ConvertFromModel(recipe)
+	completeRecipeRes(recipeRes, recipe, s.storage)
+	return recipeRes, nil
+}
+
+// addRecipeView records the view of the recipe in the background to not slow down its reading, the viewer is only stored hashed.
+// The recipe is still returned when its view can't be recorded, the view only counts for the trending recipes.
+func (s *recipeService) addRecipeView(recipeId uint, viewer string) {
+	hash := sha256.Sum256([]byte(viewer))
+	go func() {
+		err := s.repo.AddRecipeView(recipeId, hex.EncodeToString(hash[:]))
+		if err != nil {
+			s.logger.Warn("Unable to record the recipe view", zap.Uint("recipeId", recipeId), zap.Error(err))
+		}
+	}()
+}
+
+// getAuthorizedRecipe returns the recipe specified by ID when the user is allowed to modify it
+func (s *recipeService) getAuthorizedRecipe(recipeId uint, userId uint) (*models.Recipe, error) {
+	recipe, err := s.repo.GetRecipeById(recipeId)
+	if err != nil {
+		return nil, err
+	}
+	err = checkAuthorOrAdmin(s.userRepo, uint(recipe.AuthorID), userId)
+	if err != nil {
+		return nil, err
+	}
+	return recipe, nil
+}
+
 // AddToFavRecipe adds a recipe to the user's favorite list
 func (s *recipeService) AddToFavRecipe(userId uint, input *dto.CommonIdPathUri) (*dto.RecipeResBody, error) {
 	recipeModel, err := s.repo.AddToFavRecipe(userId, input.ID)
@@ -95,6 +249,7 @@ func (s *recipeService) AddToFavRecipe(userId uint, input *dto.CommonIdPathUri)
 	}
 	recipeRes := dto.RecipeResBody{}
 	recipeRes.ConvertFromModel(recipeModel)
+	completeRecipeRes(&recipeRes, recipeModel, s.storage)
 	return &recipeRes, nil
 }
 
@@ -117,14 +272,8 @@ func (s *recipeService) GetAllFavRecipes(input *dto.CommonQueryPage, userId uint
 	for i, v := range recipes {
 		res := dto.RecipeResBody{}
 		res.ConvertFromModel(v)
+		completeRecipeRes(&res, v, s.storage)
 		recipesRes[i] = res
 	}
-	totalNbPage := int(totalRecipe / int64(input.PageSize))
-	pageRes := &dto.CommonPageRespBody{
-		CommonQueryPage: *input,
-		TotalNbResult:   int(totalRecipe),
-		TotablNbPage:    totalNbPage,
-		Items:           recipesRes,
-	}
-	return pageRes, nil
+	return newPageRespBody(input, totalRecipe, recipesRes), nil
 }
@@ -0,0 +1,338 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/repositories"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type mockRecipeRepository struct {
+	views chan string // receives the viewers of the recorded views when not nil
+}
+
+func (m *mockRecipeRepository) CreateRecipe(recipe *models.Recipe) (*models.Recipe, error) {
+	recipe.ID = 1
+	return recipe, nil
+}
+
+func (m *mockRecipeRepository) GetAllRecipes(filter *repositories.RecipeFilter, pageSize int, pageNumber int) ([]*models.Recipe, int64, error) {
+	return make([]*models.Recipe, 0), 0, nil
+}
+
+func (m *mockRecipeRepository) SearchRecipes(query string, pageSize int, pageNumber int) ([]*repositories.RecipeSearchResult, int64, error) {
+	return make([]*repositories.RecipeSearchResult, 0), 0, nil
+}
+
+func (m *mockRecipeRepository) GetSimilarRecipes(recipeId uint, pageSize int, pageNumber int) ([]*repositories.SimilarRecipe, int64, error) {
+	return []*repositories.SimilarRecipe{{
+		Recipe: &models.Recipe{
+			Model: gorm.Model{ID: 6},
+			Title: "glamorgan sausage",
+			Ingredients: []*models.RecipeIngredient{
+				{IngredientID: 1, Ingredient: &models.Ingredient{Model: gorm.Model{ID: 1}, Name: "cheddar"}, Quantity: 150, Unit: "g"},
+				{IngredientID: 8, Ingredient: &models.Ingredient{Model: gorm.Model{ID: 8}, Name: "leek"}, Quantity: 1},
+			},
+		},
+		Score:                0.36,
+		IngredientSimilarity: 0.45,
+	}}, 1, nil
+}
+
+func (m *mockRecipeRepository) GetRecipeById(recipeId uint) (*models.Recipe, error) {
+	ovenTemperature := 200.0
+	if recipeId == 1 {
+		return &models.Recipe{
+			Model: gorm.Model{
+				ID:        1,
+				CreatedAt: time.Now(),
+				UpdatedAt: time.Now(),
+			},
+			Title:       "welsh rarebit",
+			Description: "toast the bread",
+			Difficulty:  2,
+			Servings:    4,
+			Ingredients: []*models.RecipeIngredient{{
+				RecipeID:     1,
+				IngredientID: 1,
+				Ingredient: &models.Ingredient{Model: gorm.Model{ID: 1}, Name: "cheddar", Type: "cheese", Allergens: models.AllergenMilk,
+					Nutrition: &models.IngredientNutrition{IngredientID: 1, EnergyKcal: 416, Fat: 34.9, Saturates: 21.7, Carbohydrate: 0.1, Sugars: 0.1, Protein: 25.4, Salt: 1.8},
+					Price:     &models.IngredientPrice{ID: 3, IngredientID: 1, Price: 8, Currency: "GBP", Quantity: 1, Unit: "kg"},
+					ReplacedIngredients: []*models.IngredientSubstitution{{ID: 2, IngredientID: 5, SubstituteID: 1, Ratio: 0.8, Bidirectional: true,
+						Ingredient: &models.Ingredient{Model: gorm.Model{ID: 5}, Name: "lancashire", Type: "cheese"}}}},
+				Quantity: 200,
+				Unit:     "g",
+				Note:     "grated",
+			}, {
+				RecipeID:     1,
+				IngredientID: 2,
+				Ingredient: &models.Ingredient{Model: gorm.Model{ID: 2}, Name: "egg", Type: "egg", Allergens: models.AllergenEggs, PieceWeight: 50,
+					Nutrition: &models.IngredientNutrition{IngredientID: 2, EnergyKcal: 143, Fat: 9.5, Saturates: 3.1, Carbohydrate: 0.7, Sugars: 0.4, Protein: 12.6, Salt: 0.36},
+					Price:     &models.IngredientPrice{ID: 2, IngredientID: 2, Price: 3, Currency: "GBP", Quantity: 6}},
+				Quantity: 2,
+				Position: 1,
+			}, {
+				RecipeID:     1,
+				IngredientID: 3,
+				Ingredient:   &models.Ingredient{Model: gorm.Model{ID: 3}, Name: "mustard", Type: "condiment", Allergens: models.AllergenMustard | models.AllergenSulphites},
+				Quantity:     1,
+				Unit:         "tsp",
+				Position:     2,
+			}},
+			Images: []*models.RecipeImage{{ID: 1, RecipeID: 1, Cover: true,
+				Image: models.Image{Path: "recipes/1/rarebit.jpg", ThumbnailPath: "recipes/1/rarebit_thumb.jpg"}}},
+			AuthorID:            1,
+			OvenTemperature:     &ovenTemperature,
+			OvenTemperatureUnit: "C",
+		}, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockRecipeRepository) AddRecipeView(recipeId uint, viewer string) error {
+	if m.views != nil {
+		m.views <- viewer
+	}
+	return nil
+}
+
+func (m *mockRecipeRepository) UpdateRecipe(recipe *models.Recipe, replaceIngredients bool, editorId uint) (*models.Recipe, error) {
+	return recipe, nil
+}
+
+func (m *mockRecipeRepository) DeleteRecipeById(recipeId uint) error {
+	return nil
+}
+
+func (m *mockRecipeRepository) ForkRecipe(recipeId uint, userId uint) (*models.Recipe, error) {
+	original, err := m.GetRecipeById(recipeId)
+	if err != nil {
+		return nil, err
+	}
+	fork := *original
+	fork.ID = 5
+	fork.Title = original.Title + " (fork)"
+	fork.AuthorID = uint64(userId)
+	fork.ParentID = &original.ID
+	fork.Parent = &models.Recipe{Model: gorm.Model{ID: original.ID}, Title: original.Title, AuthorID: original.AuthorID}
+	return &fork, nil
+}
+
+func (m *mockRecipeRepository) AddToFavRecipe(userId, recipeId uint) (*models.Recipe, error) {
+	return m.GetRecipeById(recipeId)
+}
+
+func (m *mockRecipeRepository) DeleteFavRecipe(userId, recipeId uint) error {
+	return nil
+}
+
+func (m *mockRecipeRepository) GetAllFavRecipes(pageSize int, pageNumber int, userId uint) ([]*models.Recipe, int64, error) {
+	return make([]*models.Recipe, 0), 0, nil
+}
+
+func TestUpdateRecipe(t *testing.T) {
+	recipeService := services.NewRecipeService(&mockRecipeRepository{}, &mockUserRepository{}, &mockStorage{})
+	input := &dto.CommonIdPathUri{ID: 1}
+	body := &dto.RecipeReqBody{
+		Title:       "welsh rarebit with beer",
+		Description: "melt the cheese in the beer",
+		Difficulty:  3,
+		Ingredients: []dto.RecipeIngredientReqBody{
+			{IngredientId: 1, Quantity: 250, Unit: "g", Note: "grated"},
+			{IngredientId: 2, Quantity: 100, Unit: "ml"},
+		},
+	}
+	// test happy path: the author updates the recipe
+	recipeRes, err := recipeService.UpdateRecipe(input, body, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "welsh rarebit with beer", recipeRes.Title)
+	assert.Equal(t, "melt the cheese in the beer", recipeRes.Description)
+	assert.Equal(t, uint8(3), recipeRes.Difficulty)
+	assert.Equal(t, 2, len(recipeRes.Ingredients))
+	assert.Equal(t, uint(1), recipeRes.Ingredients[0].ID)
+	assert.Equal(t, 250.0, recipeRes.Ingredients[0].Quantity)
+	assert.Equal(t, "g", recipeRes.Ingredients[0].Unit)
+	assert.Equal(t, "grated", recipeRes.Ingredients[0].Note)
+	assert.Equal(t, uint(2), recipeRes.Ingredients[1].ID)
+	assert.Equal(t, "ml", recipeRes.Ingredients[1].Unit)
+	// test happy path: an administrator updates the recipe
+	recipeRes, err = recipeService.UpdateRecipe(input, body, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), recipeRes.AuthorId)
+	// test error: another user updates the recipe
+	recipeRes, err = recipeService.UpdateRecipe(input, body, 2)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	assert.Nil(t, recipeRes)
+	// test error: recipe not found
+	recipeRes, err = recipeService.UpdateRecipe(&dto.CommonIdPathUri{ID: 2}, body, 1)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, recipeRes)
+}
+
+func TestPatchRecipe(t *testing.T) {
+	recipeService := services.NewRecipeService(&mockRecipeRepository{}, &mockUserRepository{}, &mockStorage{})
+	input := &dto.CommonIdPathUri{ID: 1}
+	title := "welsh rarebit with beer"
+	// test happy path: only the title is updated
+	recipeRes, err := recipeService.PatchRecipe(input, &dto.RecipePatchReqBody{Title: &title}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "welsh rarebit with beer", recipeRes.Title)
+	assert.Equal(t, "toast the bread", recipeRes.Description)
+	assert.Equal(t, uint8(2), recipeRes.Difficulty)
+	assert.Equal(t, 3, len(recipeRes.Ingredients))
+	assert.Equal(t, "cheddar", recipeRes.Ingredients[0].Name)
+	assert.Equal(t, 200.0, recipeRes.Ingredients[0].Quantity)
+	// test error: another user updates the recipe
+	recipeRes, err = recipeService.PatchRecipe(input, &dto.RecipePatchReqBody{Title: &title}, 2)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	assert.Nil(t, recipeRes)
+}
+
+func TestDeleteRecipeById(t *testing.T) {
+	storage := &mockStorage{files: map[string][]byte{"recipes/1/rarebit.jpg": {}, "recipes/1/rarebit_thumb.jpg": {}}}
+	recipeService := services.NewRecipeService(&mockRecipeRepository{}, &mockUserRepository{}, storage)
+	input := &dto.CommonIdPathUri{ID: 1}
+	// test error: another user deletes the recipe
+	assert.ErrorIs(t, recipeService.DeleteRecipeById(input, 2), services.ErrForbidden)
+	assert.Len(t, storage.files, 2)
+	// test happy path: the author and an administrator can delete the recipe, the files of its photos are removed
+	assert.NoError(t, recipeService.DeleteRecipeById(input, 1))
+	assert.Empty(t, storage.files)
+	assert.NoError(t, recipeService.DeleteRecipeById(input, 3))
+	// test error: recipe not found
+	assert.ErrorIs(t, recipeService.DeleteRecipeById(&dto.CommonIdPathUri{ID: 2}, 1), gorm.ErrRecordNotFound)
+}
+
+func TestForkRecipe(t *testing.T) {
+	recipeService := services.NewRecipeService(&mockRecipeRepository{}, &mockUserRepository{}, &mockStorage{})
+	recipeRes, err := recipeService.ForkRecipe(&dto.CommonIdPathUri{ID: 1}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(5), recipeRes.ID)
+	assert.Equal(t, "welsh rarebit (fork)", recipeRes.Title)
+	assert.Equal(t, uint(2), recipeRes.AuthorId)
+	assert.Len(t, recipeRes.Ingredients, 3)
+	assert.Equal(t, &dto.RecipeLinkResBody{ID: 1, Title: "welsh rarebit", AuthorId: 1}, recipeRes.Parent)
+	// test error: recipe not found
+	recipeRes, err = recipeService.ForkRecipe(&dto.CommonIdPathUri{ID: 2}, 2)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, recipeRes)
+}
+
+func TestGetRecipeById(t *testing.T) {
+	recipeService := services.NewRecipeService(&mockRecipeRepository{}, &mockUserRepository{}, &mockStorage{})
+	input := &dto.CommonIdPathUri{ID: 1}
+	// test happy path: the recipe isn't scaled
+	recipeRes, err := recipeService.GetRecipeById(input, &dto.RecipeQuery{}, "203.0.113.7")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(4), recipeRes.Servings)
+	assert.Equal(t, uint(0), recipeRes.OriginalServings)
+	assert.Equal(t, 200.0, recipeRes.Ingredients[0].Quantity)
+	// the allergens of the recipe are the ones of its ingredients
+	assert.Equal(t, []string{"eggs", "milk", "mustard", "sulphites"}, recipeRes.Allergens)
+	assert.Equal(t, []string{"milk"}, recipeRes.Ingredients[0].Allergens)
+	// the bidirectional substitution is suggested with the inverse ratio
+	assert.Len(t, recipeRes.Ingredients[0].Substitutes, 1)
+	assert.Equal(t, "lancashire", recipeRes.Ingredients[0].Substitutes[0].Name)
+	assert.Equal(t, 250.0, recipeRes.Ingredients[0].Substitutes[0].Quantity)
+	assert.Equal(t, "g", recipeRes.Ingredients[0].Substitutes[0].Unit)
+	assert.Empty(t, recipeRes.Ingredients[2].Substitutes)
+	// the nutrition is incomplete because the mustard has no nutrition facts and can't be weighed without its density
+	assert.Equal(t, 975.0, recipeRes.Nutrition.Total.EnergyKcal)
+	assert.Equal(t, 79.3, recipeRes.Nutrition.Total.Fat)
+	assert.Equal(t, 243.8, recipeRes.Nutrition.PerServing.EnergyKcal)
+	assert.False(t, recipeRes.Nutrition.Complete)
+	assert.Equal(t, []*dto.IngredientLinkResBody{{ID: 3, Name: "mustard"}}, recipeRes.Nutrition.MissingIngredients)
+	// the cost is estimated with the latest prices, the mustard isn't priced
+	assert.Equal(t, "GBP", recipeRes.Cost.Currency)
+	assert.Equal(t, 2.6, recipeRes.Cost.Total)
+	assert.Equal(t, 0.65, recipeRes.Cost.PerServing)
+	assert.False(t, recipeRes.Cost.Complete)
+	assert.Equal(t, []*dto.IngredientLinkResBody{{ID: 3, Name: "mustard"}}, recipeRes.Cost.MissingIngredients)
+	// test happy path: the recipe is scaled up
+	recipeRes, err = recipeService.GetRecipeById(input, &dto.RecipeQuery{Servings: 7}, "203.0.113.7")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), recipeRes.Servings)
+	assert.Equal(t, uint(4), recipeRes.OriginalServings)
+	assert.Equal(t, 350.0, recipeRes.Ingredients[0].Quantity)
+	assert.Equal(t, 440.0, recipeRes.Ingredients[0].Substitutes[0].Quantity)
+	assert.Equal(t, 4.0, recipeRes.Ingredients[1].Quantity)
+	assert.Equal(t, 1.75, recipeRes.Ingredients[2].Quantity)
+	// test happy path: the recipe is scaled down, the grams are rounded to 5 and the eggs to a whole number
+	recipeRes, err = recipeService.GetRecipeById(input, &dto.RecipeQuery{Servings: 3}, "203.0.113.7")
+	assert.NoError(t, err)
+	assert.Equal(t, 150.0, recipeRes.Ingredients[0].Quantity)
+	assert.Equal(t, 2.0, recipeRes.Ingredients[1].Quantity)
+	assert.Equal(t, 0.75, recipeRes.Ingredients[2].Quantity)
+	recipeRes, err = recipeService.GetRecipeById(input, &dto.RecipeQuery{Servings: 1}, "203.0.113.7")
+	assert.NoError(t, err)
+	assert.Equal(t, 50.0, recipeRes.Ingredients[0].Quantity)
+	assert.Equal(t, 1.0, recipeRes.Ingredients[1].Quantity)
+	assert.Equal(t, 0.25, recipeRes.Ingredients[2].Quantity)
+	// test happy path: the recipe is converted to the imperial system, the spoons and the eggs are kept
+	recipeRes, err = recipeService.GetRecipeById(input, &dto.RecipeQuery{Units: "imperial"}, "203.0.113.7")
+	assert.NoError(t, err)
+	assert.Equal(t, 7.0, recipeRes.Ingredients[0].Quantity)
+	assert.Equal(t, "oz", recipeRes.Ingredients[0].Unit)
+	assert.Equal(t, 2.0, recipeRes.Ingredients[1].Quantity)
+	assert.Equal(t, "tsp", recipeRes.Ingredients[2].Unit)
+	assert.Equal(t, 400.0, *recipeRes.OvenTemperature)
+	assert.Equal(t, "F", recipeRes.OvenTemperatureUnit)
+	assert.Equal(t, 6.0, *recipeRes.OvenGasMark)
+	// test happy path: the recipe is scaled and kept in the metric system
+	recipeRes, err = recipeService.GetRecipeById(input, &dto.RecipeQuery{Servings: 8, Units: "metric"}, "203.0.113.7")
+	assert.NoError(t, err)
+	assert.Equal(t, 400.0, recipeRes.Ingredients[0].Quantity)
+	assert.Equal(t, "g", recipeRes.Ingredients[0].Unit)
+	assert.Equal(t, 1950.0, recipeRes.Nutrition.Total.EnergyKcal)
+	assert.Equal(t, 5.2, recipeRes.Cost.Total)
+	assert.Equal(t, 0.65, recipeRes.Cost.PerServing)
+	assert.Equal(t, 243.8, recipeRes.Nutrition.PerServing.EnergyKcal)
+	assert.Equal(t, 200.0, *recipeRes.OvenTemperature)
+	assert.Nil(t, recipeRes.OvenGasMark)
+	// test error: recipe not found
+	recipeRes, err = recipeService.GetRecipeById(&dto.CommonIdPathUri{ID: 2}, &dto.RecipeQuery{}, "203.0.113.7")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, recipeRes)
+}
+
+func TestGetRecipeByIdAddRecipeView(t *testing.T) {
+	repo := &mockRecipeRepository{views: make(chan string, 1)}
+	recipeService := services.NewRecipeService(repo, &mockUserRepository{}, &mockStorage{})
+	// test happy path: the view is recorded in the background with the hash of the viewer
+	_, err := recipeService.GetRecipeById(&dto.CommonIdPathUri{ID: 1}, &dto.RecipeQuery{}, "203.0.113.7")
+	assert.NoError(t, err)
+	select {
+	case viewer := <-repo.views:
+		assert.Len(t, viewer, 64)
+		assert.NotContains(t, viewer, "203.0.113.7")
+	case <-time.After(time.Second):
+		assert.Fail(t, "the recipe view wasn't recorded")
+	}
+	// test error: the view of a missing recipe isn't recorded
+	_, err = recipeService.GetRecipeById(&dto.CommonIdPathUri{ID: 2}, &dto.RecipeQuery{}, "203.0.113.7")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Empty(t, repo.views)
+}
+
+func TestGetSimilarRecipes(t *testing.T) {
+	recipeService := services.NewRecipeService(&mockRecipeRepository{}, &mockUserRepository{}, &mockStorage{})
+	query := &dto.CommonQueryPage{PageSize: 10}
+	// test happy path: the ingredients shared with the recipe are listed
+	pageRes, err := recipeService.GetSimilarRecipes(&dto.CommonIdPathUri{ID: 1}, query)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, pageRes.TotalNbResult)
+	recipeRes := pageRes.Items[0].(dto.SimilarRecipeResBody)
+	assert.Equal(t, "glamorgan sausage", recipeRes.Title)
+	assert.Equal(t, 0.36, recipeRes.Similarity)
+	assert.Equal(t, 0.45, recipeRes.IngredientSimilarity)
+	assert.Equal(t, []*dto.IngredientLinkResBody{{ID: 1, Name: "cheddar"}}, recipeRes.SharedIngredients)
+	// test error: recipe not found
+	pageRes, err = recipeService.GetSimilarRecipes(&dto.CommonIdPathUri{ID: 2}, query)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, pageRes)
+}
@@ -0,0 +1,115 @@
+// The package 'services' contains the business logic for handling route
+package services
+
+import (
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/media"
+	"github.com/clementb49/welsh_academy/repositories"
+	"go.uber.org/zap"
+)
+
+// RecipeRevisionService is an interface for defining the methods to browse the revision history of the recipes and to restore a revision
+type RecipeRevisionService interface {
+	GetAllRecipeRevisions(input *dto.CommonIdPathUri, query *dto.CommonQueryPage) (*dto.CommonPageRespBody, error)
+	GetRecipeRevision(input *dto.RecipeRevisionPathUri) (*dto.RecipeRevisionResBody, error)
+	DiffRecipeRevisions(input *dto.CommonIdPathUri, query *dto.RecipeRevisionDiffQuery) (*dto.RecipeRevisionDiffResBody, error)
+	RollbackRecipe(input *dto.RecipeRevisionPathUri, userId uint) (*dto.RecipeResBody, error)
+}
+
+// recipeRevisionService is an implementation of the RecipeRevisionService interface
+type recipeRevisionService struct {
+	repo       repositories.RecipeRevisionRepository
+	recipeRepo repositories.RecipeRepository
+	userRepo   repositories.UserRepository
+	storage    media.Storage
+	logger     *zap.Logger
+}
+
+// NewRecipeRevisionService creates a new RecipeRevisionService instance, the recipe repository saves the restored revisions
+// and the user repository is used to check the user permissions
+func NewRecipeRevisionService(repo repositories.RecipeRevisionRepository, recipeRepo repositories.RecipeRepository, userRepo repositories.UserRepository, storage media.Storage) RecipeRevisionService {
+	return &recipeRevisionService{
+		repo:       repo,
+		recipeRepo: recipeRepo,
+		userRepo:   userRepo,
+		storage:    storage,
+		logger:     zap.L(),
+	}
+}
+
+// GetAllRecipeRevisions returns a page of the revisions of the recipe, the most recent first
+func (s *recipeRevisionService) GetAllRecipeRevisions(input *dto.CommonIdPathUri, query *dto.CommonQueryPage) (*dto.CommonPageRespBody, error) {
+	_, err := s.recipeRepo.GetRecipeById(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	revisions, totalRevisions, err := s.repo.GetAllRecipeRevisions(input.ID, query.PageSize, query.PageNumber)
+	if err != nil {
+		return nil, err
+	}
+	revisionsRes := make([]interface{}, len(revisions))
+	for i, v := range revisions {
+		res := dto.RecipeRevisionResBody{}
+		res.ConvertFromModel(v)
+		revisionsRes[i] = res
+	}
+	return newPageRespBody(query, totalRevisions, revisionsRes), nil
+}
+
+// GetRecipeRevision returns a revision of the recipe by its number
+func (s *recipeRevisionService) GetRecipeRevision(input *dto.RecipeRevisionPathUri) (*dto.RecipeRevisionResBody, error) {
+	_, err := s.recipeRepo.GetRecipeById(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	revision, err := s.repo.GetRecipeRevision(input.ID, input.Number)
+	if err != nil {
+		return nil, err
+	}
+	revisionRes := &dto.RecipeRevisionResBody{}
+	revisionRes.ConvertFromModel(revision)
+	return revisionRes, nil
+}
+
+// DiffRecipeRevisions returns the fields and the ingredient lines changed between the two revisions of the recipe
+func (s *recipeRevisionService) DiffRecipeRevisions(input *dto.CommonIdPathUri, query *dto.RecipeRevisionDiffQuery) (*dto.RecipeRevisionDiffResBody, error) {
+	_, err := s.recipeRepo.GetRecipeById(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	from, err := s.repo.GetRecipeRevision(input.ID, query.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.repo.GetRecipeRevision(input.ID, query.To)
+	if err != nil {
+		return nil, err
+	}
+	return diffRecipeRevisions(from, to), nil
+}
+
+// RollbackRecipe restores the fields and the ingredient lines of the recipe saved in the revision, the restored recipe
+// is saved as a new revision so the history is never rewritten. Only the author or an administrator can restore a revision.
+func (s *recipeRevisionService) RollbackRecipe(input *dto.RecipeRevisionPathUri, userId uint) (*dto.RecipeResBody, error) {
+	recipe, err := s.recipeRepo.GetRecipeById(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	err = checkAuthorOrAdmin(s.userRepo, uint(recipe.AuthorID), userId)
+	if err != nil {
+		return nil, err
+	}
+	revision, err := s.repo.GetRecipeRevision(input.ID, input.Number)
+	if err != nil {
+		return nil, err
+	}
+	applyRecipeRevision(recipe, revision)
+	recipe, err = s.recipeRepo.UpdateRecipe(recipe, true, userId)
+	if err != nil {
+		return nil, err
+	}
+	recipeRes := &dto.RecipeResBody{}
+	recipeRes.ConvertFromModel(recipe)
+	completeRecipeRes(recipeRes, recipe, s.storage)
+	return recipeRes, nil
+}
@@ -0,0 +1,124 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type mockRecipeRevisionRepository struct{}
+
+func (m *mockRecipeRevisionRepository) GetAllRecipeRevisions(recipeId uint, pageSize, pageNumber int) ([]*models.RecipeRevision, int64, error) {
+	second, _ := m.GetRecipeRevision(recipeId, 2)
+	first, _ := m.GetRecipeRevision(recipeId, 1)
+	return []*models.RecipeRevision{second, first}, 2, nil
+}
+
+func (m *mockRecipeRevisionRepository) GetRecipeRevision(recipeId, number uint) (*models.RecipeRevision, error) {
+	ovenTemperature := 200.0
+	if recipeId != 1 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	switch number {
+	case 1:
+		return &models.RecipeRevision{
+			ID: 1, CreatedAt: time.Now(), RecipeID: 1, Number: 1, EditorID: 1,
+			Title: "rarebit", Description: "cheese on toast", Difficulty: 1, Servings: 2,
+			Ingredients: models.RecipeRevisionIngredients{
+				{IngredientID: 1, Name: "cheddar", Quantity: 100, Unit: "g"},
+				{IngredientID: 4, Name: "ale", Quantity: 50, Unit: "ml"},
+				{IngredientID: 3, Name: "mustard", Quantity: 1, Unit: "tsp"},
+			},
+		}, nil
+	case 2:
+		return &models.RecipeRevision{
+			ID: 2, CreatedAt: time.Now(), RecipeID: 1, Number: 2, EditorID: 3,
+			Title: "welsh rarebit", Description: "cheese on toast", Difficulty: 1, Servings: 4,
+			OvenTemperature: &ovenTemperature, OvenTemperatureUnit: "C",
+			Ingredients: models.RecipeRevisionIngredients{
+				{IngredientID: 1, Name: "cheddar", Quantity: 200, Unit: "g", Note: "grated"},
+				{IngredientID: 2, Name: "egg", Quantity: 2},
+				{IngredientID: 3, Name: "mustard", Quantity: 1, Unit: "tsp"},
+			},
+		}, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestGetAllRecipeRevisions(t *testing.T) {
+	revisionService := services.NewRecipeRevisionService(&mockRecipeRevisionRepository{}, &mockRecipeRepository{}, &mockUserRepository{}, &mockStorage{})
+	query := &dto.CommonQueryPage{PageSize: 10, PageNumber: 0}
+	pageRes, err := revisionService.GetAllRecipeRevisions(&dto.CommonIdPathUri{ID: 1}, query)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, pageRes.TotalNbResult)
+	assert.Len(t, pageRes.Items, 2)
+	assert.Equal(t, uint(2), pageRes.Items[0].(dto.RecipeRevisionResBody).Number)
+	// test error: recipe not found
+	_, err = revisionService.GetAllRecipeRevisions(&dto.CommonIdPathUri{ID: 2}, query)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestGetRecipeRevision(t *testing.T) {
+	revisionService := services.NewRecipeRevisionService(&mockRecipeRevisionRepository{}, &mockRecipeRepository{}, &mockUserRepository{}, &mockStorage{})
+	revisionRes, err := revisionService.GetRecipeRevision(&dto.RecipeRevisionPathUri{ID: 1, Number: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "rarebit", revisionRes.Title)
+	assert.Len(t, revisionRes.Ingredients, 3)
+	assert.Equal(t, "ale", revisionRes.Ingredients[1].Name)
+	// test error: revision not found
+	_, err = revisionService.GetRecipeRevision(&dto.RecipeRevisionPathUri{ID: 1, Number: 3})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestDiffRecipeRevisions(t *testing.T) {
+	revisionService := services.NewRecipeRevisionService(&mockRecipeRevisionRepository{}, &mockRecipeRepository{}, &mockUserRepository{}, &mockStorage{})
+	diffRes, err := revisionService.DiffRecipeRevisions(&dto.CommonIdPathUri{ID: 1}, &dto.RecipeRevisionDiffQuery{From: 1, To: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []*dto.RecipeFieldChangeResBody{
+		{Field: "title", From: "rarebit", To: "welsh rarebit"},
+		{Field: "servings", From: uint(2), To: uint(4)},
+		{Field: "oven_temperature", From: nil, To: 200.0},
+		{Field: "oven_temperature_unit", From: "", To: "C"},
+	}, diffRes.Changes)
+	assert.Equal(t, []*dto.RecipeIngredientChangeResBody{
+		{IngredientId: 1, Name: "cheddar", Change: "modified", Changes: []*dto.RecipeFieldChangeResBody{
+			{Field: "quantity", From: 100.0, To: 200.0},
+			{Field: "note", From: "", To: "grated"},
+		}},
+		{IngredientId: 4, Name: "ale", Change: "removed"},
+		{IngredientId: 2, Name: "egg", Change: "added"},
+	}, diffRes.Ingredients)
+	// a revision compared to itself has no change
+	diffRes, err = revisionService.DiffRecipeRevisions(&dto.CommonIdPathUri{ID: 1}, &dto.RecipeRevisionDiffQuery{From: 2, To: 2})
+	assert.NoError(t, err)
+	assert.Empty(t, diffRes.Changes)
+	assert.Empty(t, diffRes.Ingredients)
+	// test error: revision not found
+	_, err = revisionService.DiffRecipeRevisions(&dto.CommonIdPathUri{ID: 1}, &dto.RecipeRevisionDiffQuery{From: 1, To: 5})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestRollbackRecipe(t *testing.T) {
+	revisionService := services.NewRecipeRevisionService(&mockRecipeRevisionRepository{}, &mockRecipeRepository{}, &mockUserRepository{}, &mockStorage{})
+	recipeRes, err := revisionService.RollbackRecipe(&dto.RecipeRevisionPathUri{ID: 1, Number: 1}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "rarebit", recipeRes.Title)
+	assert.Equal(t, uint(2), recipeRes.Servings)
+	assert.Nil(t, recipeRes.OvenTemperature)
+	assert.Len(t, recipeRes.Ingredients, 3)
+	assert.Equal(t, uint(4), recipeRes.Ingredients[1].ID)
+	// an administrator can restore a revision of the recipe
+	_, err = revisionService.RollbackRecipe(&dto.RecipeRevisionPathUri{ID: 1, Number: 1}, 3)
+	assert.NoError(t, err)
+	// test error: the user isn't the author of the recipe
+	_, err = revisionService.RollbackRecipe(&dto.RecipeRevisionPathUri{ID: 1, Number: 1}, 2)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	// test error: revision not found
+	_, err = revisionService.RollbackRecipe(&dto.RecipeRevisionPathUri{ID: 1, Number: 3}, 1)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
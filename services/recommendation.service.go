@@ -0,0 +1,63 @@
+// The package 'services' contains the business logic for handling route
+package services
+
+import (
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/media"
+	"github.com/clementb49/welsh_academy/repositories"
+	"go.uber.org/zap"
+)
+
+// The maximum number of recipes recommended to a user by a computation
+const maxRecommendationsPerUser = 100
+
+// RecommendationService is an interface for defining the methods to compute and to get the recipes recommended to the users
+type RecommendationService interface {
+	RefreshRecommendations() error
+	GetUserRecommendations(query *dto.CommonQueryPage, userId uint) (*dto.CommonPageRespBody, error)
+}
+
+// recommendationService is an implementation of the RecommendationService interface
+type recommendationService struct {
+	repo    repositories.RecommendationRepository
+	storage media.Storage
+	logger  *zap.Logger
+}
+
+// NewRecommendationService creates a new RecommendationService instance
+func NewRecommendationService(repo repositories.RecommendationRepository, storage media.Storage) RecommendationService {
+	return &recommendationService{
+		repo:    repo,
+		storage: storage,
+		logger:  zap.L(),
+	}
+}
+
+// RefreshRecommendations computes the recommendations of all the users from their favorites, it's run periodically in the background
+func (s *recommendationService) RefreshRecommendations() error {
+	return s.repo.RefreshRecommendations(maxRecommendationsPerUser)
+}
+
+// GetUserRecommendations returns a page of the recipes recommended to the user, the most relevant first.
+// The recommendations computed from the favorites are used when there are some, otherwise the user is new or their
+// favorites are not shared with other users and the recipes using the ingredients they like are recommended.
+func (s *recommendationService) GetUserRecommendations(query *dto.CommonQueryPage, userId uint) (*dto.CommonPageRespBody, error) {
+	results, totalRecipes, err := s.repo.GetRecommendedRecipes(userId, query.PageSize, query.PageNumber)
+	if err != nil {
+		return nil, err
+	}
+	if totalRecipes == 0 {
+		results, totalRecipes, err = s.repo.GetColdStartRecipes(userId, query.PageSize, query.PageNumber)
+		if err != nil {
+			return nil, err
+		}
+	}
+	recipesRes := make([]interface{}, len(results))
+	for i, v := range results {
+		res := dto.RecommendationResBody{Score: v.Score, Source: v.Source}
+		res.ConvertFromModel(v.Recipe)
+		completeRecipeRes(&res.RecipeResBody, v.Recipe, s.storage)
+		recipesRes[i] = res
+	}
+	return newPageRespBody(query, totalRecipes, recipesRes), nil
+}
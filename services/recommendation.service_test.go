@@ -0,0 +1,66 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/repositories"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type mockRecommendationRepository struct {
+	maxPerUser int
+}
+
+func (m *mockRecommendationRepository) RefreshRecommendations(maxPerUser int) error {
+	m.maxPerUser = maxPerUser
+	return nil
+}
+
+func (m *mockRecommendationRepository) GetRecommendedRecipes(userId uint, pageSize, pageNumber int) ([]*repositories.RecommendedRecipe, int64, error) {
+	if userId != 1 {
+		return []*repositories.RecommendedRecipe{}, 0, nil
+	}
+	return []*repositories.RecommendedRecipe{{
+		Recipe: &models.Recipe{Model: gorm.Model{ID: 6}, Title: "glamorgan sausage"},
+		Score:  1.5,
+		Source: repositories.RecommendationSourceFavorites,
+	}}, 1, nil
+}
+
+func (m *mockRecommendationRepository) GetColdStartRecipes(userId uint, pageSize, pageNumber int) ([]*repositories.RecommendedRecipe, int64, error) {
+	return []*repositories.RecommendedRecipe{
+		{Recipe: &models.Recipe{Model: gorm.Model{ID: 1}, Title: "welsh rarebit"}, Score: 0.6, Source: repositories.RecommendationSourceIngredients},
+		{Recipe: &models.Recipe{Model: gorm.Model{ID: 7}, Title: "bara brith"}, Source: repositories.RecommendationSourcePopular},
+	}, 2, nil
+}
+
+func TestRefreshRecommendations(t *testing.T) {
+	repo := &mockRecommendationRepository{}
+	recommendationService := services.NewRecommendationService(repo, &mockStorage{})
+	// test happy path: the number of recommendations by user is limited
+	err := recommendationService.RefreshRecommendations()
+	assert.NoError(t, err)
+	assert.Equal(t, 100, repo.maxPerUser)
+}
+
+func TestGetUserRecommendations(t *testing.T) {
+	recommendationService := services.NewRecommendationService(&mockRecommendationRepository{}, &mockStorage{})
+	query := &dto.CommonQueryPage{PageSize: 10}
+	// test happy path: the recommendations computed from the favorites are used
+	pageRes, err := recommendationService.GetUserRecommendations(query, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, pageRes.TotalNbResult)
+	recipeRes := pageRes.Items[0].(dto.RecommendationResBody)
+	assert.Equal(t, "glamorgan sausage", recipeRes.Title)
+	assert.Equal(t, "favorites", recipeRes.Source)
+	// test happy path: a user without recommendations gets the recipes using the ingredients they like
+	pageRes, err = recommendationService.GetUserRecommendations(query, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, pageRes.TotalNbResult)
+	assert.Equal(t, "ingredients", pageRes.Items[0].(dto.RecommendationResBody).Source)
+	assert.Equal(t, "popular", pageRes.Items[1].(dto.RecommendationResBody).Source)
+}
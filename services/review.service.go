@@ -0,0 +1,70 @@
+// The package 'services' contains the business logic for handling route
+package services
+
+import (
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/repositories"
+	"go.uber.org/zap"
+)
+
+// ReviewService is an interface for defining the methods to rate and review the recipes
+type ReviewService interface {
+	SaveReview(input *dto.CommonIdPathUri, body *dto.ReviewReqBody, userId uint) (*dto.ReviewResBody, error)
+	GetAllReviews(input *dto.CommonIdPathUri, query *dto.CommonQueryPage) (*dto.CommonPageRespBody, error)
+	DeleteReview(input *dto.CommonIdPathUri, userId uint) error
+}
+
+// reviewService is an implementation of the ReviewService interface
+type reviewService struct {
+	repo       repositories.ReviewRepository
+	recipeRepo repositories.RecipeRepository
+	logger     *zap.Logger
+}
+
+// NewReviewService creates a new ReviewService instance, the recipe repository is used to check that the recipe exists
+func NewReviewService(repo repositories.ReviewRepository, recipeRepo repositories.RecipeRepository) ReviewService {
+	return &reviewService{
+		repo:       repo,
+		recipeRepo: recipeRepo,
+		logger:     zap.L(),
+	}
+}
+
+// SaveReview creates or replaces the review of the recipe written by the user
+func (s *reviewService) SaveReview(input *dto.CommonIdPathUri, body *dto.ReviewReqBody, userId uint) (*dto.ReviewResBody, error) {
+	_, err := s.recipeRepo.GetRecipeById(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	review, err := s.repo.SaveReview(body.ConvertToModel(userId, input.ID))
+	if err != nil {
+		return nil, err
+	}
+	reviewRes := &dto.ReviewResBody{}
+	reviewRes.ConvertFromModel(review)
+	return reviewRes, nil
+}
+
+// GetAllReviews returns a page of the reviews of the recipe
+func (s *reviewService) GetAllReviews(input *dto.CommonIdPathUri, query *dto.CommonQueryPage) (*dto.CommonPageRespBody, error) {
+	_, err := s.recipeRepo.GetRecipeById(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	reviews, totalReviews, err := s.repo.GetAllReviews(input.ID, query.PageSize, query.PageNumber)
+	if err != nil {
+		return nil, err
+	}
+	reviewsRes := make([]interface{}, len(reviews))
+	for i, v := range reviews {
+		res := dto.ReviewResBody{}
+		res.ConvertFromModel(v)
+		reviewsRes[i] = res
+	}
+	return newPageRespBody(query, totalReviews, reviewsRes), nil
+}
+
+// DeleteReview deletes the review of the recipe written by the user
+func (s *reviewService) DeleteReview(input *dto.CommonIdPathUri, userId uint) error {
+	return s.repo.DeleteReview(userId, input.ID)
+}
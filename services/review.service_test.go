@@ -0,0 +1,69 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type mockReviewRepository struct{}
+
+func (m *mockReviewRepository) SaveReview(review *models.Review) (*models.Review, error) {
+	review.ID = 1
+	review.User = &models.User{Model: gorm.Model{ID: review.UserID}, FirstName: "Gwen", LastName: "Jones"}
+	return review, nil
+}
+
+func (m *mockReviewRepository) GetAllReviews(recipeId uint, pageSize, pageNumber int) ([]*models.Review, int64, error) {
+	reviews := []*models.Review{
+		{Model: gorm.Model{ID: 1}, UserID: 1, RecipeID: recipeId, Rating: 5, Text: "lovely"},
+		{Model: gorm.Model{ID: 2}, UserID: 3, RecipeID: recipeId, Rating: 3},
+	}
+	return reviews, 12, nil
+}
+
+func (m *mockReviewRepository) DeleteReview(userId, recipeId uint) error {
+	if userId == 1 && recipeId == 1 {
+		return nil
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func TestSaveReview(t *testing.T) {
+	reviewService := services.NewReviewService(&mockReviewRepository{}, &mockRecipeRepository{})
+	body := &dto.ReviewReqBody{Rating: 4, Text: "needs more mustard"}
+	reviewRes, err := reviewService.SaveReview(&dto.CommonIdPathUri{ID: 1}, body, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(4), reviewRes.Rating)
+	assert.Equal(t, uint(1), reviewRes.RecipeId)
+	assert.Equal(t, uint(1), reviewRes.AuthorId)
+	assert.Equal(t, "Gwen", reviewRes.AuthorName)
+	// test error: recipe not found
+	reviewRes, err = reviewService.SaveReview(&dto.CommonIdPathUri{ID: 2}, body, 1)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, reviewRes)
+}
+
+func TestGetAllReviews(t *testing.T) {
+	reviewService := services.NewReviewService(&mockReviewRepository{}, &mockRecipeRepository{})
+	query := &dto.CommonQueryPage{PageSize: 5, PageNumber: 1}
+	pageRes, err := reviewService.GetAllReviews(&dto.CommonIdPathUri{ID: 1}, query)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, pageRes.TotalNbResult)
+	assert.Equal(t, 3, pageRes.TotablNbPage)
+	assert.Len(t, pageRes.Items, 2)
+	// test error: recipe not found
+	_, err = reviewService.GetAllReviews(&dto.CommonIdPathUri{ID: 2}, query)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestDeleteReview(t *testing.T) {
+	reviewService := services.NewReviewService(&mockReviewRepository{}, &mockRecipeRepository{})
+	assert.NoError(t, reviewService.DeleteReview(&dto.CommonIdPathUri{ID: 1}, 1))
+	// test error: the user didn't review the recipe
+	assert.ErrorIs(t, reviewService.DeleteReview(&dto.CommonIdPathUri{ID: 1}, 3), gorm.ErrRecordNotFound)
+}
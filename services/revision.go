@@ -0,0 +1,113 @@
+// The package 'services' contains the business logic for handling route
+package services
+
+import (
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/models"
+)
+
+// The kinds of change of an ingredient line between two revisions
+const (
+	ingredientAdded    = "added"
+	ingredientRemoved  = "removed"
+	ingredientModified = "modified"
+)
+
+// diffRecipeRevisions lists the fields and the ingredient lines which are different between the two revisions.
+// The ingredient lines are matched by ingredient because a recipe uses an ingredient only once, the position
+// is the index of the line in the ingredient list.
+func diffRecipeRevisions(from *models.RecipeRevision, to *models.RecipeRevision) *dto.RecipeRevisionDiffResBody {
+	diff := &dto.RecipeRevisionDiffResBody{
+		RecipeId:    from.RecipeID,
+		From:        from.Number,
+		To:          to.Number,
+		Changes:     make([]*dto.RecipeFieldChangeResBody, 0),
+		Ingredients: make([]*dto.RecipeIngredientChangeResBody, 0),
+	}
+	diff.Changes = appendFieldChange(diff.Changes, "title", from.Title, to.Title)
+	diff.Changes = appendFieldChange(diff.Changes, "description", from.Description, to.Description)
+	diff.Changes = appendFieldChange(diff.Changes, "difficulty", from.Difficulty, to.Difficulty)
+	diff.Changes = appendFieldChange(diff.Changes, "servings", from.Servings, to.Servings)
+	diff.Changes = appendFieldChange(diff.Changes, "oven_temperature", optionalValue(from.OvenTemperature), optionalValue(to.OvenTemperature))
+	diff.Changes = appendFieldChange(diff.Changes, "oven_temperature_unit", from.OvenTemperatureUnit, to.OvenTemperatureUnit)
+
+	toPositions := make(map[uint]int, len(to.Ingredients))
+	for i, line := range to.Ingredients {
+		toPositions[line.IngredientID] = i
+	}
+	fromIngredients := make(map[uint]bool, len(from.Ingredients))
+	for i, fromLine := range from.Ingredients {
+		fromIngredients[fromLine.IngredientID] = true
+		position, ok := toPositions[fromLine.IngredientID]
+		if !ok {
+			diff.Ingredients = append(diff.Ingredients, &dto.RecipeIngredientChangeResBody{
+				IngredientId: fromLine.IngredientID,
+				Name:         fromLine.Name,
+				Change:       ingredientRemoved,
+			})
+			continue
+		}
+		toLine := to.Ingredients[position]
+		changes := make([]*dto.RecipeFieldChangeResBody, 0)
+		changes = appendFieldChange(changes, "position", i, position)
+		changes = appendFieldChange(changes, "quantity", fromLine.Quantity, toLine.Quantity)
+		changes = appendFieldChange(changes, "unit", fromLine.Unit, toLine.Unit)
+		changes = appendFieldChange(changes, "note", fromLine.Note, toLine.Note)
+		changes = appendFieldChange(changes, "optional", fromLine.Optional, toLine.Optional)
+		if len(changes) > 0 {
+			diff.Ingredients = append(diff.Ingredients, &dto.RecipeIngredientChangeResBody{
+				IngredientId: toLine.IngredientID,
+				Name:         toLine.Name,
+				Change:       ingredientModified,
+				Changes:      changes,
+			})
+		}
+	}
+	for _, toLine := range to.Ingredients {
+		if !fromIngredients[toLine.IngredientID] {
+			diff.Ingredients = append(diff.Ingredients, &dto.RecipeIngredientChangeResBody{
+				IngredientId: toLine.IngredientID,
+				Name:         toLine.Name,
+				Change:       ingredientAdded,
+			})
+		}
+	}
+	return diff
+}
+
+// appendFieldChange adds the change of the field to the changes when its two values are different
+func appendFieldChange(changes []*dto.RecipeFieldChangeResBody, field string, from interface{}, to interface{}) []*dto.RecipeFieldChangeResBody {
+	if from == to {
+		return changes
+	}
+	return append(changes, &dto.RecipeFieldChangeResBody{Field: field, From: from, To: to})
+}
+
+// optionalValue returns the value of the optional number, nil when it's not set
+func optionalValue(value *float64) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+// applyRecipeRevision replaces the fields and the ingredient lines of the recipe with the ones of the revision
+func applyRecipeRevision(recipe *models.Recipe, revision *models.RecipeRevision) {
+	recipe.Title = revision.Title
+	recipe.Description = revision.Description
+	recipe.Difficulty = revision.Difficulty
+	recipe.Servings = revision.Servings
+	recipe.OvenTemperature = revision.OvenTemperature
+	recipe.OvenTemperatureUnit = revision.OvenTemperatureUnit
+	recipe.Ingredients = make([]*models.RecipeIngredient, len(revision.Ingredients))
+	for i, line := range revision.Ingredients {
+		recipe.Ingredients[i] = &models.RecipeIngredient{
+			IngredientID: line.IngredientID,
+			Position:     uint(i),
+			Quantity:     line.Quantity,
+			Unit:         line.Unit,
+			Note:         line.Note,
+			Optional:     line.Optional,
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// The package 'services' contains the business logic for handling route
+package services
+
+import (
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/units"
+)
+
+// unitsOriginal is the units preference keeping the quantities in the units entered by the author
+const unitsOriginal = "original"
+
+// adaptRecipeQuantities scales the ingredient quantities of the recipe to the number of servings and converts them
+// to the measurement system asked by the query. The quantities are rounded only when they are modified,
+// the quantities of the substitutes follow the adapted quantities.
+func adaptRecipeQuantities(recipe *dto.RecipeResBody, query *dto.RecipeQuery) {
+	scaled := scaleRecipe(recipe, query.Servings)
+	converted := convertRecipeUnits(recipe, query.Units)
+	if scaled || converted {
+		for _, line := range recipe.Ingredients {
+			line.Quantity = units.Round(line.Quantity, line.Unit)
+			line.UpdateSubstitutes()
+		}
+	}
+}
+
+// scaleRecipe scales the ingredient quantities, the nutrition totals and the total cost of the recipe to make the given number of servings,
+// the values per serving are unchanged. It returns false when the quantities are unchanged
+func scaleRecipe(recipe *dto.RecipeResBody, servings uint) bool {
+	if servings == 0 || servings == recipe.Servings || recipe.Servings == 0 {
+		return false
+	}
+	factor := float64(servings) / float64(recipe.Servings)
+	for _, line := range recipe.Ingredients {
+		line.Quantity = line.Quantity * factor
+	}
+	if recipe.Nutrition != nil {
+		recipe.Nutrition.Total = scaleNutrition(recipe.Nutrition.Total, factor)
+	}
+	if recipe.Cost != nil {
+		recipe.Cost.Total = roundPrice(recipe.Cost.Total * factor)
+	}
+	recipe.OriginalServings = recipe.Servings
+	recipe.Servings = servings
+	return true
+}
+
+// convertRecipeUnits expresses the ingredient quantities and the oven temperature of the recipe in the measurement system,
+// the ingredient density is used to weigh the cup measures. It returns false when the original units are kept.
+func convertRecipeUnits(recipe *dto.RecipeResBody, system string) bool {
+	if system == "" || system == unitsOriginal {
+		return false
+	}
+	target := units.System(system)
+	for _, line := range recipe.Ingredients {
+		line.Quantity, line.Unit = units.ToSystem(line.Quantity, line.Unit, target, line.Density)
+	}
+	if recipe.OvenTemperature != nil {
+		convertOvenTemperature(recipe, target)
+	}
+	return true
+}
+
+// convertOvenTemperature expresses the oven temperature in Celsius for the metric system and in Fahrenheit for the imperial one,
+// the gas mark is also given for the imperial system
+func convertOvenTemperature(recipe *dto.RecipeResBody, system units.System) {
+	from := recipe.OvenTemperatureUnit
+	if from == "" {
+		from = "C"
+	}
+	to := "C"
+	if system == units.Imperial {
+		to = "F"
+		gasMark, err := units.ConvertTemperature(*recipe.OvenTemperature, from, "gas")
+		if err == nil {
+			recipe.OvenGasMark = &gasMark
+		}
+	}
+	temperature, err := units.ConvertTemperature(*recipe.OvenTemperature, from, to)
+	if err != nil {
+		return
+	}
+	temperature = units.RoundTemperature(temperature, to)
+	recipe.OvenTemperature = &temperature
+	recipe.OvenTemperatureUnit = to
+}
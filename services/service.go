@@ -0,0 +1,73 @@
+// The package 'services' contains the business logic for handling route
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"math"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/media"
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/repositories"
+	"gorm.io/gorm"
+)
+
+// ErrForbidden is returned when the user tries to modify a resource they don't own
+var ErrForbidden = errors.New("only the author or an administrator can modify this resource")
+
+// newPageRespBody builds the page response body from the page query, the total number of results and the page items
+func newPageRespBody(input *dto.CommonQueryPage, totalResult int64, items []interface{}) *dto.CommonPageRespBody {
+	totalNbPage := 1
+	if input.PageSize > 0 {
+		totalNbPage = int(math.Ceil(float64(totalResult) / float64(input.PageSize)))
+	}
+	return &dto.CommonPageRespBody{
+		CommonQueryPage: *input,
+		TotalNbResult:   int(totalResult),
+		TotablNbPage:    totalNbPage,
+		Items:           items,
+	}
+}
+
+// completeRecipeRes sets the values of the recipe response body computed from the recipe model,
+// the nutrition facts and the cost of the recipe and the URLs of its images
+func completeRecipeRes(recipeRes *dto.RecipeResBody, model *models.Recipe, storage media.Storage) {
+	recipeRes.Nutrition = recipeNutrition(model)
+	recipeRes.Cost = recipeCost(model, latestRecipePrices(model))
+	recipeRes.SetImageUrls(storage)
+}
+
+// checkAuthorOrAdmin returns ErrForbidden when the user is neither the author of the resource nor an administrator
+func checkAuthorOrAdmin(userRepo repositories.UserRepository, authorId uint, userId uint) error {
+	if authorId == userId {
+		return nil
+	}
+	return checkAdmin(userRepo, userId)
+}
+
+// checkAdmin returns ErrForbidden when the user isn't an administrator
+func checkAdmin(userRepo repositories.UserRepository, userId uint) error {
+	user, err := userRepo.GetUserById(userId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrForbidden
+	}
+	if err != nil {
+		return err
+	}
+	if !user.IsAdmin {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// newSecretToken returns a random token of 64 hexadecimal characters used to build the secret links
+func newSecretToken() (string, error) {
+	buf := make([]byte, 32)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
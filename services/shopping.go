@@ -0,0 +1,143 @@
+// The package 'services' contains the business logic for handling route
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/units"
+)
+
+// The larger metric unit used when a merged quantity reaches 1000 of the base unit
+var largerBaseUnits = map[string]string{"g": "kg", "ml": "l"}
+
+// shoppingKey identifies the lines which can be summed: the same ingredient measured in the same base unit
+type shoppingKey struct {
+	ingredientId uint
+	unit         string
+}
+
+// shoppingLine is an item being merged, the quantity is expressed in the base unit of the key
+type shoppingLine struct {
+	item    *models.ShoppingListItem
+	density float64
+	unit    string // the unit of the recipe lines, it's kept when all the merged lines use it
+	mixed   bool   // the merged lines use several units
+}
+
+// shoppingMerger sums the quantities of the recipe lines using the same ingredient in compatible units
+type shoppingMerger struct {
+	lines map[shoppingKey]*shoppingLine
+	order []shoppingKey
+}
+
+// newShoppingMerger creates an empty shoppingMerger
+func newShoppingMerger() *shoppingMerger {
+	return &shoppingMerger{lines: make(map[shoppingKey]*shoppingLine)}
+}
+
+// add adds the quantity of the recipe line multiplied by the factor.
+// The volumes are weighed when the density of the ingredient is known, the unknown units are only summed with the same unit.
+func (m *shoppingMerger) add(line *models.RecipeIngredient, factor float64) {
+	density := line.Ingredient.Density
+	quantity := line.Quantity * factor
+	unit := strings.ToLower(strings.TrimSpace(line.Unit))
+	baseUnit := unit
+	if u, ok := units.Lookup(unit); ok {
+		unit = u.Symbol
+		if base, symbol, err := units.ToBase(quantity, unit); err == nil {
+			quantity, baseUnit = base, symbol
+			if baseUnit == "ml" && density > 0 {
+				quantity, baseUnit = quantity*density, "g"
+			}
+		}
+	}
+	key := shoppingKey{ingredientId: line.IngredientID, unit: baseUnit}
+	merged, ok := m.lines[key]
+	if !ok {
+		merged = &shoppingLine{
+			item: &models.ShoppingListItem{
+				IngredientID: line.IngredientID,
+				Ingredient:   line.Ingredient,
+				Aisle:        line.Ingredient.Type,
+				Unit:         baseUnit,
+			},
+			density: density,
+			unit:    unit,
+		}
+		m.lines[key] = merged
+		m.order = append(m.order, key)
+	}
+	merged.mixed = merged.mixed || merged.unit != unit
+	merged.item.Quantity += quantity
+}
+
+// items returns the merged items in the order of their first line. The quantity is expressed in the unit of the recipe lines
+// when they all use the same one, otherwise in the base unit, and from 1000 the grams and the millilitres become kilograms and litres.
+// The quantity is rounded for its unit.
+func (m *shoppingMerger) items() []*models.ShoppingListItem {
+	items := make([]*models.ShoppingListItem, len(m.order))
+	for i, key := range m.order {
+		line := m.lines[key]
+		item := line.item
+		if !line.mixed && line.unit != item.Unit {
+			if quantity, err := units.Convert(item.Quantity, item.Unit, line.unit, line.density); err == nil {
+				item.Quantity, item.Unit = quantity, line.unit
+			}
+		} else if larger, ok := largerBaseUnits[item.Unit]; ok && item.Quantity >= 1000 {
+			item.Quantity, item.Unit = item.Quantity/1000, larger
+		}
+		if item.Unit == "piece" {
+			item.Unit = ""
+		}
+		item.Quantity = units.Round(item.Quantity, item.Unit)
+		items[i] = item
+	}
+	return items
+}
+
+// exportShoppingList formats the shopping list as plain text or Markdown, the items are grouped by aisle
+// and the ticked off items are marked with a checked box
+func exportShoppingList(list *dto.ShoppingListResBody, format string) string {
+	markdown := format == "markdown"
+	var builder strings.Builder
+	if markdown {
+		fmt.Fprintf(&builder, "# %s\n", list.Title)
+	} else {
+		fmt.Fprintf(&builder, "%s\n", list.Title)
+	}
+	for i, item := range list.Items {
+		if i == 0 || item.Aisle != list.Items[i-1].Aisle {
+			if markdown {
+				fmt.Fprintf(&builder, "\n## %s\n\n", item.Aisle)
+			} else {
+				fmt.Fprintf(&builder, "\n%s\n", item.Aisle)
+			}
+		}
+		box := "[ ]"
+		if item.Checked {
+			box = "[x]"
+		}
+		if markdown {
+			fmt.Fprintf(&builder, "- %s %s\n", box, describeShoppingItem(item))
+		} else {
+			fmt.Fprintf(&builder, "  %s %s\n", box, describeShoppingItem(item))
+		}
+	}
+	return builder.String()
+}
+
+// describeShoppingItem returns the name of the ingredient followed by the quantity to buy when it's specified
+func describeShoppingItem(item *dto.ShoppingListItemResBody) string {
+	if item.Quantity == 0 {
+		return item.Name
+	}
+	quantity := strconv.FormatFloat(item.Quantity, 'f', -1, 64)
+	if item.Unit == "" {
+		return fmt.Sprintf("%s: %s", item.Name, quantity)
+	}
+	return fmt.Sprintf("%s: %s %s", item.Name, quantity, item.Unit)
+}
@@ -0,0 +1,155 @@
+// The package 'services' contains the business logic for handling route
+package services
+
+import (
+	"sort"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/repositories"
+	"go.uber.org/zap"
+)
+
+// The title of the shopping lists generated without title
+const defaultShoppingListTitle = "Shopping list"
+
+// ShoppingListService is an interface for defining the methods to generate and manage the shopping lists of the users
+type ShoppingListService interface {
+	GenerateShoppingList(body *dto.ShoppingListReqBody) (*dto.ShoppingListResBody, error)
+	CreateShoppingList(body *dto.ShoppingListReqBody, userId uint) (*dto.ShoppingListResBody, error)
+	GetAllShoppingLists(input *dto.CommonQueryPage, userId uint) (*dto.CommonPageRespBody, error)
+	GetShoppingListById(input *dto.CommonIdPathUri, userId uint) (*dto.ShoppingListResBody, error)
+	ExportShoppingList(input *dto.CommonIdPathUri, format string, userId uint) (string, error)
+	DeleteShoppingListById(input *dto.CommonIdPathUri, userId uint) error
+	CheckShoppingListItem(input *dto.ShoppingListItemPathUri, body *dto.ShoppingListItemReqBody, userId uint) (*dto.ShoppingListItemResBody, error)
+}
+
+// shoppingListService is an implementation of the ShoppingListService interface
+type shoppingListService struct {
+	repo       repositories.ShoppingListRepository
+	recipeRepo repositories.RecipeRepository
+	logger     *zap.Logger
+}
+
+// NewShoppingListService creates a new ShoppingListService instance, the recipe repository is used to read the ingredients of the recipes
+func NewShoppingListService(repo repositories.ShoppingListRepository, recipeRepo repositories.RecipeRepository) ShoppingListService {
+	return &shoppingListService{
+		repo:       repo,
+		recipeRepo: recipeRepo,
+		logger:     zap.L(),
+	}
+}
+
+// GenerateShoppingList returns the shopping list of the recipes without saving it
+func (s *shoppingListService) GenerateShoppingList(body *dto.ShoppingListReqBody) (*dto.ShoppingListResBody, error) {
+	list, err := s.buildShoppingList(body)
+	if err != nil {
+		return nil, err
+	}
+	listRes := &dto.ShoppingListResBody{}
+	listRes.ConvertFromModel(list)
+	return listRes, nil
+}
+
+// CreateShoppingList generates the shopping list of the recipes and saves it for the user
+func (s *shoppingListService) CreateShoppingList(body *dto.ShoppingListReqBody, userId uint) (*dto.ShoppingListResBody, error) {
+	list, err := s.buildShoppingList(body)
+	if err != nil {
+		return nil, err
+	}
+	list.UserID = userId
+	list, err = s.repo.CreateShoppingList(list)
+	if err != nil {
+		return nil, err
+	}
+	listRes := &dto.ShoppingListResBody{}
+	listRes.ConvertFromModel(list)
+	return listRes, nil
+}
+
+// GetAllShoppingLists returns a page of the shopping lists of the user
+func (s *shoppingListService) GetAllShoppingLists(input *dto.CommonQueryPage, userId uint) (*dto.CommonPageRespBody, error) {
+	lists, totalLists, err := s.repo.GetAllShoppingLists(userId, input.PageSize, input.PageNumber)
+	if err != nil {
+		return nil, err
+	}
+	listsRes := make([]interface{}, len(lists))
+	for i, v := range lists {
+		res := dto.ShoppingListResBody{}
+		res.ConvertFromModel(v)
+		listsRes[i] = res
+	}
+	return newPageRespBody(input, totalLists, listsRes), nil
+}
+
+// GetShoppingListById returns a shopping list of the user by ID
+func (s *shoppingListService) GetShoppingListById(input *dto.CommonIdPathUri, userId uint) (*dto.ShoppingListResBody, error) {
+	list, err := s.repo.GetShoppingListById(userId, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	listRes := &dto.ShoppingListResBody{}
+	listRes.ConvertFromModel(list)
+	return listRes, nil
+}
+
+// ExportShoppingList returns a shopping list of the user formatted as plain text or Markdown
+func (s *shoppingListService) ExportShoppingList(input *dto.CommonIdPathUri, format string, userId uint) (string, error) {
+	listRes, err := s.GetShoppingListById(input, userId)
+	if err != nil {
+		return "", err
+	}
+	return exportShoppingList(listRes, format), nil
+}
+
+// DeleteShoppingListById deletes a shopping list of the user by ID
+func (s *shoppingListService) DeleteShoppingListById(input *dto.CommonIdPathUri, userId uint) error {
+	return s.repo.DeleteShoppingListById(userId, input.ID)
+}
+
+// CheckShoppingListItem ticks off or unticks an item of a shopping list of the user
+func (s *shoppingListService) CheckShoppingListItem(input *dto.ShoppingListItemPathUri, body *dto.ShoppingListItemReqBody, userId uint) (*dto.ShoppingListItemResBody, error) {
+	item, err := s.repo.CheckShoppingListItem(userId, input.ID, input.ItemID, *body.Checked)
+	if err != nil {
+		return nil, err
+	}
+	itemRes := &dto.ShoppingListItemResBody{}
+	itemRes.ConvertFromModel(item)
+	return itemRes, nil
+}
+
+// buildShoppingList merges the ingredient lines of the recipes scaled to their servings,
+// the items are sorted by aisle then by ingredient name
+func (s *shoppingListService) buildShoppingList(body *dto.ShoppingListReqBody) (*models.ShoppingList, error) {
+	merger := newShoppingMerger()
+	for _, r := range body.Recipes {
+		recipe, err := s.recipeRepo.GetRecipeById(r.RecipeId)
+		if err != nil {
+			return nil, err
+		}
+		factor := 1.0
+		if r.Servings > 0 && recipe.Servings > 0 {
+			factor = float64(r.Servings) / float64(recipe.Servings)
+		}
+		for _, line := range recipe.Ingredients {
+			if line.Optional && !body.IncludeOptional {
+				continue
+			}
+			merger.add(line, factor)
+		}
+	}
+	list := &models.ShoppingList{
+		Title: body.Title,
+		Items: merger.items(),
+	}
+	if list.Title == "" {
+		list.Title = defaultShoppingListTitle
+	}
+	sort.SliceStable(list.Items, func(i, j int) bool {
+		if list.Items[i].Aisle != list.Items[j].Aisle {
+			return list.Items[i].Aisle < list.Items[j].Aisle
+		}
+		return list.Items[i].Ingredient.Name < list.Items[j].Ingredient.Name
+	})
+	return list, nil
+}
@@ -0,0 +1,152 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type mockShoppingListRepository struct{}
+
+func (m *mockShoppingListRepository) CreateShoppingList(list *models.ShoppingList) (*models.ShoppingList, error) {
+	list.ID = 1
+	for i, item := range list.Items {
+		item.ID = uint(i + 1)
+		item.ShoppingListID = list.ID
+	}
+	return list, nil
+}
+
+func (m *mockShoppingListRepository) GetAllShoppingLists(userId uint, pageSize, pageNumber int) ([]*models.ShoppingList, int64, error) {
+	return make([]*models.ShoppingList, 0), 0, nil
+}
+
+func (m *mockShoppingListRepository) GetShoppingListById(userId, listId uint) (*models.ShoppingList, error) {
+	if userId == 1 && listId == 1 {
+		return &models.ShoppingList{
+			Model:  gorm.Model{ID: 1},
+			Title:  "rarebit party",
+			UserID: 1,
+			Items: []*models.ShoppingListItem{{
+				Model:        gorm.Model{ID: 1},
+				IngredientID: 1,
+				Ingredient:   &models.Ingredient{Model: gorm.Model{ID: 1}, Name: "cheddar", Type: "cheese"},
+				Aisle:        "cheese",
+				Quantity:     1.2,
+				Unit:         "kg",
+				Checked:      true,
+			}, {
+				Model:        gorm.Model{ID: 2},
+				IngredientID: 2,
+				Ingredient:   &models.Ingredient{Model: gorm.Model{ID: 2}, Name: "egg", Type: "egg"},
+				Aisle:        "egg",
+				Quantity:     6,
+			}, {
+				Model:        gorm.Model{ID: 3},
+				IngredientID: 4,
+				Ingredient:   &models.Ingredient{Model: gorm.Model{ID: 4}, Name: "salt", Type: "egg"},
+				Aisle:        "egg",
+			}},
+		}, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockShoppingListRepository) DeleteShoppingListById(userId, listId uint) error {
+	_, err := m.GetShoppingListById(userId, listId)
+	return err
+}
+
+func (m *mockShoppingListRepository) CheckShoppingListItem(userId, listId, itemId uint, checked bool) (*models.ShoppingListItem, error) {
+	list, err := m.GetShoppingListById(userId, listId)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range list.Items {
+		if item.ID == itemId {
+			item.Checked = checked
+			return item, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestGenerateShoppingList(t *testing.T) {
+	shoppingListService := services.NewShoppingListService(&mockShoppingListRepository{}, &mockRecipeRepository{})
+	// test happy path: the same recipe is cooked for 4 and 8 servings, the quantities are summed
+	body := &dto.ShoppingListReqBody{
+		Recipes: []dto.ShoppingListRecipeReqBody{{RecipeId: 1}, {RecipeId: 1, Servings: 8}},
+	}
+	listRes, err := shoppingListService.GenerateShoppingList(body)
+	assert.NoError(t, err)
+	assert.Equal(t, "Shopping list", listRes.Title)
+	assert.Len(t, listRes.Items, 3)
+	assert.Equal(t, "cheese", listRes.Items[0].Aisle)
+	assert.Equal(t, 600.0, listRes.Items[0].Quantity)
+	assert.Equal(t, "g", listRes.Items[0].Unit)
+	assert.Equal(t, "condiment", listRes.Items[1].Aisle)
+	assert.Equal(t, 3.0, listRes.Items[1].Quantity)
+	assert.Equal(t, "tsp", listRes.Items[1].Unit)
+	assert.Equal(t, "egg", listRes.Items[2].Name)
+	assert.Equal(t, 6.0, listRes.Items[2].Quantity)
+	assert.Equal(t, "", listRes.Items[2].Unit)
+	// test happy path: the merged quantity is expressed in kilograms
+	body.Recipes = append(body.Recipes, dto.ShoppingListRecipeReqBody{RecipeId: 1, Servings: 10})
+	listRes, err = shoppingListService.GenerateShoppingList(body)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.1, listRes.Items[0].Quantity)
+	assert.Equal(t, "kg", listRes.Items[0].Unit)
+	// test error: recipe not found
+	body.Recipes = []dto.ShoppingListRecipeReqBody{{RecipeId: 2}}
+	listRes, err = shoppingListService.GenerateShoppingList(body)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, listRes)
+}
+
+func TestCreateShoppingList(t *testing.T) {
+	shoppingListService := services.NewShoppingListService(&mockShoppingListRepository{}, &mockRecipeRepository{})
+	body := &dto.ShoppingListReqBody{
+		Title:   "rarebit party",
+		Recipes: []dto.ShoppingListRecipeReqBody{{RecipeId: 1, Servings: 2}},
+	}
+	listRes, err := shoppingListService.CreateShoppingList(body, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), listRes.ID)
+	assert.Equal(t, "rarebit party", listRes.Title)
+	assert.Equal(t, 100.0, listRes.Items[0].Quantity)
+	assert.Equal(t, 0.5, listRes.Items[1].Quantity)
+	assert.Equal(t, 1.0, listRes.Items[2].Quantity)
+}
+
+func TestExportShoppingList(t *testing.T) {
+	shoppingListService := services.NewShoppingListService(&mockShoppingListRepository{}, &mockRecipeRepository{})
+	input := &dto.CommonIdPathUri{ID: 1}
+	// test happy path: plain text
+	export, err := shoppingListService.ExportShoppingList(input, "text", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "rarebit party\n\ncheese\n  [x] cheddar: 1.2 kg\n\negg\n  [ ] egg: 6\n  [ ] salt\n", export)
+	// test happy path: Markdown
+	export, err = shoppingListService.ExportShoppingList(input, "markdown", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "# rarebit party\n\n## cheese\n\n- [x] cheddar: 1.2 kg\n\n## egg\n\n- [ ] egg: 6\n- [ ] salt\n", export)
+	// test error: the list belongs to another user
+	_, err = shoppingListService.ExportShoppingList(input, "text", 2)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestCheckShoppingListItem(t *testing.T) {
+	shoppingListService := services.NewShoppingListService(&mockShoppingListRepository{}, &mockRecipeRepository{})
+	checked := true
+	body := &dto.ShoppingListItemReqBody{Checked: &checked}
+	itemRes, err := shoppingListService.CheckShoppingListItem(&dto.ShoppingListItemPathUri{ID: 1, ItemID: 2}, body, 1)
+	assert.NoError(t, err)
+	assert.True(t, itemRes.Checked)
+	assert.Equal(t, "egg", itemRes.Name)
+	// test error: item not found
+	_, err = shoppingListService.CheckShoppingListItem(&dto.ShoppingListItemPathUri{ID: 1, ItemID: 5}, body, 1)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
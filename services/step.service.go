@@ -0,0 +1,114 @@
+// The package 'services' contains the business logic for handling route
+package services
+
+import (
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/repositories"
+	"go.uber.org/zap"
+)
+
+// StepService is an interface for defining the methods to manage the preparation steps of the recipes
+type StepService interface {
+	GetAllSteps(input *dto.CommonIdPathUri) ([]*dto.RecipeStepResBody, error)
+	CreateStep(input *dto.CommonIdPathUri, body *dto.RecipeStepReqBody, userId uint) (*dto.RecipeStepResBody, error)
+	UpdateStep(input *dto.RecipeStepPathUri, body *dto.RecipeStepReqBody, userId uint) (*dto.RecipeStepResBody, error)
+	DeleteStepById(input *dto.RecipeStepPathUri, userId uint) error
+	ReorderSteps(input *dto.CommonIdPathUri, body *dto.RecipeStepsOrderReqBody, userId uint) ([]*dto.RecipeStepResBody, error)
+}
+
+// stepService is an implementation of the StepService interface
+type stepService struct {
+	repo       repositories.StepRepository
+	recipeRepo repositories.RecipeRepository
+	userRepo   repositories.UserRepository
+	logger     *zap.Logger
+}
+
+// NewStepService creates a new StepService instance, the recipe and the user repositories are used to check the user permissions
+func NewStepService(repo repositories.StepRepository, recipeRepo repositories.RecipeRepository, userRepo repositories.UserRepository) StepService {
+	return &stepService{
+		repo:       repo,
+		recipeRepo: recipeRepo,
+		userRepo:   userRepo,
+		logger:     zap.L(),
+	}
+}
+
+// GetAllSteps returns the steps of the recipe in their order
+func (s *stepService) GetAllSteps(input *dto.CommonIdPathUri) ([]*dto.RecipeStepResBody, error) {
+	_, err := s.recipeRepo.GetRecipeById(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	steps, err := s.repo.GetAllSteps(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ConvertStepsFromModel(steps), nil
+}
+
+// CreateStep adds a step to the recipe, only the author of the recipe or an administrator can add it
+func (s *stepService) CreateStep(input *dto.CommonIdPathUri, body *dto.RecipeStepReqBody, userId uint) (*dto.RecipeStepResBody, error) {
+	err := s.checkRecipeAuthor(input.ID, userId)
+	if err != nil {
+		return nil, err
+	}
+	step, err := s.repo.CreateStep(body.ConvertToModel(input.ID), body.IngredientsId)
+	if err != nil {
+		return nil, err
+	}
+	stepRes := &dto.RecipeStepResBody{}
+	stepRes.ConvertFromModel(step)
+	return stepRes, nil
+}
+
+// UpdateStep replaces the text, the duration and the ingredients of the step, only the author of the recipe or an administrator can update it
+func (s *stepService) UpdateStep(input *dto.RecipeStepPathUri, body *dto.RecipeStepReqBody, userId uint) (*dto.RecipeStepResBody, error) {
+	err := s.checkRecipeAuthor(input.ID, userId)
+	if err != nil {
+		return nil, err
+	}
+	step, err := s.repo.GetStepById(input.ID, input.StepID)
+	if err != nil {
+		return nil, err
+	}
+	body.ApplyToModel(step)
+	step, err = s.repo.UpdateStep(step, body.IngredientsId)
+	if err != nil {
+		return nil, err
+	}
+	stepRes := &dto.RecipeStepResBody{}
+	stepRes.ConvertFromModel(step)
+	return stepRes, nil
+}
+
+// DeleteStepById deletes a step of the recipe, only the author of the recipe or an administrator can delete it
+func (s *stepService) DeleteStepById(input *dto.RecipeStepPathUri, userId uint) error {
+	err := s.checkRecipeAuthor(input.ID, userId)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteStepById(input.ID, input.StepID)
+}
+
+// ReorderSteps changes the order of the steps of the recipe, only the author of the recipe or an administrator can reorder them
+func (s *stepService) ReorderSteps(input *dto.CommonIdPathUri, body *dto.RecipeStepsOrderReqBody, userId uint) ([]*dto.RecipeStepResBody, error) {
+	err := s.checkRecipeAuthor(input.ID, userId)
+	if err != nil {
+		return nil, err
+	}
+	steps, err := s.repo.ReorderSteps(input.ID, body.StepsId)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ConvertStepsFromModel(steps), nil
+}
+
+// checkRecipeAuthor returns an error when the recipe doesn't exist or when the user isn't allowed to modify it
+func (s *stepService) checkRecipeAuthor(recipeId uint, userId uint) error {
+	recipe, err := s.recipeRepo.GetRecipeById(recipeId)
+	if err != nil {
+		return err
+	}
+	return checkAuthorOrAdmin(s.userRepo, uint(recipe.AuthorID), userId)
+}
@@ -0,0 +1,162 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/repositories"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type mockStepRepository struct{}
+
+func (m *mockStepRepository) GetAllSteps(recipeId uint) ([]*models.RecipeStep, error) {
+	return []*models.RecipeStep{
+		{Model: gorm.Model{ID: 1}, RecipeID: recipeId, Position: 1, Text: "toast the bread"},
+		{Model: gorm.Model{ID: 2}, RecipeID: recipeId, Position: 2, Text: "melt the cheese"},
+	}, nil
+}
+
+func (m *mockStepRepository) CreateStep(step *models.RecipeStep, ingredientsId []uint) (*models.RecipeStep, error) {
+	for _, id := range ingredientsId {
+		if id != 1 {
+			return nil, repositories.ErrStepIngredientNotAcceptable
+		}
+		step.Ingredients = append(step.Ingredients, &models.Ingredient{Model: gorm.Model{ID: 1}, Name: "cheddar"})
+	}
+	step.ID = 3
+	if step.Position == 0 {
+		step.Position = 3
+	}
+	return step, nil
+}
+
+func (m *mockStepRepository) GetStepById(recipeId, stepId uint) (*models.RecipeStep, error) {
+	if recipeId != 1 || stepId != 1 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &models.RecipeStep{Model: gorm.Model{ID: 1}, RecipeID: 1, Position: 1, Text: "toast the bread"}, nil
+}
+
+func (m *mockStepRepository) UpdateStep(step *models.RecipeStep, ingredientsId []uint) (*models.RecipeStep, error) {
+	return step, nil
+}
+
+func (m *mockStepRepository) DeleteStepById(recipeId, stepId uint) error {
+	_, err := m.GetStepById(recipeId, stepId)
+	return err
+}
+
+func (m *mockStepRepository) ReorderSteps(recipeId uint, stepsId []uint) ([]*models.RecipeStep, error) {
+	if len(stepsId) != 2 || stepsId[0] == stepsId[1] || stepsId[0] > 2 || stepsId[1] > 2 {
+		return nil, repositories.ErrStepsOrderNotAcceptable
+	}
+	steps, _ := m.GetAllSteps(recipeId)
+	ordered := make([]*models.RecipeStep, len(stepsId))
+	for i, id := range stepsId {
+		ordered[i] = steps[id-1]
+		ordered[i].Position = uint(i + 1)
+	}
+	return ordered, nil
+}
+
+func TestGetAllSteps(t *testing.T) {
+	stepService := services.NewStepService(&mockStepRepository{}, &mockRecipeRepository{}, &mockUserRepository{})
+	steps, err := stepService.GetAllSteps(&dto.CommonIdPathUri{ID: 1})
+	assert.NoError(t, err)
+	assert.Len(t, steps, 2)
+	assert.Equal(t, "toast the bread", steps[0].Text)
+	// test error: recipe not found
+	steps, err = stepService.GetAllSteps(&dto.CommonIdPathUri{ID: 2})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, steps)
+}
+
+func TestCreateStep(t *testing.T) {
+	stepService := services.NewStepService(&mockStepRepository{}, &mockRecipeRepository{}, &mockUserRepository{})
+	input := &dto.CommonIdPathUri{ID: 1}
+	body := &dto.RecipeStepReqBody{Text: "grill the toasts", IngredientsId: []uint{1}}
+	// test happy path: the author appends a step
+	step, err := stepService.CreateStep(input, body, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), step.ID)
+	assert.Equal(t, uint(3), step.Position)
+	assert.Equal(t, "grill the toasts", step.Text)
+	assert.Len(t, step.Ingredients, 1)
+	// test happy path: an administrator adds a step
+	_, err = stepService.CreateStep(input, body, 3)
+	assert.NoError(t, err)
+	// test error: another user adds a step
+	step, err = stepService.CreateStep(input, body, 2)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	assert.Nil(t, step)
+	// test error: recipe not found
+	_, err = stepService.CreateStep(&dto.CommonIdPathUri{ID: 2}, body, 1)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	// test error: the step uses an ingredient which isn't in the recipe
+	_, err = stepService.CreateStep(input, &dto.RecipeStepReqBody{Text: "add the ale", IngredientsId: []uint{9}}, 1)
+	assert.ErrorIs(t, err, repositories.ErrStepIngredientNotAcceptable)
+}
+
+func TestUpdateStep(t *testing.T) {
+	stepService := services.NewStepService(&mockStepRepository{}, &mockRecipeRepository{}, &mockUserRepository{})
+	duration := uint(5)
+	body := &dto.RecipeStepReqBody{Text: "toast the bread on both sides", Duration: &duration}
+	// test happy path: the author updates the step
+	step, err := stepService.UpdateStep(&dto.RecipeStepPathUri{ID: 1, StepID: 1}, body, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "toast the bread on both sides", step.Text)
+	assert.Equal(t, &duration, step.Duration)
+	assert.Equal(t, uint(1), step.Position)
+	// test error: another user updates the step
+	_, err = stepService.UpdateStep(&dto.RecipeStepPathUri{ID: 1, StepID: 1}, body, 2)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	// test error: recipe not found
+	_, err = stepService.UpdateStep(&dto.RecipeStepPathUri{ID: 2, StepID: 1}, body, 1)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	// test error: step not found
+	step, err = stepService.UpdateStep(&dto.RecipeStepPathUri{ID: 1, StepID: 7}, body, 1)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, step)
+}
+
+func TestDeleteStepById(t *testing.T) {
+	stepService := services.NewStepService(&mockStepRepository{}, &mockRecipeRepository{}, &mockUserRepository{})
+	// test happy path: the author and an administrator can delete the step
+	assert.NoError(t, stepService.DeleteStepById(&dto.RecipeStepPathUri{ID: 1, StepID: 1}, 1))
+	assert.NoError(t, stepService.DeleteStepById(&dto.RecipeStepPathUri{ID: 1, StepID: 1}, 3))
+	// test error: another user deletes the step
+	assert.ErrorIs(t, stepService.DeleteStepById(&dto.RecipeStepPathUri{ID: 1, StepID: 1}, 2), services.ErrForbidden)
+	// test error: recipe not found
+	assert.ErrorIs(t, stepService.DeleteStepById(&dto.RecipeStepPathUri{ID: 2, StepID: 1}, 1), gorm.ErrRecordNotFound)
+	// test error: step not found
+	assert.ErrorIs(t, stepService.DeleteStepById(&dto.RecipeStepPathUri{ID: 1, StepID: 7}, 1), gorm.ErrRecordNotFound)
+}
+
+func TestReorderSteps(t *testing.T) {
+	stepService := services.NewStepService(&mockStepRepository{}, &mockRecipeRepository{}, &mockUserRepository{})
+	input := &dto.CommonIdPathUri{ID: 1}
+	// test happy path: the author swaps the steps
+	steps, err := stepService.ReorderSteps(input, &dto.RecipeStepsOrderReqBody{StepsId: []uint{2, 1}}, 1)
+	assert.NoError(t, err)
+	assert.Len(t, steps, 2)
+	assert.Equal(t, uint(2), steps[0].ID)
+	assert.Equal(t, uint(1), steps[0].Position)
+	assert.Equal(t, uint(1), steps[1].ID)
+	assert.Equal(t, uint(2), steps[1].Position)
+	// test error: another user reorders the steps
+	_, err = stepService.ReorderSteps(input, &dto.RecipeStepsOrderReqBody{StepsId: []uint{2, 1}}, 2)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	// test error: recipe not found
+	_, err = stepService.ReorderSteps(&dto.CommonIdPathUri{ID: 2}, &dto.RecipeStepsOrderReqBody{StepsId: []uint{2, 1}}, 1)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	// test error: the new order doesn't match the steps of the recipe
+	steps, err = stepService.ReorderSteps(input, &dto.RecipeStepsOrderReqBody{StepsId: []uint{1}}, 1)
+	assert.ErrorIs(t, err, repositories.ErrStepsOrderNotAcceptable)
+	assert.Nil(t, steps)
+	_, err = stepService.ReorderSteps(input, &dto.RecipeStepsOrderReqBody{StepsId: []uint{1, 1}}, 1)
+	assert.ErrorIs(t, err, repositories.ErrStepsOrderNotAcceptable)
+}
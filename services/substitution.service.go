@@ -0,0 +1,132 @@
+// The package 'services' contains the business logic for handling route
+package services
+
+import (
+	"errors"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/repositories"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ErrSubstitutionNotAcceptable is returned when an ingredient is added as its own substitute
+// or when a substitution between the two ingredients already exists in one direction or the other
+var ErrSubstitutionNotAcceptable = errors.New("an ingredient can't be its own substitute or have two substitutions with the same ingredient, substitution not acceptable")
+
+// SubstitutionService is an interface for defining the methods to manage the substitutions between the ingredients
+type SubstitutionService interface {
+	CreateSubstitution(input *dto.CommonIdPathUri, body *dto.SubstitutionReqBody, userId uint) (*dto.SubstitutionResBody, error)
+	GetAllSubstitutions(input *dto.CommonIdPathUri) ([]*dto.SubstitutionResBody, error)
+	UpdateSubstitution(input *dto.SubstitutionPathUri, body *dto.SubstitutionUpdateReqBody, userId uint) (*dto.SubstitutionResBody, error)
+	DeleteSubstitution(input *dto.SubstitutionPathUri, userId uint) error
+}
+
+// substitutionService is an implementation of the SubstitutionService interface
+type substitutionService struct {
+	repo           repositories.SubstitutionRepository
+	ingredientRepo repositories.IngredientRepository
+	userRepo       repositories.UserRepository
+	logger         *zap.Logger
+}
+
+// NewSubstitutionService creates a new SubstitutionService instance, the user repository is used to check the user permissions
+func NewSubstitutionService(repo repositories.SubstitutionRepository, ingredientRepo repositories.IngredientRepository, userRepo repositories.UserRepository) SubstitutionService {
+	return &substitutionService{
+		repo:           repo,
+		ingredientRepo: ingredientRepo,
+		userRepo:       userRepo,
+		logger:         zap.L(),
+	}
+}
+
+// CreateSubstitution adds a substitute to the ingredient, both ingredients must exist and not be substituted by each other yet.
+// An existing reverse substitution can be made bidirectional instead.
+func (s *substitutionService) CreateSubstitution(input *dto.CommonIdPathUri, body *dto.SubstitutionReqBody, userId uint) (*dto.SubstitutionResBody, error) {
+	if body.SubstituteId == input.ID {
+		return nil, ErrSubstitutionNotAcceptable
+	}
+	for _, ingredientId := range []uint{input.ID, body.SubstituteId} {
+		_, err := s.ingredientRepo.GetIngredientById(ingredientId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	totalSubstitutions, err := s.repo.CountSubstitutionsBetween(input.ID, body.SubstituteId)
+	if err != nil {
+		return nil, err
+	}
+	if totalSubstitutions > 0 {
+		return nil, ErrSubstitutionNotAcceptable
+	}
+	substitution, err := s.repo.CreateSubstitution(body.ConvertToModel(input.ID, userId))
+	if err != nil {
+		return nil, err
+	}
+	substitutionRes := &dto.SubstitutionResBody{}
+	substitutionRes.ConvertFromModel(substitution, input.ID)
+	return substitutionRes, nil
+}
+
+// GetAllSubstitutions returns the substitutes of the ingredient, including the ingredients it can replace through a bidirectional substitution
+func (s *substitutionService) GetAllSubstitutions(input *dto.CommonIdPathUri) ([]*dto.SubstitutionResBody, error) {
+	_, err := s.ingredientRepo.GetIngredientById(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	substitutions, err := s.repo.GetAllSubstitutions(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	substitutionsRes := make([]*dto.SubstitutionResBody, len(substitutions))
+	for i, v := range substitutions {
+		res := &dto.SubstitutionResBody{}
+		res.ConvertFromModel(v, input.ID)
+		substitutionsRes[i] = res
+	}
+	return substitutionsRes, nil
+}
+
+// UpdateSubstitution replaces the ratio, the note and the direction of the substitution,
+// only the user who added it or an administrator can update it
+func (s *substitutionService) UpdateSubstitution(input *dto.SubstitutionPathUri, body *dto.SubstitutionUpdateReqBody, userId uint) (*dto.SubstitutionResBody, error) {
+	substitution, err := s.getAuthorizedSubstitution(input, userId)
+	if err != nil {
+		return nil, err
+	}
+	body.ApplyToModel(substitution, input.ID)
+	substitution, err = s.repo.UpdateSubstitution(substitution)
+	if err != nil {
+		return nil, err
+	}
+	substitutionRes := &dto.SubstitutionResBody{}
+	substitutionRes.ConvertFromModel(substitution, input.ID)
+	return substitutionRes, nil
+}
+
+// DeleteSubstitution removes the substitution, only the user who added it or an administrator can remove it
+func (s *substitutionService) DeleteSubstitution(input *dto.SubstitutionPathUri, userId uint) error {
+	substitution, err := s.getAuthorizedSubstitution(input, userId)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteSubstitutionById(substitution.ID)
+}
+
+// getAuthorizedSubstitution returns the substitution of the ingredient when the user is allowed to modify it,
+// the substitution isn't found when it can't replace the ingredient, its substitute only sees it when it's bidirectional
+func (s *substitutionService) getAuthorizedSubstitution(input *dto.SubstitutionPathUri, userId uint) (*models.IngredientSubstitution, error) {
+	substitution, err := s.repo.GetSubstitutionById(input.SubstitutionID)
+	if err != nil {
+		return nil, err
+	}
+	if substitution.IngredientID != input.ID && (substitution.SubstituteID != input.ID || !substitution.Bidirectional) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	err = checkAuthorOrAdmin(s.userRepo, substitution.CreatorID, userId)
+	if err != nil {
+		return nil, err
+	}
+	return substitution, nil
+}
@@ -0,0 +1,158 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type mockSubstitutionRepository struct{}
+
+func (m *mockSubstitutionRepository) CreateSubstitution(substitution *models.IngredientSubstitution) (*models.IngredientSubstitution, error) {
+	substitution.ID = 3
+	substitution.Substitute = &models.Ingredient{Model: gorm.Model{ID: substitution.SubstituteID}, Name: "test"}
+	return substitution, nil
+}
+
+func (m *mockSubstitutionRepository) GetAllSubstitutions(ingredientId uint) ([]*models.IngredientSubstitution, error) {
+	caerphilly, _ := m.GetSubstitutionById(1)
+	stout, _ := m.GetSubstitutionById(2)
+	return []*models.IngredientSubstitution{caerphilly, stout}, nil
+}
+
+func (m *mockSubstitutionRepository) GetSubstitutionById(substitutionId uint) (*models.IngredientSubstitution, error) {
+	switch substitutionId {
+	case 1:
+		return &models.IngredientSubstitution{ID: 1, IngredientID: 1, SubstituteID: 6, Ratio: 1, CreatorID: 1,
+			Substitute: &models.Ingredient{Model: gorm.Model{ID: 6}, Name: "caerphilly"}}, nil
+	case 2:
+		return &models.IngredientSubstitution{ID: 2, IngredientID: 7, SubstituteID: 1, Ratio: 0.5, Bidirectional: true, CreatorID: 2,
+			Ingredient: &models.Ingredient{Model: gorm.Model{ID: 7}, Name: "stout"}}, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockSubstitutionRepository) CountSubstitutionsBetween(ingredientId, otherId uint) (int64, error) {
+	var totalSubstitutions int64
+	substitutions, _ := m.GetAllSubstitutions(ingredientId)
+	for _, v := range substitutions {
+		if (v.IngredientID == ingredientId && v.SubstituteID == otherId) || (v.IngredientID == otherId && v.SubstituteID == ingredientId) {
+			totalSubstitutions++
+		}
+	}
+	return totalSubstitutions, nil
+}
+
+func (m *mockSubstitutionRepository) UpdateSubstitution(substitution *models.IngredientSubstitution) (*models.IngredientSubstitution, error) {
+	return substitution, nil
+}
+
+func (m *mockSubstitutionRepository) DeleteSubstitutionById(substitutionId uint) error {
+	return nil
+}
+
+// mockSubstituteIngredientRepository also knows the ingredients substituting the ingredient 1
+type mockSubstituteIngredientRepository struct {
+	mockIngredientRepository
+}
+
+func (m *mockSubstituteIngredientRepository) GetIngredientById(ingredientId uint) (*models.Ingredient, error) {
+	switch ingredientId {
+	case 6:
+		return &models.Ingredient{Model: gorm.Model{ID: 6}, Name: "caerphilly"}, nil
+	case 7:
+		return &models.Ingredient{Model: gorm.Model{ID: 7}, Name: "stout"}, nil
+	case 8:
+		return &models.Ingredient{Model: gorm.Model{ID: 8}, Name: "lancashire"}, nil
+	}
+	return m.mockIngredientRepository.GetIngredientById(ingredientId)
+}
+
+func TestCreateSubstitution(t *testing.T) {
+	substitutionService := services.NewSubstitutionService(&mockSubstitutionRepository{}, &mockSubstituteIngredientRepository{}, &mockUserRepository{})
+	// test happy path: the substitution is added by the user
+	body := &dto.SubstitutionReqBody{SubstituteId: 8, SubstitutionUpdateReqBody: dto.SubstitutionUpdateReqBody{Ratio: 1.5, Note: "a crumblier cheese"}}
+	substitutionRes, err := substitutionService.CreateSubstitution(&dto.CommonIdPathUri{ID: 1}, body, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), substitutionRes.ID)
+	assert.Equal(t, uint(1), substitutionRes.IngredientId)
+	assert.Equal(t, uint(8), substitutionRes.Substitute.ID)
+	assert.Equal(t, 1.5, substitutionRes.Ratio)
+	assert.Equal(t, "a crumblier cheese", substitutionRes.Note)
+	assert.False(t, substitutionRes.Bidirectional)
+	assert.Equal(t, uint(2), substitutionRes.CreatorId)
+	// test error: the substitute doesn't exist
+	body = &dto.SubstitutionReqBody{SubstituteId: 2, SubstitutionUpdateReqBody: dto.SubstitutionUpdateReqBody{Ratio: 1.5}}
+	substitutionRes, err = substitutionService.CreateSubstitution(&dto.CommonIdPathUri{ID: 1}, body, 1)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, substitutionRes)
+	// test error: the ingredient replaces itself
+	body = &dto.SubstitutionReqBody{SubstituteId: 1}
+	substitutionRes, err = substitutionService.CreateSubstitution(&dto.CommonIdPathUri{ID: 1}, body, 1)
+	assert.ErrorIs(t, err, services.ErrSubstitutionNotAcceptable)
+	assert.Nil(t, substitutionRes)
+	// test error: the substitution already exists
+	body = &dto.SubstitutionReqBody{SubstituteId: 6}
+	substitutionRes, err = substitutionService.CreateSubstitution(&dto.CommonIdPathUri{ID: 1}, body, 1)
+	assert.ErrorIs(t, err, services.ErrSubstitutionNotAcceptable)
+	assert.Nil(t, substitutionRes)
+	// test error: the reverse substitution already exists
+	body = &dto.SubstitutionReqBody{SubstituteId: 7}
+	substitutionRes, err = substitutionService.CreateSubstitution(&dto.CommonIdPathUri{ID: 1}, body, 1)
+	assert.ErrorIs(t, err, services.ErrSubstitutionNotAcceptable)
+	assert.Nil(t, substitutionRes)
+}
+
+func TestGetAllSubstitutions(t *testing.T) {
+	substitutionService := services.NewSubstitutionService(&mockSubstitutionRepository{}, &mockSubstituteIngredientRepository{}, &mockUserRepository{})
+	// test happy path: the bidirectional substitution is seen from the ingredient
+	substitutionsRes, err := substitutionService.GetAllSubstitutions(&dto.CommonIdPathUri{ID: 1})
+	assert.NoError(t, err)
+	assert.Len(t, substitutionsRes, 2)
+	assert.Equal(t, &dto.IngredientLinkResBody{ID: 6, Name: "caerphilly"}, substitutionsRes[0].Substitute)
+	assert.Equal(t, &dto.IngredientLinkResBody{ID: 7, Name: "stout"}, substitutionsRes[1].Substitute)
+	assert.Equal(t, 2.0, substitutionsRes[1].Ratio)
+	// test error: ingredient not found
+	substitutionsRes, err = substitutionService.GetAllSubstitutions(&dto.CommonIdPathUri{ID: 2})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, substitutionsRes)
+}
+
+func TestUpdateSubstitution(t *testing.T) {
+	substitutionService := services.NewSubstitutionService(&mockSubstitutionRepository{}, &mockSubstituteIngredientRepository{}, &mockUserRepository{})
+	// test happy path: an administrator updates the substitution from the substitute side, the ratio is stored inverted
+	body := &dto.SubstitutionUpdateReqBody{Ratio: 4, Note: "use a dark ale", Bidirectional: true}
+	substitutionRes, err := substitutionService.UpdateSubstitution(&dto.SubstitutionPathUri{ID: 1, SubstitutionID: 2}, body, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 4.0, substitutionRes.Ratio)
+	assert.Equal(t, "use a dark ale", substitutionRes.Note)
+	// test error: the user didn't add the substitution
+	substitutionRes, err = substitutionService.UpdateSubstitution(&dto.SubstitutionPathUri{ID: 1, SubstitutionID: 2}, body, 1)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	assert.Nil(t, substitutionRes)
+	// test error: the substitution doesn't involve the ingredient
+	substitutionRes, err = substitutionService.UpdateSubstitution(&dto.SubstitutionPathUri{ID: 3, SubstitutionID: 1}, body, 1)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, substitutionRes)
+	// test error: the ingredient is the substitute of a substitution which isn't bidirectional
+	substitutionRes, err = substitutionService.UpdateSubstitution(&dto.SubstitutionPathUri{ID: 6, SubstitutionID: 1}, body, 1)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, substitutionRes)
+}
+
+func TestDeleteSubstitution(t *testing.T) {
+	substitutionService := services.NewSubstitutionService(&mockSubstitutionRepository{}, &mockSubstituteIngredientRepository{}, &mockUserRepository{})
+	// test happy path
+	err := substitutionService.DeleteSubstitution(&dto.SubstitutionPathUri{ID: 1, SubstitutionID: 1}, 1)
+	assert.NoError(t, err)
+	// test error: the user didn't add the substitution
+	err = substitutionService.DeleteSubstitution(&dto.SubstitutionPathUri{ID: 1, SubstitutionID: 2}, 1)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	// test error: substitution not found
+	err = substitutionService.DeleteSubstitution(&dto.SubstitutionPathUri{ID: 1, SubstitutionID: 4}, 1)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
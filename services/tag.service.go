@@ -0,0 +1,175 @@
+// The package 'services' contains the business logic for handling route
+package services
+
+import (
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/media"
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/repositories"
+	"go.uber.org/zap"
+)
+
+// TagService is an interface for defining the methods to manage the tags and to classify the recipes with them
+type TagService interface {
+	CreateTag(body *dto.TagReqBody, userId uint) (*dto.TagResBody, error)
+	GetAllTags(query *dto.TagQuery) (*dto.CommonPageRespBody, error)
+	GetTagById(input *dto.CommonIdPathUri) (*dto.TagResBody, error)
+	UpdateTag(input *dto.CommonIdPathUri, body *dto.TagReqBody, userId uint) (*dto.TagResBody, error)
+	DeleteTagById(input *dto.CommonIdPathUri, userId uint) error
+	AddTagToRecipe(input *dto.RecipeTagPathUri, userId uint) (*dto.RecipeResBody, error)
+	DeleteTagFromRecipe(input *dto.RecipeTagPathUri, userId uint) error
+}
+
+// tagService is an implementation of the TagService interface
+type tagService struct {
+	repo       repositories.TagRepository
+	recipeRepo repositories.RecipeRepository
+	userRepo   repositories.UserRepository
+	storage    media.Storage
+	logger     *zap.Logger
+}
+
+// NewTagService creates a new TagService instance, the recipe and the user repositories are used to check the user permissions
+func NewTagService(repo repositories.TagRepository, recipeRepo repositories.RecipeRepository, userRepo repositories.UserRepository, storage media.Storage) TagService {
+	return &tagService{
+		repo:       repo,
+		recipeRepo: recipeRepo,
+		userRepo:   userRepo,
+		storage:    storage,
+		logger:     zap.L(),
+	}
+}
+
+// CreateTag creates a new tag, only an administrator can create a tag in a curated category
+func (s *tagService) CreateTag(body *dto.TagReqBody, userId uint) (*dto.TagResBody, error) {
+	tag := body.ConvertToModel(userId)
+	if models.IsCuratedTagCategory(tag.Category) {
+		err := checkAdmin(s.userRepo, userId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	tag, err := s.repo.CreateTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	tagRes := &dto.TagResBody{}
+	tagRes.ConvertFromModel(tag)
+	return tagRes, nil
+}
+
+// GetAllTags returns a page of the tags with the number of recipes using them, the most used first
+func (s *tagService) GetAllTags(query *dto.TagQuery) (*dto.CommonPageRespBody, error) {
+	tags, totalTags, err := s.repo.GetAllTags(query.Category, query.PageSize, query.PageNumber)
+	if err != nil {
+		return nil, err
+	}
+	tagsRes := make([]interface{}, len(tags))
+	for i, v := range tags {
+		res := dto.TagUsageResBody{UsageCount: v.UsageCount}
+		res.ConvertFromModel(v.Tag)
+		tagsRes[i] = res
+	}
+	return newPageRespBody(&query.CommonQueryPage, totalTags, tagsRes), nil
+}
+
+// GetTagById returns a tag by ID
+func (s *tagService) GetTagById(input *dto.CommonIdPathUri) (*dto.TagResBody, error) {
+	tag, err := s.repo.GetTagById(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	tagRes := &dto.TagResBody{}
+	tagRes.ConvertFromModel(tag)
+	return tagRes, nil
+}
+
+// UpdateTag renames the tag or moves it to another category, the user must be allowed to manage the tag in both categories
+func (s *tagService) UpdateTag(input *dto.CommonIdPathUri, body *dto.TagReqBody, userId uint) (*dto.TagResBody, error) {
+	tag, err := s.getAuthorizedTag(input.ID, userId)
+	if err != nil {
+		return nil, err
+	}
+	body.ApplyToModel(tag)
+	if models.IsCuratedTagCategory(tag.Category) {
+		err = checkAdmin(s.userRepo, userId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	tag, err = s.repo.UpdateTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	tagRes := &dto.TagResBody{}
+	tagRes.ConvertFromModel(tag)
+	return tagRes, nil
+}
+
+// DeleteTagById deletes the tag and removes it from the recipes
+func (s *tagService) DeleteTagById(input *dto.CommonIdPathUri, userId uint) error {
+	_, err := s.getAuthorizedTag(input.ID, userId)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteTagById(input.ID)
+}
+
+// getAuthorizedTag returns the tag specified by ID when the user is allowed to manage it:
+// the free-form tags are managed by their creator and the administrators, the curated ones only by the administrators
+func (s *tagService) getAuthorizedTag(tagId uint, userId uint) (*models.Tag, error) {
+	tag, err := s.repo.GetTagById(tagId)
+	if err != nil {
+		return nil, err
+	}
+	if models.IsCuratedTagCategory(tag.Category) {
+		err = checkAdmin(s.userRepo, userId)
+	} else {
+		err = checkAuthorOrAdmin(s.userRepo, tag.CreatorID, userId)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// AddTagToRecipe classifies the recipe with the tag, only the author of the recipe or an administrator can tag it
+func (s *tagService) AddTagToRecipe(input *dto.RecipeTagPathUri, userId uint) (*dto.RecipeResBody, error) {
+	recipe, err := s.recipeRepo.GetRecipeById(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	err = checkAuthorOrAdmin(s.userRepo, uint(recipe.AuthorID), userId)
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.repo.GetTagById(input.TagID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.AddTagToRecipe(input.ID, input.TagID)
+	if err != nil {
+		return nil, err
+	}
+	recipe, err = s.recipeRepo.GetRecipeById(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	recipeRes := &dto.RecipeResBody{}
+	recipeRes.ConvertFromModel(recipe)
+	completeRecipeRes(recipeRes, recipe, s.storage)
+	return recipeRes, nil
+}
+
+// DeleteTagFromRecipe removes the tag from the recipe, only the author of the recipe or an administrator can untag it
+func (s *tagService) DeleteTagFromRecipe(input *dto.RecipeTagPathUri, userId uint) error {
+	recipe, err := s.recipeRepo.GetRecipeById(input.ID)
+	if err != nil {
+		return err
+	}
+	err = checkAuthorOrAdmin(s.userRepo, uint(recipe.AuthorID), userId)
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteTagFromRecipe(input.ID, input.TagID)
+}
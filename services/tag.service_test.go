@@ -0,0 +1,127 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/repositories"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type mockTagRepository struct{}
+
+func (m *mockTagRepository) CreateTag(tag *models.Tag) (*models.Tag, error) {
+	tag.ID = 3
+	return tag, nil
+}
+
+func (m *mockTagRepository) GetAllTags(category string, pageSize, pageNumber int) ([]*repositories.TagUsage, int64, error) {
+	course, _ := m.GetTagById(2)
+	cheesy, _ := m.GetTagById(1)
+	return []*repositories.TagUsage{{Tag: course, UsageCount: 12}, {Tag: cheesy, UsageCount: 3}}, 2, nil
+}
+
+func (m *mockTagRepository) GetTagById(tagId uint) (*models.Tag, error) {
+	switch tagId {
+	case 1:
+		return &models.Tag{Model: gorm.Model{ID: 1}, Name: "cheesy", Category: models.TagCategoryFree, CreatorID: 1}, nil
+	case 2:
+		return &models.Tag{Model: gorm.Model{ID: 2}, Name: "starter", Category: models.TagCategoryCourse, CreatorID: 3}, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockTagRepository) UpdateTag(tag *models.Tag) (*models.Tag, error) {
+	return tag, nil
+}
+
+func (m *mockTagRepository) DeleteTagById(tagId uint) error {
+	return nil
+}
+
+func (m *mockTagRepository) AddTagToRecipe(recipeId, tagId uint) error {
+	return nil
+}
+
+func (m *mockTagRepository) DeleteTagFromRecipe(recipeId, tagId uint) error {
+	return nil
+}
+
+func TestCreateTag(t *testing.T) {
+	tagService := services.NewTagService(&mockTagRepository{}, &mockRecipeRepository{}, &mockUserRepository{}, &mockStorage{})
+	// the tags without category are free-form tags stored in lower case
+	tagRes, err := tagService.CreateTag(&dto.TagReqBody{Name: " Comfort Food "}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "comfort food", tagRes.Name)
+	assert.Equal(t, models.TagCategoryFree, tagRes.Category)
+	assert.Equal(t, uint(1), tagRes.CreatorId)
+	// an administrator can create a curated tag
+	tagRes, err = tagService.CreateTag(&dto.TagReqBody{Name: "Welsh", Category: "cuisine"}, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "cuisine", tagRes.Category)
+	// test error: a user can't create a curated tag
+	tagRes, err = tagService.CreateTag(&dto.TagReqBody{Name: "Welsh", Category: "cuisine"}, 1)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	assert.Nil(t, tagRes)
+}
+
+func TestGetAllTags(t *testing.T) {
+	tagService := services.NewTagService(&mockTagRepository{}, &mockRecipeRepository{}, &mockUserRepository{}, &mockStorage{})
+	pageRes, err := tagService.GetAllTags(&dto.TagQuery{CommonQueryPage: dto.CommonQueryPage{PageSize: 10}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, pageRes.TotalNbResult)
+	assert.Len(t, pageRes.Items, 2)
+	first := pageRes.Items[0].(dto.TagUsageResBody)
+	assert.Equal(t, "starter", first.Name)
+	assert.Equal(t, int64(12), first.UsageCount)
+}
+
+func TestUpdateTag(t *testing.T) {
+	tagService := services.NewTagService(&mockTagRepository{}, &mockRecipeRepository{}, &mockUserRepository{}, &mockStorage{})
+	body := &dto.TagReqBody{Name: "Extra cheesy"}
+	tagRes, err := tagService.UpdateTag(&dto.CommonIdPathUri{ID: 1}, body, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "extra cheesy", tagRes.Name)
+	// test error: another user renames the free-form tag
+	_, err = tagService.UpdateTag(&dto.CommonIdPathUri{ID: 1}, body, 2)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	// test error: the creator moves the tag to a curated category
+	_, err = tagService.UpdateTag(&dto.CommonIdPathUri{ID: 1}, &dto.TagReqBody{Name: "cheesy", Category: "occasion"}, 1)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	// test error: tag not found
+	_, err = tagService.UpdateTag(&dto.CommonIdPathUri{ID: 4}, body, 1)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestDeleteTagById(t *testing.T) {
+	tagService := services.NewTagService(&mockTagRepository{}, &mockRecipeRepository{}, &mockUserRepository{}, &mockStorage{})
+	assert.NoError(t, tagService.DeleteTagById(&dto.CommonIdPathUri{ID: 1}, 1))
+	assert.NoError(t, tagService.DeleteTagById(&dto.CommonIdPathUri{ID: 2}, 3))
+	// test error: only the administrators manage the curated tags
+	assert.ErrorIs(t, tagService.DeleteTagById(&dto.CommonIdPathUri{ID: 2}, 1), services.ErrForbidden)
+}
+
+func TestAddTagToRecipe(t *testing.T) {
+	tagService := services.NewTagService(&mockTagRepository{}, &mockRecipeRepository{}, &mockUserRepository{}, &mockStorage{})
+	recipeRes, err := tagService.AddTagToRecipe(&dto.RecipeTagPathUri{ID: 1, TagID: 2}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), recipeRes.ID)
+	// test error: the user isn't the author of the recipe
+	_, err = tagService.AddTagToRecipe(&dto.RecipeTagPathUri{ID: 1, TagID: 2}, 2)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	// test error: tag not found
+	_, err = tagService.AddTagToRecipe(&dto.RecipeTagPathUri{ID: 1, TagID: 4}, 1)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestDeleteTagFromRecipe(t *testing.T) {
+	tagService := services.NewTagService(&mockTagRepository{}, &mockRecipeRepository{}, &mockUserRepository{}, &mockStorage{})
+	assert.NoError(t, tagService.DeleteTagFromRecipe(&dto.RecipeTagPathUri{ID: 1, TagID: 2}, 3))
+	// test error: the user isn't the author of the recipe
+	assert.ErrorIs(t, tagService.DeleteTagFromRecipe(&dto.RecipeTagPathUri{ID: 1, TagID: 2}, 2), services.ErrForbidden)
+	// test error: recipe not found
+	assert.ErrorIs(t, tagService.DeleteTagFromRecipe(&dto.RecipeTagPathUri{ID: 2, TagID: 2}, 1), gorm.ErrRecordNotFound)
+}
@@ -0,0 +1,59 @@
+// The package 'services' contains the business logic for handling route
+package services
+
+import (
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/media"
+	"github.com/clementb49/welsh_academy/repositories"
+	"go.uber.org/zap"
+)
+
+// The time window of the trending recipes when the query doesn't specify one
+const defaultTrendingWindow = "7d"
+
+// TrendingService is an interface for defining the methods to compute and to get the trending recipes
+type TrendingService interface {
+	RefreshTrendingRecipes() error
+	GetTrendingRecipes(query *dto.TrendingQuery) (*dto.CommonPageRespBody, error)
+}
+
+// trendingService is an implementation of the TrendingService interface
+type trendingService struct {
+	repo    repositories.TrendingRepository
+	storage media.Storage
+	logger  *zap.Logger
+}
+
+// NewTrendingService creates a new TrendingService instance
+func NewTrendingService(repo repositories.TrendingRepository, storage media.Storage) TrendingService {
+	return &trendingService{
+		repo:    repo,
+		storage: storage,
+		logger:  zap.L(),
+	}
+}
+
+// RefreshTrendingRecipes computes the trending scores of the recipes from the favorites and the views, it's run periodically in the background
+func (s *trendingService) RefreshTrendingRecipes() error {
+	return s.repo.RefreshTrendingRecipes()
+}
+
+// GetTrendingRecipes returns a page of the recipes trending over the window of the query, the hottest first
+func (s *trendingService) GetTrendingRecipes(query *dto.TrendingQuery) (*dto.CommonPageRespBody, error) {
+	window := query.Window
+	if window == "" {
+		window = defaultTrendingWindow
+	}
+	results, totalRecipes, err := s.repo.GetTrendingRecipes(window, query.PageSize, query.PageNumber)
+	if err != nil {
+		return nil, err
+	}
+	recipesRes := make([]interface{}, len(results))
+	for i, v := range results {
+		res := dto.TrendingRecipeResBody{Score: v.Score, FavoriteCount: v.FavoriteCount, ViewCount: v.ViewCount}
+		res.ConvertFromModel(v.Recipe)
+		completeRecipeRes(&res.RecipeResBody, v.Recipe, s.storage)
+		recipesRes[i] = res
+	}
+	return newPageRespBody(&query.CommonQueryPage, totalRecipes, recipesRes), nil
+}
@@ -0,0 +1,48 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/clementb49/welsh_academy/dto"
+	"github.com/clementb49/welsh_academy/models"
+	"github.com/clementb49/welsh_academy/repositories"
+	"github.com/clementb49/welsh_academy/services"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type mockTrendingRepository struct{}
+
+func (m *mockTrendingRepository) RefreshTrendingRecipes() error {
+	return nil
+}
+
+func (m *mockTrendingRepository) GetTrendingRecipes(window string, pageSize, pageNumber int) ([]*repositories.TrendingRecipe, int64, error) {
+	if window != "7d" {
+		return []*repositories.TrendingRecipe{}, 0, nil
+	}
+	return []*repositories.TrendingRecipe{{
+		Recipe:        &models.Recipe{Model: gorm.Model{ID: 1}, Title: "welsh rarebit"},
+		Score:         12.5,
+		FavoriteCount: 2,
+		ViewCount:     4,
+	}}, 1, nil
+}
+
+func TestGetTrendingRecipes(t *testing.T) {
+	trendingService := services.NewTrendingService(&mockTrendingRepository{}, &mockStorage{})
+	// test happy path: the recipes trending over the last 7 days are returned by default
+	pageRes, err := trendingService.GetTrendingRecipes(&dto.TrendingQuery{CommonQueryPage: dto.CommonQueryPage{PageSize: 10}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, pageRes.TotalNbResult)
+	recipeRes := pageRes.Items[0].(dto.TrendingRecipeResBody)
+	assert.Equal(t, "welsh rarebit", recipeRes.Title)
+	assert.Equal(t, 12.5, recipeRes.Score)
+	assert.Equal(t, uint(2), recipeRes.FavoriteCount)
+	assert.Equal(t, uint(4), recipeRes.ViewCount)
+	// test happy path: nothing is trending over the last 24 hours
+	pageRes, err = trendingService.GetTrendingRecipes(&dto.TrendingQuery{CommonQueryPage: dto.CommonQueryPage{PageSize: 10}, Window: "24h"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, pageRes.TotalNbResult)
+	assert.Empty(t, pageRes.Items)
+}
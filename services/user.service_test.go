@@ -57,10 +57,34 @@ func (m *mockUserRepository) GetUserById(userId uint) (*models.User, error) {
 			Password: "hashed_password",
 		}, nil
 	}
+	if userId == 3 {
+		return &models.User{
+			Model: gorm.Model{
+				ID:        3,
+				CreatedAt: time.Now(),
+				UpdatedAt: time.Now(),
+			},
+			Email:    "admin@example.com",
+			Password: "hashed_password",
+			IsAdmin:  true,
+		}, nil
+	}
 
 	return nil, gorm.ErrRecordNotFound
 }
 
+func (m *mockUserRepository) GetUserByCalendarToken(token string) (*models.User, error) {
+	if token == calendarToken {
+		return m.GetUserById(1)
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockUserRepository) SetCalendarToken(userId uint, token string) error {
+	_, err := m.GetUserById(userId)
+	return err
+}
+
 func TestCreateUser(t *testing.T) {
 	repo := &mockUserRepository{}
 	userService := services.NewUserService(repo)
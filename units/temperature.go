@@ -0,0 +1,86 @@
+// This file converts the oven temperatures between Celsius, Fahrenheit and the gas marks
+package units
+
+import "math"
+
+// gasMark associates a gas mark with its oven temperature in Celsius
+type gasMark struct {
+	mark    float64
+	celsius float64
+}
+
+// The gas marks of the British ovens with their temperature in Celsius, ordered by temperature
+var gasMarks = []gasMark{
+	{0.25, 110}, {0.5, 120}, {1, 140}, {2, 150}, {3, 170}, {4, 180}, {5, 190}, {6, 200}, {7, 220}, {8, 230}, {9, 240}, {10, 260},
+}
+
+// ConvertTemperature converts an oven temperature between Celsius ("C"), Fahrenheit ("F") and gas mark ("gas").
+// The gas mark is the nearest mark of the temperature.
+func ConvertTemperature(value float64, from string, to string) (float64, error) {
+	fromUnit, ok := Lookup(from)
+	if !ok {
+		return 0, ErrUnknownUnit
+	}
+	toUnit, ok := Lookup(to)
+	if !ok {
+		return 0, ErrUnknownUnit
+	}
+	if fromUnit.Kind != Temperature || toUnit.Kind != Temperature {
+		return 0, ErrIncompatibleUnits
+	}
+	var celsius float64
+	switch fromUnit.Symbol {
+	case "C":
+		celsius = value
+	case "F":
+		celsius = (value - 32) * 5 / 9
+	case "gas":
+		celsius = gasMarkToCelsius(value)
+	}
+	switch toUnit.Symbol {
+	case "F":
+		return celsius*9/5 + 32, nil
+	case "gas":
+		return celsiusToGasMark(celsius), nil
+	default:
+		return celsius, nil
+	}
+}
+
+// gasMarkToCelsius returns the temperature of the gas mark, the marks between two known marks are interpolated
+func gasMarkToCelsius(mark float64) float64 {
+	if mark <= gasMarks[0].mark {
+		return gasMarks[0].celsius
+	}
+	for i := 1; i < len(gasMarks); i++ {
+		if mark <= gasMarks[i].mark {
+			previous := gasMarks[i-1]
+			ratio := (mark - previous.mark) / (gasMarks[i].mark - previous.mark)
+			return previous.celsius + ratio*(gasMarks[i].celsius-previous.celsius)
+		}
+	}
+	return gasMarks[len(gasMarks)-1].celsius
+}
+
+// celsiusToGasMark returns the gas mark whose temperature is the nearest of the given temperature
+func celsiusToGasMark(celsius float64) float64 {
+	nearest := gasMarks[0]
+	for _, gm := range gasMarks[1:] {
+		if math.Abs(gm.celsius-celsius) < math.Abs(nearest.celsius-celsius) {
+			nearest = gm
+		}
+	}
+	return nearest.mark
+}
+
+// RoundTemperature rounds an oven temperature to the steps of the oven dials, 5 degrees Celsius and 25 degrees Fahrenheit
+func RoundTemperature(value float64, unit string) float64 {
+	u, ok := Lookup(unit)
+	if !ok || u.Kind != Temperature {
+		return value
+	}
+	if u.Symbol == "gas" {
+		return value
+	}
+	return roundTo(value, u.Precision)
+}
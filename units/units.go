@@ -0,0 +1,238 @@
+// Package units knows the measurement units used by the recipes and converts the quantities between them.
+// The quantities are converted through the base unit of their kind: the gram for the mass, the millilitre for the volume
+// and the piece for the count. The mass and the volume are converted into each other with the ingredient density.
+package units
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+// Kind is the physical quantity measured by a unit
+type Kind int
+
+// The kinds of quantity known by the package
+const (
+	Count Kind = iota
+	Mass
+	Volume
+	Temperature
+)
+
+// System is the measurement system a unit belongs to
+type System string
+
+// The measurement systems, the neutral units (spoons, pieces) are used by every system
+const (
+	Neutral  System = "neutral"
+	Metric   System = "metric"
+	Imperial System = "imperial"
+	US       System = "us"
+)
+
+// Unit describes a measurement unit
+type Unit struct {
+	Symbol    string  // the canonical symbol of the unit
+	Kind      Kind    // the kind of quantity measured by the unit
+	System    System  // the measurement system of the unit
+	Factor    float64 // the value of one unit in the base unit of its kind
+	Precision float64 // the precision used to round the quantities expressed in this unit
+}
+
+// ErrUnknownUnit is returned when the unit is not known by the package
+var ErrUnknownUnit = errors.New("unknown unit")
+
+// ErrIncompatibleUnits is returned when a quantity can't be converted from a unit to another one
+var ErrIncompatibleUnits = errors.New("incompatible units")
+
+// The known units indexed by their canonical symbol.
+// The cup alone is the 250 ml metric cup used in the UK recipes, the US customary cup and the old imperial cup have their own symbol.
+var knownUnits = map[string]*Unit{
+	"piece":    {Symbol: "piece", Kind: Count, System: Neutral, Factor: 1, Precision: 1},
+	"slice":    {Symbol: "slice", Kind: Count, System: Neutral, Factor: 1, Precision: 1},
+	"clove":    {Symbol: "clove", Kind: Count, System: Neutral, Factor: 1, Precision: 1},
+	"pinch":    {Symbol: "pinch", Kind: Count, System: Neutral, Factor: 1, Precision: 1},
+	"mg":       {Symbol: "mg", Kind: Mass, System: Metric, Factor: 0.001, Precision: 1},
+	"g":        {Symbol: "g", Kind: Mass, System: Metric, Factor: 1, Precision: 5},
+	"kg":       {Symbol: "kg", Kind: Mass, System: Metric, Factor: 1000, Precision: 0.05},
+	"oz":       {Symbol: "oz", Kind: Mass, System: Imperial, Factor: 28.349523125, Precision: 0.25},
+	"lb":       {Symbol: "lb", Kind: Mass, System: Imperial, Factor: 453.59237, Precision: 0.05},
+	"ml":       {Symbol: "ml", Kind: Volume, System: Metric, Factor: 1, Precision: 5},
+	"cl":       {Symbol: "cl", Kind: Volume, System: Metric, Factor: 10, Precision: 0.5},
+	"dl":       {Symbol: "dl", Kind: Volume, System: Metric, Factor: 100, Precision: 0.25},
+	"l":        {Symbol: "l", Kind: Volume, System: Metric, Factor: 1000, Precision: 0.05},
+	"tsp":      {Symbol: "tsp", Kind: Volume, System: Neutral, Factor: 5, Precision: 0.25},
+	"tbsp":     {Symbol: "tbsp", Kind: Volume, System: Neutral, Factor: 15, Precision: 0.25},
+	"cup":      {Symbol: "cup", Kind: Volume, System: Metric, Factor: 250, Precision: 0.25},
+	"uk_cup":   {Symbol: "uk_cup", Kind: Volume, System: Imperial, Factor: 284.130625, Precision: 0.25},
+	"us_cup":   {Symbol: "us_cup", Kind: Volume, System: US, Factor: 236.5882365, Precision: 0.25},
+	"fl_oz":    {Symbol: "fl_oz", Kind: Volume, System: Imperial, Factor: 28.4130625, Precision: 0.5},
+	"us_fl_oz": {Symbol: "us_fl_oz", Kind: Volume, System: US, Factor: 29.5735295625, Precision: 0.5},
+	"pint":     {Symbol: "pint", Kind: Volume, System: Imperial, Factor: 568.26125, Precision: 0.25},
+	"us_pint":  {Symbol: "us_pint", Kind: Volume, System: US, Factor: 473.176473, Precision: 0.25},
+	"C":        {Symbol: "C", Kind: Temperature, System: Metric, Factor: 1, Precision: 5},
+	"F":        {Symbol: "F", Kind: Temperature, System: Imperial, Factor: 1, Precision: 25},
+	"gas":      {Symbol: "gas", Kind: Temperature, System: Imperial, Factor: 1, Precision: 1},
+}
+
+// The alternative spellings of the units, the lookup is case insensitive except for the temperature symbols.
+// A quantity without unit is a number of pieces.
+var aliases = map[string]string{
+	"": "piece", "pieces": "piece", "pc": "piece", "pcs": "piece", "whole": "piece", "egg": "piece", "eggs": "piece",
+	"slices": "slice", "cloves": "clove", "pinches": "pinch",
+	"milligram": "mg", "milligrams": "mg",
+	"gram": "g", "grams": "g", "gr": "g",
+	"kilogram": "kg", "kilograms": "kg", "kilo": "kg", "kilos": "kg",
+	"ounce": "oz", "ounces": "oz",
+	"pound": "lb", "pounds": "lb", "lbs": "lb",
+	"millilitre": "ml", "millilitres": "ml", "milliliter": "ml", "milliliters": "ml",
+	"centilitre": "cl", "centilitres": "cl", "centiliter": "cl", "centiliters": "cl",
+	"decilitre": "dl", "decilitres": "dl", "deciliter": "dl", "deciliters": "dl",
+	"litre": "l", "litres": "l", "liter": "l", "liters": "l",
+	"teaspoon": "tsp", "teaspoons": "tsp", "tsps": "tsp",
+	"tablespoon": "tbsp", "tablespoons": "tbsp", "tbsps": "tbsp", "tbs": "tbsp",
+	"cups": "cup", "metric_cup": "cup", "uk_cups": "uk_cup", "imperial_cup": "uk_cup", "us_cups": "us_cup",
+	"fl oz": "fl_oz", "floz": "fl_oz", "fluid ounce": "fl_oz", "fluid ounces": "fl_oz", "us_fl oz": "us_fl_oz",
+	"pints": "pint", "pt": "pint", "us_pints": "us_pint",
+	"°c": "C", "celsius": "C",
+	"°f": "F", "fahrenheit": "F",
+	"gas mark": "gas", "gas_mark": "gas", "gasmark": "gas", "mark": "gas",
+}
+
+// Lookup returns the unit matching the symbol or one of its alternative spellings
+func Lookup(symbol string) (*Unit, bool) {
+	symbol = strings.TrimSpace(symbol)
+	if unit, ok := knownUnits[symbol]; ok {
+		return unit, true
+	}
+	lower := strings.ToLower(strings.TrimSuffix(symbol, "."))
+	if unit, ok := knownUnits[lower]; ok && unit.Kind != Temperature {
+		return unit, true
+	}
+	if canonical, ok := aliases[lower]; ok {
+		return knownUnits[canonical], true
+	}
+	return nil, false
+}
+
+// Convert converts the quantity from a unit to another one. The mass and the volume are converted into each other
+// using the density of the ingredient in g/ml, a density of 0 means it's unknown.
+func Convert(quantity float64, from string, to string, density float64) (float64, error) {
+	fromUnit, ok := Lookup(from)
+	if !ok {
+		return 0, ErrUnknownUnit
+	}
+	toUnit, ok := Lookup(to)
+	if !ok {
+		return 0, ErrUnknownUnit
+	}
+	if fromUnit.Kind == Temperature || toUnit.Kind == Temperature {
+		return ConvertTemperature(quantity, fromUnit.Symbol, toUnit.Symbol)
+	}
+	base := quantity * fromUnit.Factor
+	switch {
+	case fromUnit.Kind == Count && fromUnit != toUnit:
+		// the slices, the cloves and the pieces can't be compared
+		return 0, ErrIncompatibleUnits
+	case fromUnit.Kind == toUnit.Kind:
+	case fromUnit.Kind == Volume && toUnit.Kind == Mass && density > 0:
+		base = base * density
+	case fromUnit.Kind == Mass && toUnit.Kind == Volume && density > 0:
+		base = base / density
+	default:
+		return 0, ErrIncompatibleUnits
+	}
+	return base / toUnit.Factor, nil
+}
+
+// ToBase converts the quantity to the base unit of its kind, it returns the converted quantity with the base unit symbol
+func ToBase(quantity float64, unit string) (float64, string, error) {
+	fromUnit, ok := Lookup(unit)
+	if !ok {
+		return 0, "", ErrUnknownUnit
+	}
+	switch fromUnit.Kind {
+	case Mass:
+		return quantity * fromUnit.Factor, "g", nil
+	case Volume:
+		return quantity * fromUnit.Factor, "ml", nil
+	case Count:
+		return quantity, fromUnit.Symbol, nil
+	default:
+		return 0, "", ErrIncompatibleUnits
+	}
+}
+
+// ToSystem expresses the quantity in the most readable unit of the measurement system.
+// The metric system prefers the weight for the cup measures when the density is known, the neutral units are kept as is.
+// The unknown units and the count are returned unchanged.
+func ToSystem(quantity float64, unit string, system System, density float64) (float64, string) {
+	fromUnit, ok := Lookup(unit)
+	if !ok || fromUnit.Kind == Count || fromUnit.Kind == Temperature {
+		return quantity, unit
+	}
+	kind := fromUnit.Kind
+	base := quantity * fromUnit.Factor
+	if system == Metric && isCup(fromUnit) && density > 0 {
+		kind = Mass
+		base = base * density
+	} else if fromUnit.System == Neutral || fromUnit.System == system {
+		return quantity, fromUnit.Symbol
+	}
+	var candidates []string
+	switch {
+	case system == Metric && kind == Mass:
+		candidates = []string{"g", "kg"}
+	case system == Metric && kind == Volume:
+		candidates = []string{"ml", "l"}
+	case system == Imperial && kind == Mass:
+		candidates = []string{"oz", "lb"}
+	case system == Imperial && kind == Volume:
+		candidates = []string{"fl_oz", "pint"}
+	case system == US && kind == Mass:
+		candidates = []string{"oz", "lb"}
+	case system == US && kind == Volume:
+		candidates = []string{"us_fl_oz", "us_cup"}
+	default:
+		return quantity, fromUnit.Symbol
+	}
+	// use the largest unit which keeps the quantity above 1
+	best := knownUnits[candidates[0]]
+	for _, symbol := range candidates[1:] {
+		candidate := knownUnits[symbol]
+		if base/candidate.Factor >= 1 {
+			best = candidate
+		}
+	}
+	return base / best.Factor, best.Symbol
+}
+
+// isCup returns true when the unit is one of the cup measures
+func isCup(unit *Unit) bool {
+	return unit.Symbol == "cup" || unit.Symbol == "uk_cup" || unit.Symbol == "us_cup"
+}
+
+// Round rounds the quantity with a precision which makes sense for the unit, e.g. the eggs to a whole number
+// and the grams to the nearest 5. A used ingredient never disappears because of the rounding
+// so the result is at least the precision, the small quantities of the units rounded to 5 are rounded to the unit.
+func Round(quantity float64, unit string) float64 {
+	if quantity <= 0 {
+		return quantity
+	}
+	precision := 0.01
+	if u, ok := Lookup(unit); ok {
+		precision = u.Precision
+	}
+	if precision == 5 && quantity < 20 {
+		precision = 1
+	}
+	return math.Max(roundTo(quantity, precision), precision)
+}
+
+// roundTo rounds the value to the nearest multiple of the precision
+func roundTo(value float64, precision float64) float64 {
+	rounded := math.Round(value/precision) * precision
+	// remove the floating point noise introduced by the decimal precisions
+	return math.Round(rounded*100) / 100
+}
@@ -0,0 +1,112 @@
+package units_test
+
+import (
+	"testing"
+
+	"github.com/clementb49/welsh_academy/units"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookup(t *testing.T) {
+	unit, ok := units.Lookup("Grams")
+	assert.True(t, ok)
+	assert.Equal(t, "g", unit.Symbol)
+	unit, ok = units.Lookup("")
+	assert.True(t, ok)
+	assert.Equal(t, units.Count, unit.Kind)
+	unit, ok = units.Lookup("gas mark")
+	assert.True(t, ok)
+	assert.Equal(t, "gas", unit.Symbol)
+	// test error: the unknown units and the ambiguous temperature symbols
+	_, ok = units.Lookup("handful")
+	assert.False(t, ok)
+	_, ok = units.Lookup("c")
+	assert.False(t, ok)
+}
+
+func TestConvert(t *testing.T) {
+	quantity, err := units.Convert(1.5, "kg", "g", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1500.0, quantity)
+	quantity, err = units.Convert(2, "tbsp", "ml", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 30.0, quantity)
+	// the UK and US cups are different
+	quantity, err = units.Convert(1, "us_cup", "ml", 0)
+	assert.NoError(t, err)
+	assert.InDelta(t, 236.59, quantity, 0.01)
+	quantity, err = units.Convert(1, "uk_cup", "ml", 0)
+	assert.NoError(t, err)
+	assert.InDelta(t, 284.13, quantity, 0.01)
+	// the volume is weighed with the density
+	quantity, err = units.Convert(1, "cup", "g", 0.53)
+	assert.NoError(t, err)
+	assert.InDelta(t, 132.5, quantity, 0.001)
+	// test error: the density is unknown
+	_, err = units.Convert(1, "cup", "g", 0)
+	assert.ErrorIs(t, err, units.ErrIncompatibleUnits)
+	// test error: the count can't be converted
+	_, err = units.Convert(2, "clove", "g", 1)
+	assert.ErrorIs(t, err, units.ErrIncompatibleUnits)
+	// test error: unknown unit
+	_, err = units.Convert(2, "handful", "g", 1)
+	assert.ErrorIs(t, err, units.ErrUnknownUnit)
+}
+
+func TestToSystem(t *testing.T) {
+	quantity, unit := units.ToSystem(500, "g", units.Imperial, 0)
+	assert.InDelta(t, 1.1, quantity, 0.01)
+	assert.Equal(t, "lb", unit)
+	quantity, unit = units.ToSystem(300, "ml", units.Imperial, 0)
+	assert.InDelta(t, 10.56, quantity, 0.01)
+	assert.Equal(t, "fl_oz", unit)
+	quantity, unit = units.ToSystem(2, "lb", units.Metric, 0)
+	assert.InDelta(t, 907.18, quantity, 0.01)
+	assert.Equal(t, "g", unit)
+	// the cups are weighed in the metric system when the density is known
+	quantity, unit = units.ToSystem(2, "us_cup", units.Metric, 0.53)
+	assert.InDelta(t, 250.78, quantity, 0.01)
+	assert.Equal(t, "g", unit)
+	quantity, unit = units.ToSystem(2, "us_cup", units.Metric, 0)
+	assert.InDelta(t, 473.18, quantity, 0.01)
+	assert.Equal(t, "ml", unit)
+	// the spoons, the count and the unknown units are kept
+	quantity, unit = units.ToSystem(1, "tsp", units.Imperial, 0)
+	assert.Equal(t, 1.0, quantity)
+	assert.Equal(t, "tsp", unit)
+	quantity, unit = units.ToSystem(3, "", units.Imperial, 0)
+	assert.Equal(t, 3.0, quantity)
+	assert.Equal(t, "", unit)
+	quantity, unit = units.ToSystem(1, "handful", units.Metric, 0)
+	assert.Equal(t, 1.0, quantity)
+	assert.Equal(t, "handful", unit)
+}
+
+func TestRound(t *testing.T) {
+	assert.Equal(t, 155.0, units.Round(153.4, "g"))
+	assert.Equal(t, 7.0, units.Round(7.4, "g"))
+	assert.Equal(t, 3.0, units.Round(2.6, "eggs"))
+	assert.Equal(t, 1.0, units.Round(0.3, ""))
+	assert.Equal(t, 1.75, units.Round(1.8, "tsp"))
+	assert.Equal(t, 1.23, units.Round(1.234, "handful"))
+}
+
+func TestConvertTemperature(t *testing.T) {
+	value, err := units.ConvertTemperature(180, "C", "F")
+	assert.NoError(t, err)
+	assert.Equal(t, 356.0, value)
+	assert.Equal(t, 350.0, units.RoundTemperature(value, "F"))
+	value, err = units.ConvertTemperature(356, "F", "gas")
+	assert.NoError(t, err)
+	assert.Equal(t, 4.0, value)
+	value, err = units.ConvertTemperature(6, "gas mark", "°C")
+	assert.NoError(t, err)
+	assert.Equal(t, 200.0, value)
+	// the marks between two known marks are interpolated
+	value, err = units.ConvertTemperature(3.5, "gas", "C")
+	assert.NoError(t, err)
+	assert.Equal(t, 175.0, value)
+	// test error: a temperature can't be converted to a mass
+	_, err = units.ConvertTemperature(180, "C", "g")
+	assert.ErrorIs(t, err, units.ErrIncompatibleUnits)
+}